@@ -28,7 +28,13 @@ import (
 	"github.com/abcxyz/pkg/logging"
 	"github.com/abcxyz/tagrep/internal/metricswrap"
 	"github.com/abcxyz/tagrep/internal/version"
+	"github.com/abcxyz/tagrep/pkg/commands/batch"
+	"github.com/abcxyz/tagrep/pkg/commands/edit"
+	"github.com/abcxyz/tagrep/pkg/commands/labels"
 	"github.com/abcxyz/tagrep/pkg/commands/parse"
+	"github.com/abcxyz/tagrep/pkg/commands/report"
+	"github.com/abcxyz/tagrep/pkg/commands/schema"
+	"github.com/abcxyz/tagrep/pkg/commands/serve"
 )
 
 // rootCmd defines the starting command structure.
@@ -37,9 +43,30 @@ var rootCmd = func() cli.Command {
 		Name:    "tagrep",
 		Version: version.HumanVersion,
 		Commands: map[string]cli.CommandFactory{
+			"batch": func() cli.Command {
+				return &batch.BatchCommand{}
+			},
 			"parse": func() cli.Command {
 				return &parse.ParseCommand{}
 			},
+			"report": func() cli.Command {
+				return &report.ReportCommand{}
+			},
+			"schema": func() cli.Command {
+				return &schema.SchemaCommand{}
+			},
+			"serve": func() cli.Command {
+				return &serve.ServeCommand{}
+			},
+			"set": func() cli.Command {
+				return &edit.SetCommand{}
+			},
+			"sync-labels": func() cli.Command {
+				return &labels.SyncLabelsCommand{}
+			},
+			"unset": func() cli.Command {
+				return &edit.UnsetCommand{}
+			},
 		},
 	}
 }
@@ -7,11 +7,13 @@ require (
 	github.com/abcxyz/pkg v1.5.4
 	github.com/google/go-cmp v0.7.0
 	github.com/google/go-github/v53 v53.2.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/posener/complete/v2 v2.1.0
 	github.com/sethvargo/go-githubactions v1.3.0
 	github.com/sethvargo/go-retry v0.3.0
 	gitlab.com/gitlab-org/api/client-go v0.125.0
 	golang.org/x/exp v0.0.0-20250305212735-054e65f0b394
+	golang.org/x/net v0.38.0
 	golang.org/x/oauth2 v0.26.0
 )
 
@@ -27,7 +29,6 @@ require (
 	github.com/posener/script v1.2.0 // indirect
 	github.com/sethvargo/go-envconfig v1.1.1 // indirect
 	golang.org/x/crypto v0.36.0 // indirect
-	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	golang.org/x/time v0.10.0 // indirect
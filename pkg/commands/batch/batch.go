@@ -0,0 +1,191 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batch parses every github issue or pull request, or gitlab issue,
+// matching a search query and streams the tags of each to stdout.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+var _ cli.Command = (*BatchCommand)(nil)
+
+const defaultConcurrency = 4
+
+// BatchCommand searches for items and prints out the tags of each.
+type BatchCommand struct {
+	cli.BaseCommand
+
+	platformConfig platform.Config
+	tagsConfig     tags.Config
+
+	platformClient platform.Platform
+	tagParser      tags.TagParser
+
+	FlagQuery       string
+	FlagConcurrency int
+}
+
+// Result is a single line of the batch output.
+type Result struct {
+	*platform.Item
+
+	Tags map[string]any `json:"tags"`
+
+	// Error is why the tags of the item could not be parsed, e.g. a missing
+	// required tag.
+	Error string `json:"error,omitempty"`
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *BatchCommand) Desc() string {
+	return "Parse tags of all items matching a search query"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *BatchCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options]
+
+	Parse the tags of every item matching a search query and stream the results
+	to stdout as newline delimited JSON, one object per item.
+
+	For GitHub the query is a search query for issues and pull requests:
+
+	tagrep batch -query="repo:owner/repo is:issue is:open JUSTIFICATION in:body"
+
+	For GitLab the query is a URL encoded filter for project issues:
+
+	tagrep batch -query="state=opened&search=JUSTIFICATION"
+`
+}
+
+func (c *BatchCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+
+	c.platformConfig.RegisterFlagsContext(ctx, set)
+	c.tagsConfig.RegisterTagFlags(set)
+
+	f := set.NewSection("BATCH OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "query",
+		Target:  &c.FlagQuery,
+		Example: "is:issue is:open JUSTIFICATION in:body",
+		Usage:   "The platform specific search query or filter.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "concurrency",
+		Target:  &c.FlagConcurrency,
+		Default: defaultConcurrency,
+		Usage: "The maximum number of pages of results to fetch at the same time. " +
+			"GitHub search results are always fetched one page at a time.",
+	})
+
+	set.AfterParse(func(merr error) error {
+		c.FlagQuery = strings.TrimSpace(c.FlagQuery)
+
+		if c.FlagQuery == "" {
+			merr = errors.Join(merr, fmt.Errorf("query is required"))
+		}
+		if c.FlagConcurrency < 1 {
+			merr = errors.Join(merr, fmt.Errorf("concurrency must be at least 1"))
+		}
+
+		return merr
+	})
+
+	return set
+}
+
+func (c *BatchCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_batch", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) > 0 {
+		return flag.ErrHelp
+	}
+
+	platform, err := platform.NewPlatform(ctx, &c.platformConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create platform: %w", err)
+	}
+	c.platformClient = platform
+	c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+	return c.Process(ctx)
+}
+
+// Process handles the main logic for the tagrep batch.
+func (c *BatchCommand) Process(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.DebugContext(ctx, "starting tagrep batch",
+		"platform", c.platformConfig.Type,
+		"query", c.FlagQuery,
+		"concurrency", c.FlagConcurrency)
+
+	searcher, ok := c.platformClient.(platform.Searcher)
+	if !ok {
+		return fmt.Errorf("searching is not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported)
+	}
+
+	var count int
+	if err := searcher.SearchItems(ctx, c.FlagQuery, c.FlagConcurrency, func(item *platform.Item) error {
+		// One item with invalid tags should not stop the stream.
+		result := &Result{Item: item}
+		if ts, err := c.tagParser.ParseTagValues(ctx, item.Body); err != nil {
+			logger.WarnContext(ctx, "failed to parse tags for item",
+				"number", item.Number,
+				"error", err)
+			result.Error = err.Error()
+		} else {
+			result.Tags = ts
+		}
+
+		b, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to parse as json: %w", err)
+		}
+		c.Outf("%s", b)
+		count++
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	logger.DebugContext(ctx, "parsed tags from items",
+		"items", count)
+
+	return nil
+}
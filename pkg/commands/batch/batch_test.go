@@ -0,0 +1,177 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func TestBatch_Process(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name                  string
+		err                   string
+		mockPlatform          *platform.MockPlatform
+		tagParser             tags.TagParser
+		expPlatformClientReqs []*platform.Request
+		expStdout             string
+	}{
+		{
+			name: "streams_results",
+			mockPlatform: &platform.MockPlatform{
+				SearchItemsResponse: []*platform.Item{
+					{
+						Number: 1,
+						URL:    "https://github.com/owner/repo/issues/1",
+						Title:  "AOD request",
+						Body:   "AOD request\n\nJUSTIFICATION=debugging an outage\n",
+					},
+					{
+						Number: 2,
+						URL:    "https://github.com/owner/repo/issues/2",
+						Title:  "Another AOD request",
+						Body:   "JUSTIFICATION=release\nOTHER=ignored\n",
+					},
+				},
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{
+				StringTags: []string{"JUSTIFICATION"},
+			}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "SearchItems",
+					Params: []any{"is:issue JUSTIFICATION", 2},
+				},
+			},
+			expStdout: `
+{"number":1,"url":"https://github.com/owner/repo/issues/1","title":"AOD request","tags":{"JUSTIFICATION":"debugging an outage"}}
+{"number":2,"url":"https://github.com/owner/repo/issues/2","title":"Another AOD request","tags":{"JUSTIFICATION":"release"}}`,
+		},
+		{
+			name: "parse_error",
+			mockPlatform: &platform.MockPlatform{
+				SearchItemsResponse: []*platform.Item{
+					{
+						Number: 1,
+						Body:   "FLAG=not-a-bool\n",
+					},
+					{
+						Number: 2,
+						Body:   "FLAG=true\n",
+					},
+				},
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{
+				BoolTags: []string{"FLAG"},
+			}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "SearchItems",
+					Params: []any{"is:issue JUSTIFICATION", 2},
+				},
+			},
+			expStdout: `
+{"number":1,"url":"","title":"","tags":null,"error":"failed to process duplicate keys: failed to parse bool: failed to parse not-a-bool as bool: strconv.ParseBool: parsing \"not-a-bool\": invalid syntax"}
+{"number":2,"url":"","title":"","tags":{"FLAG":true}}`,
+		},
+		{
+			name: "missing_required_tag",
+			mockPlatform: &platform.MockPlatform{
+				SearchItemsResponse: []*platform.Item{
+					{
+						Number: 1,
+						Body:   "JUSTIFICATION=release\n",
+					},
+					{
+						Number: 2,
+						Body:   "A description.\n",
+					},
+					{
+						Number: 3,
+						Body:   "JUSTIFICATION=debugging\n",
+					},
+				},
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{
+				StringTags:   []string{"JUSTIFICATION"},
+				RequiredTags: []string{"JUSTIFICATION"},
+			}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "SearchItems",
+					Params: []any{"is:issue JUSTIFICATION", 2},
+				},
+			},
+			expStdout: `
+{"number":1,"url":"","title":"","tags":{"JUSTIFICATION":"release"}}
+{"number":2,"url":"","title":"","tags":null,"error":"missing required tag JUSTIFICATION"}
+{"number":3,"url":"","title":"","tags":{"JUSTIFICATION":"debugging"}}`,
+		},
+		{
+			name: "search_error",
+			mockPlatform: &platform.MockPlatform{
+				SearchItemsErr: fmt.Errorf("boom"),
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "SearchItems",
+					Params: []any{"is:issue JUSTIFICATION", 2},
+				},
+			},
+			err: "failed to search items: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &BatchCommand{
+				FlagQuery:       "is:issue JUSTIFICATION",
+				FlagConcurrency: 2,
+				platformClient:  tc.mockPlatform,
+				tagParser:       tc.tagParser,
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, tc.expPlatformClientReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package edit writes and removes tags in the body of a pull request, merge
+// request or issue.
+package edit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/posener/complete/v2"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/pkg/platform"
+)
+
+const (
+	TypeUnspecified = ""
+	TypeIssue       = "issue"
+	TypeRequest     = "request"
+
+	// maxAttempts is the number of times the body is updated when it keeps
+	// being changed concurrently.
+	maxAttempts = 3
+)
+
+var (
+	allowedTypes = map[string]struct{}{
+		TypeIssue:   {},
+		TypeRequest: {},
+	}
+	sortedTypes = func() []string {
+		allowed := append([]string{}, TypeIssue, TypeRequest)
+		sort.Strings(allowed)
+		return allowed
+	}()
+)
+
+// editCommand is the shared implementation of the commands that edit the body
+// of a request or issue.
+type editCommand struct {
+	cli.BaseCommand
+
+	platformConfig platform.Config
+
+	platformClient platform.Platform
+
+	FlagType   string
+	FlagDryRun bool
+}
+
+func (c *editCommand) registerFlags(ctx context.Context, set *cli.FlagSet, section string) {
+	c.platformConfig.RegisterFlagsContext(ctx, set)
+
+	f := set.NewSection(section)
+
+	f.StringVar(&cli.StringVar{
+		Name:    "type",
+		Target:  &c.FlagType,
+		Example: "issue",
+		Usage:   fmt.Sprintf("Type of version control platform asset to update. Allowed values are %q.", sortedTypes),
+		Predict: complete.PredictFunc(func(prefix string) []string {
+			return sortedTypes
+		}),
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "dry-run",
+		Target:  &c.FlagDryRun,
+		Default: false,
+		Usage:   "Print the unified diff of the body change instead of updating the body.",
+	})
+
+	set.AfterParse(func(merr error) error {
+		c.FlagType = strings.ToLower(strings.TrimSpace(c.FlagType))
+
+		if _, ok := allowedTypes[c.FlagType]; !ok || c.FlagType == TypeUnspecified {
+			merr = errors.Join(merr, fmt.Errorf("unsupported value for type flag: %s", c.FlagType))
+		}
+
+		return merr
+	})
+}
+
+func (c *editCommand) createPlatform(ctx context.Context) error {
+	c.platformConfig.Local.Stdin = c.Stdin()
+	platform, err := platform.NewPlatform(ctx, &c.platformConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create platform: %w", err)
+	}
+	c.platformClient = platform
+	return nil
+}
+
+// editBody applies edit to the body. If the body is changed concurrently, edit
+// is applied again to the changed body instead of overwriting the change.
+func (c *editCommand) editBody(ctx context.Context, edit func(body string) string) error {
+	logger := logging.FromContext(ctx)
+
+	updater, ok := c.platformClient.(platform.BodyUpdater)
+	if !ok {
+		return fmt.Errorf("editing bodies is not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported)
+	}
+
+	var get func(ctx context.Context) (string, error)
+	var update func(ctx context.Context, oldBody, newBody string) error
+	switch c.FlagType {
+	case TypeRequest:
+		get, update = c.platformClient.GetRequestBody, updater.UpdateRequestBody
+	case TypeIssue:
+		get, update = c.platformClient.GetIssueBody, updater.UpdateIssueBody
+	default:
+		return fmt.Errorf("failed to edit tags for unsupported version control object of type %s", c.FlagType)
+	}
+
+	body, err := get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get %s body: %w", c.FlagType, err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		newBody := edit(body)
+		if newBody == body {
+			logger.DebugContext(ctx, "body is unchanged")
+			return nil
+		}
+
+		if c.FlagDryRun {
+			return c.printDiff(body, newBody)
+		}
+
+		err := update(ctx, body, newBody)
+		if err == nil {
+			logger.DebugContext(ctx, "updated body",
+				"attempts", attempt)
+			return nil
+		}
+
+		// Apply the edit to the changed body instead of overwriting the change.
+		var changed *platform.BodyChangedError
+		if !errors.As(err, &changed) || attempt >= maxAttempts {
+			return fmt.Errorf("failed to update %s body: %w", c.FlagType, err)
+		}
+		logger.DebugContext(ctx, "body was changed concurrently, retrying",
+			"attempt", attempt)
+		body = changed.Current
+	}
+}
+
+// printDiff prints the unified diff between the old and new body.
+func (c *editCommand) printDiff(oldBody, newBody string) error {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(oldBody),
+		B:        diffLines(newBody),
+		FromFile: "a/" + c.FlagType,
+		ToFile:   "b/" + c.FlagType,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to diff %s body: %w", c.FlagType, err)
+	}
+	c.Outf("%s", strings.TrimSuffix(diff, "\n"))
+	return nil
+}
+
+// diffLines splits the body into lines ending with a line break. Like git, a
+// missing line break at the end is marked in the diff.
+func diffLines(body string) []string {
+	if body == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(body, "\n")
+	if last := lines[len(lines)-1]; last == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] = last + "\n\\ No newline at end of file\n"
+	}
+	return lines
+}
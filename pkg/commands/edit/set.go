@@ -0,0 +1,99 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+var _ cli.Command = (*SetCommand)(nil)
+
+// SetCommand sets tags in the body of a request or issue.
+type SetCommand struct {
+	editCommand
+
+	tags []*tags.Tag
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *SetCommand) Desc() string {
+	return "Set tags in the body of a request or issue"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *SetCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options] NAME=VALUE [NAME=VALUE...]
+
+	Set tags in the body of a request or issue. Existing lines of a tag are
+	replaced, other tags are added to the tagrep managed block at the end of
+	the body. Repeat a tag to set multiple values:
+
+	tagrep set -type=issue PRIORITY=p1 REVIEWERS=alice REVIEWERS=bob
+
+	If the body is edited at the same time, the tags are set again on the
+	edited body instead of overwriting the edit.
+`
+}
+
+func (c *SetCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+	c.registerFlags(ctx, set, "SET OPTIONS")
+	return set
+}
+
+func (c *SetCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_set", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) == 0 {
+		return fmt.Errorf("at least one NAME=VALUE tag is required")
+	}
+	ts, err := tags.ParseTagArgs(parsedArgs)
+	if err != nil {
+		return fmt.Errorf("failed to parse tags: %w", err)
+	}
+	c.tags = ts
+
+	if err := c.createPlatform(ctx); err != nil {
+		return err
+	}
+
+	return c.Process(ctx)
+}
+
+// Process handles the main logic for the tagrep set.
+func (c *SetCommand) Process(ctx context.Context) error {
+	logging.FromContext(ctx).DebugContext(ctx, "starting tagrep set",
+		"platform", c.platformConfig.Type,
+		"type", c.FlagType,
+		"dry_run", c.FlagDryRun)
+
+	return c.editBody(ctx, func(body string) string {
+		return tags.SetTags(body, c.tags)
+	})
+}
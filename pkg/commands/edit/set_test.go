@@ -0,0 +1,320 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func TestSet_Process(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name                  string
+		err                   string
+		setType               string
+		dryRun                bool
+		args                  []string
+		mockPlatform          *platform.MockPlatform
+		expPlatformClientReqs []*platform.Request
+		expStdout             string
+	}{
+		{
+			name:    "append_to_body",
+			setType: TypeIssue,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Access request.\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{"Access request.\n", "Access request.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->\n"}},
+			},
+		},
+		{
+			name:    "append_to_managed_block",
+			setType: TypeRequest,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "Adds a feature.\n\n<!-- tagrep:begin -->\nBUG=12\n<!-- tagrep:end -->\n\nFooter.\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{
+					"Adds a feature.\n\n<!-- tagrep:begin -->\nBUG=12\n<!-- tagrep:end -->\n\nFooter.\n",
+					"Adds a feature.\n\n<!-- tagrep:begin -->\nBUG=12\nPRIORITY=p1\n<!-- tagrep:end -->\n\nFooter.\n",
+				}},
+			},
+		},
+		{
+			name:    "append_to_managed_block_crlf",
+			setType: TypeRequest,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "Adds a feature.\r\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{
+					"Adds a feature.\r\n",
+					"Adds a feature.\r\n\r\n<!-- tagrep:begin -->\r\nPRIORITY=p1\r\n<!-- tagrep:end -->\r\n",
+				}},
+			},
+		},
+		{
+			name:    "replace_in_place",
+			setType: TypeRequest,
+			args:    []string{"reviewers=alice", "REVIEWERS=bob", "BUG=13"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "Reviewers=carol\r\nSome text.\r\nREVIEWERS=dave\r\nBUG=12\r\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{
+					"Reviewers=carol\r\nSome text.\r\nREVIEWERS=dave\r\nBUG=12\r\n",
+					"Reviewers=alice\r\nReviewers=bob\r\nSome text.\r\nBUG=13\r\n",
+				}},
+			},
+		},
+		{
+			name:    "empty_body",
+			setType: TypeIssue,
+			args:    []string{"PRIORITY=p1", "OWNER=alice"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{"", "<!-- tagrep:begin -->\nPRIORITY=p1\nOWNER=alice\n<!-- tagrep:end -->"}},
+			},
+		},
+		{
+			name:    "already_set",
+			setType: TypeIssue,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "PRIORITY=p1\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+			},
+		},
+		{
+			name:    "body_changed",
+			setType: TypeIssue,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Stale.\n",
+				UpdateIssueBodyErrs: []error{
+					fmt.Errorf("failed to update issue body: %w", &platform.BodyChangedError{Current: "Edited.\n"}),
+				},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{"Stale.\n", "Stale.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->\n"}},
+				{Name: "UpdateIssueBody", Params: []any{"Edited.\n", "Edited.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->\n"}},
+			},
+		},
+		{
+			name:    "body_keeps_changing",
+			setType: TypeIssue,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "",
+				UpdateIssueBodyErrs: []error{
+					&platform.BodyChangedError{Current: "1"},
+					&platform.BodyChangedError{Current: "2"},
+					&platform.BodyChangedError{Current: "3"},
+				},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{"", "<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->"}},
+				{Name: "UpdateIssueBody", Params: []any{"1", "1\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->"}},
+				{Name: "UpdateIssueBody", Params: []any{"2", "2\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->"}},
+			},
+			err: "failed to update issue body: body was changed since it was fetched",
+		},
+		{
+			name:    "already_set_after_change",
+			setType: TypeRequest,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "",
+				UpdateRequestBodyErrs: []error{
+					&platform.BodyChangedError{Current: "PRIORITY=p1"},
+				},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{"", "<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->"}},
+			},
+		},
+		{
+			name:    "dry_run",
+			setType: TypeIssue,
+			dryRun:  true,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Access request.\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+			},
+			expStdout: `--- a/issue
++++ b/issue
+@@ -1 +1,5 @@
+ Access request.
++
++<!-- tagrep:begin -->
++PRIORITY=p1
++<!-- tagrep:end -->
+ `,
+		},
+		{
+			name:    "dry_run_unchanged",
+			setType: TypeIssue,
+			dryRun:  true,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "PRIORITY=p1\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+			},
+		},
+		{
+			name:    "get_error",
+			setType: TypeRequest,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyErr: fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+			},
+			err: "failed to get request body: boom",
+		},
+		{
+			name:    "update_error",
+			setType: TypeRequest,
+			args:    []string{"PRIORITY=p1"},
+			mockPlatform: &platform.MockPlatform{
+				UpdateRequestBodyErrs: []error{fmt.Errorf("boom")},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{"", "<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->"}},
+			},
+			err: "failed to update request body: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts, err := tags.ParseTagArgs(tc.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c := &SetCommand{
+				editCommand: editCommand{
+					FlagType:       tc.setType,
+					FlagDryRun:     tc.dryRun,
+					platformClient: tc.mockPlatform,
+				},
+				tags: ts,
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err = c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, tc.expPlatformClientReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestSet_ParseTagArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		args []string
+		exp  []*tags.Tag
+		err  string
+	}{
+		{
+			name: "values",
+			args: []string{"PRIORITY=p1", "REVIEWERS=alice", "reviewers=bob", "EMPTY="},
+			exp: []*tags.Tag{
+				{Name: "PRIORITY", Values: []string{"p1"}},
+				{Name: "REVIEWERS", Values: []string{"alice", "bob"}},
+				{Name: "EMPTY", Values: []string{""}},
+			},
+		},
+		{
+			name: "missing_value",
+			args: []string{"PRIORITY"},
+			err:  `invalid tag "PRIORITY", must be of the form NAME=VALUE`,
+		},
+		{
+			name: "invalid_name",
+			args: []string{"MY-TAG=value"},
+			err:  `invalid tag name "MY-TAG"`,
+		},
+		{
+			name: "multiline_value",
+			args: []string{"TAG=a\nOTHER=b"},
+			err:  "invalid value for tag TAG, must not contain line breaks",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tags.ParseTagArgs(tc.args)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("tags not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+var _ cli.Command = (*UnsetCommand)(nil)
+
+// UnsetCommand removes tags from the body of a request or issue.
+type UnsetCommand struct {
+	editCommand
+
+	names []string
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *UnsetCommand) Desc() string {
+	return "Remove tags from the body of a request or issue"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *UnsetCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options] NAME [NAME...]
+
+	Remove all lines of the tags from the body of a request or issue. The
+	tagrep managed block is removed once it has no tags left:
+
+	tagrep unset -type=issue PRIORITY
+
+	If the body is edited at the same time, the tags are removed again from
+	the edited body instead of overwriting the edit.
+`
+}
+
+func (c *UnsetCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+	c.registerFlags(ctx, set, "UNSET OPTIONS")
+	return set
+}
+
+func (c *UnsetCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_unset", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) == 0 {
+		return fmt.Errorf("at least one tag NAME is required")
+	}
+	for _, name := range parsedArgs {
+		if err := tags.ValidateTagName(name); err != nil {
+			return fmt.Errorf("failed to parse tags: %w", err)
+		}
+	}
+	c.names = parsedArgs
+
+	if err := c.createPlatform(ctx); err != nil {
+		return err
+	}
+
+	return c.Process(ctx)
+}
+
+// Process handles the main logic for the tagrep unset.
+func (c *UnsetCommand) Process(ctx context.Context) error {
+	logging.FromContext(ctx).DebugContext(ctx, "starting tagrep unset",
+		"platform", c.platformConfig.Type,
+		"type", c.FlagType,
+		"dry_run", c.FlagDryRun)
+
+	return c.editBody(ctx, func(body string) string {
+		return tags.UnsetTags(body, c.names)
+	})
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/platform"
+)
+
+func TestUnset_Process(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name                  string
+		err                   string
+		unsetType             string
+		dryRun                bool
+		names                 []string
+		mockPlatform          *platform.MockPlatform
+		expPlatformClientReqs []*platform.Request
+		expStdout             string
+	}{
+		{
+			name:      "remove_from_body",
+			unsetType: TypeRequest,
+			names:     []string{"reviewers"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "REVIEWERS=alice\nSome text.\nReviewers=bob\nBUG=12\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{
+					"REVIEWERS=alice\nSome text.\nReviewers=bob\nBUG=12\n",
+					"Some text.\nBUG=12\n",
+				}},
+			},
+		},
+		{
+			name:      "keep_managed_block",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Access request.\r\n\r\n<!-- tagrep:begin -->\r\nPRIORITY=p1\r\nOWNER=alice\r\n<!-- tagrep:end -->\r\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{
+					"Access request.\r\n\r\n<!-- tagrep:begin -->\r\nPRIORITY=p1\r\nOWNER=alice\r\n<!-- tagrep:end -->\r\n",
+					"Access request.\r\n\r\n<!-- tagrep:begin -->\r\nOWNER=alice\r\n<!-- tagrep:end -->\r\n",
+				}},
+			},
+		},
+		{
+			name:      "remove_empty_managed_block",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY", "OWNER"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Access request.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\nOWNER=alice\n<!-- tagrep:end -->\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{
+					"Access request.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\nOWNER=alice\n<!-- tagrep:end -->\n",
+					"Access request.\n",
+				}},
+			},
+		},
+		{
+			name:      "remove_empty_managed_block_between_text",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Access request.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->\n\nFooter.",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{
+					"Access request.\n\n<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->\n\nFooter.",
+					"Access request.\n\nFooter.",
+				}},
+			},
+		},
+		{
+			name:      "only_managed_block",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{"<!-- tagrep:begin -->\nPRIORITY=p1\n<!-- tagrep:end -->", ""}},
+			},
+		},
+		{
+			name:      "not_set",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "Access request.\n\nOWNER=alice\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+			},
+		},
+		{
+			name:      "body_changed",
+			unsetType: TypeRequest,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "PRIORITY=p1\n",
+				UpdateRequestBodyErrs: []error{
+					&platform.BodyChangedError{Current: "Edited.\nPRIORITY=p1\n"},
+				},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpdateRequestBody", Params: []any{"PRIORITY=p1\n", ""}},
+				{Name: "UpdateRequestBody", Params: []any{"Edited.\nPRIORITY=p1\n", "Edited.\n"}},
+			},
+		},
+		{
+			name:      "dry_run",
+			unsetType: TypeRequest,
+			dryRun:    true,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "Adds a feature.\n\nPRIORITY=p1\nBUG=12",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+			},
+			expStdout: "--- a/request\n" +
+				"+++ b/request\n" +
+				"@@ -1,4 +1,3 @@\n" +
+				" Adds a feature.\n" +
+				" \n" +
+				"-PRIORITY=p1\n" +
+				" BUG=12\n" +
+				"\\ No newline at end of file",
+		},
+		{
+			name:      "get_error",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyErr: fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+			},
+			err: "failed to get issue body: boom",
+		},
+		{
+			name:      "update_error",
+			unsetType: TypeIssue,
+			names:     []string{"PRIORITY"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "PRIORITY=p1",
+				UpdateIssueBodyErrs:  []error{fmt.Errorf("boom")},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "UpdateIssueBody", Params: []any{"PRIORITY=p1", ""}},
+			},
+			err: "failed to update issue body: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &UnsetCommand{
+				editCommand: editCommand{
+					FlagType:       tc.unsetType,
+					FlagDryRun:     tc.dryRun,
+					platformClient: tc.mockPlatform,
+				},
+				names: tc.names,
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, tc.expPlatformClientReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
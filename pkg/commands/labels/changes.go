@@ -0,0 +1,128 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labels
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+// Changes are the labels to add and remove to sync the labels of a request or
+// issue with its tags.
+type Changes struct {
+	Add    []string
+	Remove []string
+}
+
+// PlanChanges lists the labels of the request or issue and returns the
+// changes to sync them with the parsed tags.
+func PlanChanges(ctx context.Context, client platform.Labeler, kind platform.ItemKind, values map[string]*tags.TagValue, rules []*Rule) (*Changes, error) {
+	existing, err := client.ListLabels(ctx, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	changes := diffLabels(rules, values, existing)
+	logging.FromContext(ctx).DebugContext(ctx, "computed label changes",
+		"existing", existing,
+		"add", changes.Add,
+		"remove", changes.Remove)
+	return changes, nil
+}
+
+// Apply adds and removes the labels of the request or issue. Nothing is
+// changed when there are no changes, so syncing again is a no-op.
+func (c *Changes) Apply(ctx context.Context, client platform.Labeler, kind platform.ItemKind) error {
+	if len(c.Add) > 0 {
+		if err := client.AddLabels(ctx, kind, c.Add); err != nil {
+			return fmt.Errorf("failed to add labels: %w", err)
+		}
+	}
+	if len(c.Remove) > 0 {
+		if err := client.RemoveLabels(ctx, kind, c.Remove); err != nil {
+			return fmt.Errorf("failed to remove labels: %w", err)
+		}
+	}
+	return nil
+}
+
+// diffLabels returns the labels the tags map to that are missing from
+// existing, and the existing labels a rule manages that the tags no longer map
+// to. Labels are compared case-insensitively and returned sorted.
+func diffLabels(rules []*Rule, values map[string]*tags.TagValue, existing []string) *Changes {
+	want := make(map[string]string)
+	for _, rule := range rules {
+		for _, v := range ruleValues(values[rule.Tag]) {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			if label, ok := rule.labelFor(v); ok {
+				want[strings.ToLower(label)] = label
+			}
+		}
+	}
+
+	changes := &Changes{}
+	have := make(map[string]struct{}, len(existing))
+	for _, l := range existing {
+		key := strings.ToLower(l)
+		have[key] = struct{}{}
+		if _, ok := want[key]; ok {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.manages(l) {
+				changes.Remove = append(changes.Remove, l)
+				break
+			}
+		}
+	}
+
+	for key, label := range want {
+		if _, ok := have[key]; !ok {
+			changes.Add = append(changes.Add, label)
+		}
+	}
+
+	sort.Strings(changes.Add)
+	sort.Strings(changes.Remove)
+	return changes
+}
+
+// ruleValues returns the values of a parsed tag that rules map to labels: each
+// value of an array tag, and the value parsing uses for other tags.
+func ruleValues(tv *tags.TagValue) []string {
+	if tv == nil {
+		return nil
+	}
+	switch v := tv.Value.(type) {
+	case []string:
+		return v
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case string:
+		return []string{v}
+	default:
+		return nil
+	}
+}
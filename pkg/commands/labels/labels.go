@@ -0,0 +1,232 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package labels keeps the labels of a pull request, merge request or issue in
+// sync with its tags.
+package labels
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete/v2"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+const (
+	TypeUnspecified = ""
+	TypeIssue       = "issue"
+	TypeRequest     = "request"
+)
+
+var (
+	allowedTypes = map[string]struct{}{
+		TypeIssue:   {},
+		TypeRequest: {},
+	}
+	sortedTypes = func() []string {
+		allowed := append([]string{}, TypeIssue, TypeRequest)
+		sort.Strings(allowed)
+		return allowed
+	}()
+)
+
+var _ cli.Command = (*SyncLabelsCommand)(nil)
+
+// SyncLabelsCommand applies the labels mapped from the tags of a request or
+// issue and removes the mapped labels that no longer apply.
+type SyncLabelsCommand struct {
+	cli.BaseCommand
+
+	platformConfig platform.Config
+	tagsConfig     tags.Config
+
+	platformClient platform.Platform
+	tagParser      tags.TagParser
+
+	rules []*Rule
+
+	FlagType     string
+	FlagDryRun   bool
+	FlagLabelMap []string
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *SyncLabelsCommand) Desc() string {
+	return "Sync the labels of a request or issue with its tags"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *SyncLabelsCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options]
+
+	Apply the labels mapped from the tags of a request or issue, and remove
+	the labels of the mappings that no longer apply. Labels that no mapping
+	can produce are left alone, so running it again changes nothing:
+
+	tagrep sync-labels -type=request -label-map="WANT_LGTM=lgtm:*"
+
+	With WANT_LGTM=all in the body this applies lgtm:all and removes other
+	lgtm:* labels, such as a stale lgtm:some. Tags are parsed like the parse
+	command: each value of an array tag is mapped, and only the last value of
+	other tags.
+`
+}
+
+func (c *SyncLabelsCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+
+	c.platformConfig.RegisterFlagsContext(ctx, set)
+	c.tagsConfig.RegisterTagFlags(set)
+
+	f := set.NewSection("SYNC-LABELS OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "type",
+		Target:  &c.FlagType,
+		Example: "request",
+		Usage:   fmt.Sprintf("Type of version control platform asset to label. Allowed values are %q.", sortedTypes),
+		Predict: complete.PredictFunc(func(prefix string) []string {
+			return sortedTypes
+		}),
+	})
+
+	f.StringSliceVar(&cli.StringSliceVar{
+		Name:    "label-map",
+		Target:  &c.FlagLabelMap,
+		Example: "WANT_LGTM=lgtm:*",
+		Usage: `Mapping from tag values to a label, repeat for multiple mappings. ` +
+			`TAG=LABEL applies LABEL for each value of TAG, with each "*" in LABEL ` +
+			`replaced by the value. TAG:VALUE=LABEL applies LABEL when TAG has VALUE.`,
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "dry-run",
+		Target:  &c.FlagDryRun,
+		Default: false,
+		Usage:   "Print the labels that would be added and removed instead of changing them.",
+	})
+
+	set.AfterParse(func(merr error) error {
+		c.FlagType = strings.ToLower(strings.TrimSpace(c.FlagType))
+
+		if _, ok := allowedTypes[c.FlagType]; !ok || c.FlagType == TypeUnspecified {
+			merr = errors.Join(merr, fmt.Errorf("unsupported value for type flag: %s", c.FlagType))
+		}
+
+		if len(c.FlagLabelMap) == 0 {
+			merr = errors.Join(merr, fmt.Errorf("at least one -label-map is required"))
+		}
+		rules, err := ParseRules(c.FlagLabelMap)
+		if err != nil {
+			merr = errors.Join(merr, err)
+		}
+		c.rules = rules
+
+		if err := ValidateRules(rules, &c.tagsConfig); err != nil {
+			merr = errors.Join(merr, err)
+		}
+
+		return merr
+	})
+
+	return set
+}
+
+func (c *SyncLabelsCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_sync_labels", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) > 0 {
+		return flag.ErrHelp
+	}
+
+	c.platformConfig.Local.Stdin = c.Stdin()
+	platform, err := platform.NewPlatform(ctx, &c.platformConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create platform: %w", err)
+	}
+	c.platformClient = platform
+	c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+	return c.Process(ctx)
+}
+
+// Process handles the main logic for the tagrep sync-labels.
+func (c *SyncLabelsCommand) Process(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.DebugContext(ctx, "starting tagrep sync-labels",
+		"platform", c.platformConfig.Type,
+		"type", c.FlagType,
+		"dry_run", c.FlagDryRun)
+
+	var kind platform.ItemKind
+	var get func(ctx context.Context) (string, error)
+	switch c.FlagType {
+	case TypeRequest:
+		kind, get = platform.ItemKindRequest, c.platformClient.GetRequestBody
+	case TypeIssue:
+		kind, get = platform.ItemKindIssue, c.platformClient.GetIssueBody
+	default:
+		return fmt.Errorf("failed to sync labels for unsupported version control object of type %s", c.FlagType)
+	}
+
+	labeler, ok := c.platformClient.(platform.Labeler)
+	if !ok {
+		return fmt.Errorf("labels are not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported)
+	}
+
+	body, err := get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get %s body: %w", c.FlagType, err)
+	}
+
+	values, err := c.tagParser.Parse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("failed to parse tags: %w", err)
+	}
+
+	changes, err := PlanChanges(ctx, labeler, kind, values, c.rules)
+	if err != nil {
+		return err
+	}
+
+	if c.FlagDryRun {
+		for _, l := range changes.Add {
+			c.Outf("+%s", l)
+		}
+		for _, l := range changes.Remove {
+			c.Outf("-%s", l)
+		}
+		return nil
+	}
+
+	return changes.Apply(ctx, labeler, kind)
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labels
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func TestSyncLabels_Process(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name                  string
+		err                   string
+		syncType              string
+		dryRun                bool
+		labelMap              []string
+		mockPlatform          *platform.MockPlatform
+		expPlatformClientReqs []*platform.Request
+		expStdout             string
+	}{
+		{
+			name:     "add_and_remove_stale",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "Adds a feature.\n\nWANT_LGTM=all\n",
+				ListLabelsResponse:     []string{"bug", "lgtm:some"},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:all"}}},
+				{Name: "RemoveLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:some"}}},
+			},
+		},
+		{
+			name:     "unchanged",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "want_lgtm=All\n",
+				ListLabelsResponse:     []string{"bug", "LGTM:all"},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+			},
+		},
+		{
+			name:     "remove_when_tag_missing",
+			syncType: TypeIssue,
+			labelMap: []string{"WANT_LGTM=lgtm:*", "PRIORITY:p1=urgent"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "PRIORITY=p2\n",
+				ListLabelsResponse:   []string{"urgent", "lgtm:all", "triaged"},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindIssue}},
+				{Name: "RemoveLabels", Params: []any{platform.ItemKindIssue, []string{"lgtm:all", "urgent"}}},
+			},
+		},
+		{
+			name:     "multiple_values_and_rules",
+			syncType: TypeIssue,
+			labelMap: []string{"TEAM=team:*", "PRIORITY:P1=urgent"},
+			mockPlatform: &platform.MockPlatform{
+				GetIssueBodyResponse: "TEAM=infra\nTEAM=\nPRIORITY=p1\nTeam=web\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetIssueBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindIssue}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindIssue, []string{"team:infra", "team:web", "urgent"}}},
+			},
+		},
+		{
+			name:     "last_value",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "WANT_LGTM=any\nWANT_LGTM=all\n",
+				ListLabelsResponse:     []string{"lgtm:any"},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:all"}}},
+				{Name: "RemoveLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:any"}}},
+			},
+		},
+		{
+			name:     "bool_value",
+			syncType: TypeRequest,
+			labelMap: []string{"DRAFT:true=draft"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "DRAFT=yes\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindRequest, []string{"draft"}}},
+			},
+		},
+		{
+			name:     "parse_error",
+			syncType: TypeRequest,
+			labelMap: []string{"DRAFT:true=draft"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "DRAFT=maybe\n",
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+			},
+			err: "failed to parse tags: failed to parse tag DRAFT on line 1",
+		},
+		{
+			name:     "dry_run",
+			syncType: TypeRequest,
+			dryRun:   true,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "WANT_LGTM=all\n",
+				ListLabelsResponse:     []string{"lgtm:some"},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+			},
+			expStdout: "+lgtm:all\n-lgtm:some",
+		},
+		{
+			name:     "get_error",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyErr: fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+			},
+			err: "failed to get request body: boom",
+		},
+		{
+			name:     "list_error",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				ListLabelsErr: fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+			},
+			err: "failed to list labels: boom",
+		},
+		{
+			name:     "add_error",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "WANT_LGTM=all\n",
+				ListLabelsResponse:     []string{"lgtm:some"},
+				AddLabelsErr:           fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:all"}}},
+			},
+			err: "failed to add labels: boom",
+		},
+		{
+			name:     "remove_error",
+			syncType: TypeRequest,
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{
+				ListLabelsResponse: []string{"lgtm:some"},
+				RemoveLabelsErr:    fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+				{Name: "RemoveLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:some"}}},
+			},
+			err: "failed to remove labels: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := make([]*Rule, 0, len(tc.labelMap))
+			for _, m := range tc.labelMap {
+				rule, err := ParseRule(m)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rules = append(rules, rule)
+			}
+
+			c := &SyncLabelsCommand{
+				FlagType:       tc.syncType,
+				FlagDryRun:     tc.dryRun,
+				platformClient: tc.mockPlatform,
+				tagParser: tags.NewTagParser(ctx, &tags.Config{
+					ArrayTags:  []string{"TEAM"},
+					StringTags: []string{"WANT_LGTM", "PRIORITY"},
+					BoolTags:   []string{"DRAFT"},
+				}),
+				rules: rules,
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, tc.expPlatformClientReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestSyncLabels_ProcessUnsupported(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	// basePlatform only has the methods of platform.Platform.
+	type basePlatform struct{ platform.Platform }
+
+	mockPlatform := &platform.MockPlatform{GetRequestBodyResponse: "WANT_LGTM=all\n"}
+	c := &SyncLabelsCommand{
+		platformConfig: platform.Config{Type: platform.TypeGit},
+		FlagType:       TypeRequest,
+		platformClient: basePlatform{mockPlatform},
+		rules:          []*Rule{{Tag: "WANT_LGTM", Label: "lgtm:*"}},
+	}
+
+	err := c.Process(ctx)
+	if diff := testutil.DiffErrString(err, "labels are not supported by the git platform"); diff != "" {
+		t.Error(diff)
+	}
+	if len(mockPlatform.Reqs) != 0 {
+		t.Errorf("expected no platform calls, got %v", mockPlatform.Reqs)
+	}
+}
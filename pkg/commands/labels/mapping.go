@@ -0,0 +1,142 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+// valuePlaceholder is replaced by the tag value in a label template.
+const valuePlaceholder = "*"
+
+// Rule maps the values of a tag to a label.
+type Rule struct {
+	// Tag is the upper case name of the tag.
+	Tag string
+	// Value is the value the tag must have for the label to apply, matched
+	// case-insensitively. Empty matches any value.
+	Value string
+	// Label is the label to apply. Without Value, each "*" is replaced by the
+	// tag value.
+	Label string
+}
+
+// ParseRule parses a rule in the form TAG=LABEL or TAG:VALUE=LABEL.
+func ParseRule(s string) (*Rule, error) {
+	tag, label, ok := strings.Cut(s, "=")
+	if !ok {
+		return nil, fmt.Errorf("invalid label mapping %q, expected TAG=LABEL or TAG:VALUE=LABEL", s)
+	}
+	tag, value, hasValue := strings.Cut(strings.TrimSpace(tag), ":")
+	tag = strings.TrimSpace(tag)
+	value = strings.TrimSpace(value)
+	label = strings.TrimSpace(label)
+
+	if err := tags.ValidateTagName(tag); err != nil {
+		return nil, fmt.Errorf("invalid label mapping %q: %w", s, err)
+	}
+	if hasValue && value == "" {
+		return nil, fmt.Errorf("invalid label mapping %q: value is empty", s)
+	}
+	if label == "" {
+		return nil, fmt.Errorf("invalid label mapping %q: label is empty", s)
+	}
+	if hasValue && strings.Contains(label, valuePlaceholder) {
+		return nil, fmt.Errorf("invalid label mapping %q: label of a TAG:VALUE mapping cannot contain %q", s, valuePlaceholder)
+	}
+
+	return &Rule{
+		Tag:   strings.ToUpper(tag),
+		Value: value,
+		Label: label,
+	}, nil
+}
+
+// ParseRules parses the rules of a list of -label-map values.
+func ParseRules(ss []string) ([]*Rule, error) {
+	rules := make([]*Rule, 0, len(ss))
+	var merr error
+	for _, s := range ss {
+		rule, err := ParseRule(s)
+		if err != nil {
+			merr = errors.Join(merr, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if merr != nil {
+		return nil, merr
+	}
+	return rules, nil
+}
+
+// ValidateRules returns an error for every rule of a tag that is not parsed
+// with cfg, as its labels would always be removed.
+func ValidateRules(rules []*Rule, cfg *tags.Config) error {
+	if cfg.OutputAll {
+		return nil
+	}
+
+	var merr error
+	for _, rule := range rules {
+		if !slices.Contains(cfg.ArrayTags, rule.Tag) && !slices.Contains(cfg.StringTags, rule.Tag) && !slices.Contains(cfg.BoolTags, rule.Tag) {
+			merr = errors.Join(merr, fmt.Errorf("label mapping tag %s is not in -array-tags, -string-tags or -bool-tags", rule.Tag))
+		}
+	}
+	return merr
+}
+
+// labelFor returns the label for the tag value and whether the rule applies to
+// it.
+func (r *Rule) labelFor(value string) (string, bool) {
+	if r.Value != "" {
+		return r.Label, strings.EqualFold(r.Value, value)
+	}
+	return strings.ReplaceAll(r.Label, valuePlaceholder, value), true
+}
+
+// manages reports whether the label is one the rule can apply, so it is
+// removed when the rule no longer applies. A "*" in the label matches any
+// text. Labels are matched case-insensitively, like platforms compare them.
+func (r *Rule) manages(label string) bool {
+	pattern := strings.ToLower(r.Label)
+	label = strings.ToLower(label)
+	if r.Value != "" {
+		return pattern == label
+	}
+
+	parts := strings.Split(pattern, valuePlaceholder)
+	if !strings.HasPrefix(label, parts[0]) {
+		return false
+	}
+	label = label[len(parts[0]):]
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		i := strings.Index(label, part)
+		if i < 0 {
+			return false
+		}
+		label = label[i+len(part):]
+	}
+	if last == 0 {
+		return label == ""
+	}
+	return strings.HasSuffix(label, parts[last])
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labels
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  *Rule
+		err  string
+	}{
+		{
+			name: "template",
+			in:   "want_lgtm=lgtm:*",
+			exp:  &Rule{Tag: "WANT_LGTM", Label: "lgtm:*"},
+		},
+		{
+			name: "value",
+			in:   " PRIORITY : p1 = priority: high ",
+			exp:  &Rule{Tag: "PRIORITY", Value: "p1", Label: "priority: high"},
+		},
+		{
+			name: "missing_label",
+			in:   "PRIORITY",
+			err:  `invalid label mapping "PRIORITY", expected TAG=LABEL or TAG:VALUE=LABEL`,
+		},
+		{
+			name: "empty_label",
+			in:   "PRIORITY= ",
+			err:  `invalid label mapping "PRIORITY= ": label is empty`,
+		},
+		{
+			name: "empty_value",
+			in:   "PRIORITY:=high",
+			err:  `invalid label mapping "PRIORITY:=high": value is empty`,
+		},
+		{
+			name: "invalid_tag",
+			in:   "PRI ORITY=high",
+			err:  `invalid label mapping "PRI ORITY=high"`,
+		},
+		{
+			name: "value_with_template",
+			in:   "PRIORITY:p1=priority:*",
+			err:  `label of a TAG:VALUE mapping cannot contain "*"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseRule(tc.in)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("rule not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRule_Manages(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		rule  *Rule
+		label string
+		exp   bool
+	}{
+		{
+			name:  "template_prefix",
+			rule:  &Rule{Tag: "WANT_LGTM", Label: "lgtm:*"},
+			label: "LGTM:Some",
+			exp:   true,
+		},
+		{
+			name:  "template_other",
+			rule:  &Rule{Tag: "WANT_LGTM", Label: "lgtm:*"},
+			label: "bug",
+			exp:   false,
+		},
+		{
+			name:  "template_infix",
+			rule:  &Rule{Tag: "TEAM", Label: "team/*/owner"},
+			label: "team/infra/owner",
+			exp:   true,
+		},
+		{
+			name:  "template_infix_suffix_mismatch",
+			rule:  &Rule{Tag: "TEAM", Label: "team/*/owner"},
+			label: "team/infra",
+			exp:   false,
+		},
+		{
+			name:  "template_multiple_placeholders",
+			rule:  &Rule{Tag: "TEAM", Label: "*-*-x"},
+			label: "a-x",
+			exp:   false,
+		},
+		{
+			name:  "value_exact",
+			rule:  &Rule{Tag: "PRIORITY", Value: "p1", Label: "urgent"},
+			label: "Urgent",
+			exp:   true,
+		},
+		{
+			name:  "value_other",
+			rule:  &Rule{Tag: "PRIORITY", Value: "p1", Label: "urgent"},
+			label: "urgent-ish",
+			exp:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.rule.manages(tc.label); got != tc.exp {
+				t.Errorf("expected manages(%q) to be %t, got %t", tc.label, tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	t.Parallel()
+
+	rules := []*Rule{
+		{Tag: "TEAM", Label: "team:*"},
+		{Tag: "WANT_LGTM", Label: "lgtm:*"},
+		{Tag: "DRAFT", Value: "true", Label: "draft"},
+		{Tag: "PRIORITY", Value: "p1", Label: "urgent"},
+	}
+
+	cases := []struct {
+		name string
+		cfg  *tags.Config
+		err  string
+	}{
+		{
+			name: "configured",
+			cfg: &tags.Config{
+				ArrayTags:  []string{"TEAM"},
+				StringTags: []string{"WANT_LGTM", "PRIORITY"},
+				BoolTags:   []string{"DRAFT"},
+			},
+		},
+		{
+			name: "output_all",
+			cfg:  &tags.Config{OutputAll: true},
+		},
+		{
+			name: "not_configured",
+			cfg: &tags.Config{
+				ArrayTags: []string{"TEAM"},
+				BoolTags:  []string{"DRAFT"},
+			},
+			err: "label mapping tag WANT_LGTM is not in -array-tags, -string-tags or -bool-tags\n" +
+				"label mapping tag PRIORITY is not in -array-tags, -string-tags or -bool-tags",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateRules(rules, tc.cfg)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -38,6 +39,16 @@ const (
 	TypeUnspecified = ""
 	TypeIssue       = "issue"
 	TypeRequest     = "request"
+
+	// PrecedenceRequest keeps the tags of the request over the tags of linked
+	// issues and tickets.
+	PrecedenceRequest = tags.PrecedenceRequest
+	// PrecedenceLinked replaces the tags of the request with the tags of linked
+	// issues and tickets.
+	PrecedenceLinked = tags.PrecedenceLinked
+
+	// SourceSuffix is appended to the name of a tag to annotate its source.
+	SourceSuffix = "_SOURCE"
 )
 
 var (
@@ -50,6 +61,7 @@ var (
 		sort.Strings(allowed)
 		return allowed
 	}()
+	sortedPrecedences = []string{PrecedenceLinked, PrecedenceRequest}
 )
 
 // ParseCommand fetches and parses a request and prints out all tags.
@@ -60,9 +72,17 @@ type ParseCommand struct {
 	tagsConfig     tags.Config
 
 	platformClient platform.Platform
+	ticketSource   platform.TicketSource
 	tagParser      tags.TagParser
 
-	FlagType string
+	FlagType               string
+	FlagTicketTag          string
+	FlagFollowLinkedIssues bool
+	FlagLinkedPrecedence   string
+	FlagAnnotateSources    bool
+	FlagSummaryComment     bool
+	FlagPublishStatus      bool
+	FlagStatusName         string
 }
 
 // Desc provides a short, one-line description of the command.
@@ -103,6 +123,65 @@ func (c *ParseCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
 		}),
 	})
 
+	f.StringVar(&cli.StringVar{
+		Name:    "ticket-tag",
+		Target:  &c.FlagTicketTag,
+		Default: "TICKET",
+		Usage: "The tag in a request whose values are Jira issue keys to read additional " +
+			"tags from when -jira-url is set.",
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "follow-linked-issues",
+		Target:  &c.FlagFollowLinkedIssues,
+		Default: false,
+		Usage: "Whether to merge the tags of the issues linked from a request, such as " +
+			"the issues it closes, into the result.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "linked-precedence",
+		Target:  &c.FlagLinkedPrecedence,
+		Default: PrecedenceRequest,
+		Usage: fmt.Sprintf("Which tags win when a request and its linked issues or tickets "+
+			"declare the same tag. Allowed values are %q.", sortedPrecedences),
+		Predict: complete.PredictFunc(func(prefix string) []string {
+			return sortedPrecedences
+		}),
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "annotate-sources",
+		Target:  &c.FlagAnnotateSources,
+		Default: false,
+		Usage: "Whether to output a TAG_SOURCE tag for every tag with where it was " +
+			"found, e.g. request, issue:<url> or ticket:<key>. Fails if a tag is " +
+			"already named TAG_SOURCE.",
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "summary-comment",
+		Target:  &c.FlagSummaryComment,
+		Default: false,
+		Usage: "Whether to create or update a comment on the request with a table of " +
+			"the recognized tags and their errors. Supported for GitHub and GitLab.",
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "publish-status",
+		Target:  &c.FlagPublishStatus,
+		Default: false,
+		Usage: "Whether to publish the validation result of the tags as a GitHub check run " +
+			"or GitLab commit status on the head commit of the request.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "status-name",
+		Target:  &c.FlagStatusName,
+		Default: "tagrep",
+		Usage:   "The name of the status published with -publish-status, e.g. to require it in branch protection.",
+	})
+
 	set.AfterParse(func(merr error) error {
 		c.FlagType = strings.ToLower(strings.TrimSpace(c.FlagType))
 
@@ -110,6 +189,21 @@ func (c *ParseCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
 			merr = errors.Join(merr, fmt.Errorf("unsupported value for type flag: %s", c.FlagType))
 		}
 
+		if c.FlagSummaryComment && c.FlagType != TypeRequest {
+			merr = errors.Join(merr, fmt.Errorf("summary-comment flag requires type %s", TypeRequest))
+		}
+		if c.FlagPublishStatus && c.FlagType != TypeRequest {
+			merr = errors.Join(merr, fmt.Errorf("publish-status flag requires type %s", TypeRequest))
+		}
+		if c.FlagPublishStatus && strings.TrimSpace(c.FlagStatusName) == "" {
+			merr = errors.Join(merr, fmt.Errorf("status-name flag is required with publish-status"))
+		}
+
+		c.FlagLinkedPrecedence = strings.ToLower(strings.TrimSpace(c.FlagLinkedPrecedence))
+		if !slices.Contains(sortedPrecedences, c.FlagLinkedPrecedence) {
+			merr = errors.Join(merr, fmt.Errorf("unsupported value for linked-precedence flag: %s", c.FlagLinkedPrecedence))
+		}
+
 		return merr
 	})
 
@@ -129,6 +223,15 @@ func (c *ParseCommand) Run(ctx context.Context, args []string) error {
 		return flag.ErrHelp
 	}
 
+	c.platformConfig.Local.Stdin = c.Stdin()
+	if c.platformConfig.Jira.JiraURL != "" && c.platformConfig.Type != platform.TypeJira {
+		jira, err := platform.NewJira(ctx, &c.platformConfig.Jira)
+		if err != nil {
+			return fmt.Errorf("failed to create jira: %w", err)
+		}
+		c.ticketSource = jira
+	}
+
 	platform, err := platform.NewPlatform(ctx, &c.platformConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create platform: %w", err)
@@ -159,7 +262,36 @@ func (c *ParseCommand) Process(ctx context.Context) (merr error) {
 	default:
 		return fmt.Errorf("failed to process tags for unsupported version control object of type %s", c.FlagType)
 	}
-	ts, err := c.tagParser.ParseTags(ctx, body)
+
+	var linked []*tags.LinkedBody
+	var linkedErr error
+	if c.FlagType == TypeRequest {
+		if linked, err = c.linkedBodies(ctx, body); err != nil {
+			linkedErr = fmt.Errorf("failed to parse linked tags: %w", err)
+		}
+	}
+
+	// Publish the summary before parsing, so invalid tags are reported too.
+	if c.FlagSummaryComment || c.FlagPublishStatus {
+		merr = errors.Join(merr, c.publishSummary(ctx, body, linked))
+	}
+	if linkedErr != nil {
+		return errors.Join(merr, linkedErr)
+	}
+
+	parsed, err := c.tagParser.ParseLinked(ctx, c.FlagType, body, linked, c.FlagLinkedPrecedence)
+	if err != nil {
+		return errors.Join(merr, fmt.Errorf("failed to parse tags: %w", err))
+	}
+
+	values := tags.Values(parsed)
+	if c.FlagAnnotateSources {
+		if err := annotateSources(values, parsed); err != nil {
+			return errors.Join(merr, fmt.Errorf("failed to annotate sources: %w", err))
+		}
+	}
+
+	ts, err := c.tagParser.FormatTags(ctx, values)
 	if err != nil {
 		return errors.Join(merr, fmt.Errorf("failed to parse tags: %w", err))
 	}
@@ -171,3 +303,96 @@ func (c *ParseCommand) Process(ctx context.Context) (merr error) {
 
 	return merr
 }
+
+// annotateSources adds the source of each parsed tag to values as a tag with
+// SourceSuffix. Tags that would be replaced by the source of another tag are
+// an error.
+func annotateSources(values map[string]any, parsed map[string]*tags.TagValue) error {
+	keys := make([]string, 0, len(parsed))
+	for k := range parsed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var merr error
+	for _, k := range keys {
+		if _, ok := parsed[k+SourceSuffix]; ok {
+			merr = errors.Join(merr, fmt.Errorf("tag %s%s conflicts with the source of tag %s", k, SourceSuffix, k))
+		}
+	}
+	if merr != nil {
+		return merr
+	}
+
+	for _, k := range keys {
+		values[k+SourceSuffix] = parsed[k].Source
+	}
+	return nil
+}
+
+// linkedBodies returns the bodies of the issues linked from the request when
+// -follow-linked-issues is set, followed by the bodies of the tickets
+// referenced with -ticket-tag, and outside of the body on platforms such as
+// Bitbucket, when a ticket source is configured.
+func (c *ParseCommand) linkedBodies(ctx context.Context, body string) ([]*tags.LinkedBody, error) {
+	var linked []*tags.LinkedBody
+
+	if c.FlagFollowLinkedIssues {
+		lister, ok := c.platformClient.(platform.LinkedIssueLister)
+		if !ok {
+			return nil, fmt.Errorf("linked issues are not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported)
+		}
+		issues, err := lister.ListLinkedIssues(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list linked issues: %w", err)
+		}
+		for _, issue := range issues {
+			ref := issue.URL
+			if ref == "" {
+				ref = fmt.Sprintf("#%d", issue.Number)
+			}
+			linked = append(linked, &tags.LinkedBody{
+				Source: "issue:" + ref,
+				Body:   issue.Body,
+			})
+		}
+	}
+
+	if c.ticketSource == nil {
+		return linked, nil
+	}
+
+	var keys []string
+	for _, v := range tags.Lookup(ctx, body, c.FlagTicketTag) {
+		for _, key := range strings.Split(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if referencer, ok := c.platformClient.(platform.TicketReferencer); ok {
+		refKeys, err := referencer.ListTicketKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ticket keys: %w", err)
+		}
+		keys = append(keys, refKeys...)
+	}
+
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		ticketBody, err := c.ticketSource.GetTicketBody(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticket body: %w", err)
+		}
+		linked = append(linked, &tags.LinkedBody{
+			Source: "ticket:" + key,
+			Body:   ticketBody,
+		})
+	}
+	return linked, nil
+}
@@ -15,6 +15,7 @@
 package parse
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -480,6 +481,75 @@ TAG_6=y
 TAG_7=n
 TAG_8=no`,
 		},
+		{
+			name:      "required_and_enum",
+			parseType: TypeRequest,
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "WANT_LGTM=all\nREVIEWERS=bob\nREVIEWERS=carol",
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{
+				Format:       tags.FormatRaw,
+				ArrayTags:    []string{"REVIEWERS"},
+				StringTags:   []string{"WANT_LGTM"},
+				RequiredTags: []string{"WANT_LGTM"},
+				Enums: map[string][]string{
+					"WANT_LGTM": {"all", "any"},
+					"REVIEWERS": {"bob", "carol"},
+				},
+			}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "GetRequestBody",
+					Params: []any{},
+				},
+			},
+			expStdout: `
+REVIEWERS=bob,carol
+WANT_LGTM=all`,
+		},
+		{
+			name:      "missing_required_tag",
+			parseType: TypeRequest,
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "REVIEWERS=bob",
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{
+				Format:       tags.FormatRaw,
+				ArrayTags:    []string{"REVIEWERS"},
+				StringTags:   []string{"WANT_LGTM"},
+				RequiredTags: []string{"WANT_LGTM"},
+			}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "GetRequestBody",
+					Params: []any{},
+				},
+			},
+			err: "missing required tag WANT_LGTM",
+		},
+		{
+			name:      "enum_value_not_allowed",
+			parseType: TypeRequest,
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "WANT_LGTM=some\nREVIEWERS=bob\nREVIEWERS=dave",
+			},
+			tagParser: tags.NewTagParser(ctx, &tags.Config{
+				Format:     tags.FormatRaw,
+				ArrayTags:  []string{"REVIEWERS"},
+				StringTags: []string{"WANT_LGTM"},
+				Enums: map[string][]string{
+					"WANT_LGTM": {"all", "any"},
+					"REVIEWERS": {"bob", "carol"},
+				},
+			}),
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "GetRequestBody",
+					Params: []any{},
+				},
+			},
+			err: `failed to parse tag REVIEWERS on line 3: value "dave" is not one of ["bob" "carol"]`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -602,3 +672,573 @@ TAG_3=A message about the tag. Something.`,
 		})
 	}
 }
+
+func TestParse_ProcessRequestTickets(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name          string
+		err           string
+		body          string
+		ticketKeys    []string
+		ticketKeysErr error
+		ticketSource  *platform.MockTicketSource
+		expTicketReqs []*platform.Request
+		expStdout     string
+	}{
+		{
+			name: "no_ticket",
+			body: "TAG_1=request",
+			ticketSource: &platform.MockTicketSource{
+				GetTicketBodyResponses: map[string]string{},
+			},
+			expStdout: "TAG_1=request",
+		},
+		{
+			name: "request_takes_precedence",
+			body: "TAG_1=request\nTICKET=PROJ-1, PROJ-2\nTICKET=PROJ-1",
+			ticketSource: &platform.MockTicketSource{
+				GetTicketBodyResponses: map[string]string{
+					"PROJ-1": "TAG_1=ticket-1\nTAG_2=ticket-1",
+					"PROJ-2": "TAG_2=ticket-2\nTAG_3=ticket-2",
+				},
+			},
+			expTicketReqs: []*platform.Request{
+				{Name: "GetTicketBody", Params: []any{"PROJ-1"}},
+				{Name: "GetTicketBody", Params: []any{"PROJ-2"}},
+			},
+			expStdout: `
+TAG_1=request
+TAG_2=ticket-1
+TAG_3=ticket-2
+TICKET=PROJ-1`,
+		},
+		{
+			name:       "referenced_tickets",
+			body:       "TAG_1=request\nTICKET=PROJ-1",
+			ticketKeys: []string{"PROJ-2", "PROJ-1"},
+			ticketSource: &platform.MockTicketSource{
+				GetTicketBodyResponses: map[string]string{
+					"PROJ-1": "TAG_2=ticket-1",
+					"PROJ-2": "TAG_2=ticket-2\nTAG_3=ticket-2",
+				},
+			},
+			expTicketReqs: []*platform.Request{
+				{Name: "GetTicketBody", Params: []any{"PROJ-1"}},
+				{Name: "GetTicketBody", Params: []any{"PROJ-2"}},
+			},
+			expStdout: `
+TAG_1=request
+TAG_2=ticket-1
+TAG_3=ticket-2
+TICKET=PROJ-1`,
+		},
+		{
+			name:          "ticket_keys_error",
+			body:          "TAG_1=request",
+			ticketKeysErr: fmt.Errorf("pull request not found"),
+			ticketSource:  &platform.MockTicketSource{},
+			err:           "failed to list ticket keys: pull request not found",
+		},
+		{
+			name: "ticket_error",
+			body: "TICKET=PROJ-1",
+			ticketSource: &platform.MockTicketSource{
+				GetTicketBodyErr: fmt.Errorf("ticket not found"),
+			},
+			expTicketReqs: []*platform.Request{
+				{Name: "GetTicketBody", Params: []any{"PROJ-1"}},
+			},
+			err: "ticket not found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &ParseCommand{
+				FlagType:      TypeRequest,
+				FlagTicketTag: "TICKET",
+				platformClient: &platform.MockPlatform{
+					GetRequestBodyResponse: tc.body,
+					ListTicketKeysResponse: tc.ticketKeys,
+					ListTicketKeysErr:      tc.ticketKeysErr,
+				},
+				ticketSource: tc.ticketSource,
+				tagParser: tags.NewTagParser(ctx, &tags.Config{
+					Format:    tags.FormatRaw,
+					OutputAll: true,
+				}),
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(tc.ticketSource.Reqs, tc.expTicketReqs); diff != "" {
+				t.Errorf("TicketSource calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestParse_ProcessRequestLinkedIssues(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name            string
+		err             string
+		precedence      string
+		annotateSources bool
+		requiredTags    []string
+		mockPlatform    *platform.MockPlatform
+		ticketSource    *platform.MockTicketSource
+		expStdout       string
+	}{
+		{
+			name: "request_precedence",
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "TAG_1=request\nTICKET=PROJ-1",
+				ListLinkedIssuesResponse: []*platform.Item{
+					{Number: 1, URL: "https://example.com/issues/1", Body: "TAG_1=issue-1\nTAG_2=issue-1"},
+					{Number: 2, Body: "TAG_2=issue-2\nTAG_3=issue-2"},
+				},
+			},
+			ticketSource: &platform.MockTicketSource{
+				GetTicketBodyResponses: map[string]string{
+					"PROJ-1": "TAG_3=ticket\nTAG_4=ticket",
+				},
+			},
+			annotateSources: true,
+			expStdout: `
+TAG_1=request
+TAG_1_SOURCE=request
+TAG_2=issue-1
+TAG_2_SOURCE=issue:https://example.com/issues/1
+TAG_3=issue-2
+TAG_3_SOURCE=issue:#2
+TAG_4=ticket
+TAG_4_SOURCE=ticket:PROJ-1
+TICKET=PROJ-1
+TICKET_SOURCE=request`,
+		},
+		{
+			name:       "linked_precedence",
+			precedence: PrecedenceLinked,
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "TAG_1=request\nTAG_2=request",
+				ListLinkedIssuesResponse: []*platform.Item{
+					{Number: 1, Body: "TAG_1=issue-1"},
+					{Number: 2, Body: "TAG_1=issue-2\nTAG_3=issue-2"},
+				},
+			},
+			expStdout: `
+TAG_1=issue-1
+TAG_2=request
+TAG_3=issue-2`,
+		},
+		{
+			name:         "required_tag_from_linked_issue",
+			requiredTags: []string{"TAG_2"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "TAG_1=request",
+				ListLinkedIssuesResponse: []*platform.Item{
+					{Number: 1, Body: "TAG_2=issue-1"},
+				},
+			},
+			expStdout: `
+TAG_1=request
+TAG_2=issue-1`,
+		},
+		{
+			name:         "required_tag_missing",
+			requiredTags: []string{"TAG_2", "TAG_3"},
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "TAG_1=request",
+				ListLinkedIssuesResponse: []*platform.Item{
+					{Number: 1, Body: "TAG_2=issue-1"},
+				},
+			},
+			err: "missing required tag TAG_3",
+		},
+		{
+			name:            "source_conflicts_with_tag",
+			annotateSources: true,
+			mockPlatform: &platform.MockPlatform{
+				GetRequestBodyResponse: "JUSTIFICATION=needed\nJUSTIFICATION_SOURCE=audit",
+			},
+			err: "tag JUSTIFICATION_SOURCE conflicts with the source of tag JUSTIFICATION",
+		},
+		{
+			name: "linked_issues_error",
+			mockPlatform: &platform.MockPlatform{
+				ListLinkedIssuesErr: fmt.Errorf("not supported"),
+			},
+			err: "failed to list linked issues: not supported",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &ParseCommand{
+				FlagType:               TypeRequest,
+				FlagTicketTag:          "TICKET",
+				FlagFollowLinkedIssues: true,
+				FlagLinkedPrecedence:   tc.precedence,
+				FlagAnnotateSources:    tc.annotateSources,
+				platformClient:         tc.mockPlatform,
+				tagParser: tags.NewTagParser(ctx, &tags.Config{
+					Format:       tags.FormatRaw,
+					OutputAll:    true,
+					RequiredTags: tc.requiredTags,
+				}),
+			}
+			if tc.ticketSource != nil {
+				c.ticketSource = tc.ticketSource
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestParse_ProcessRequestSummaryComment(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name         string
+		err          string
+		body         string
+		mockPlatform *platform.MockPlatform
+		expComment   string
+		expStdout    string
+	}{
+		{
+			name:         "tags",
+			body:         "REVIEWERS=alice\nREVIEWERS=bob\nOWNER=a|b\nOWNER=carol\nDRAFT=yes\nOTHER=ignored",
+			mockPlatform: &platform.MockPlatform{},
+			expComment: `<!-- tagrep:summary -->
+#### tagrep
+
+Recognized 3 tags, 1 with warnings.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| DRAFT | bool | true |  |
+| OWNER | string | carol | Warning: found 2 values for a tag that is not in -array-tags, using the last one |
+| REVIEWERS | array | alice<br>bob |  |
+`,
+			expStdout: `
+DRAFT=true
+OWNER=carol
+REVIEWERS=alice,bob`,
+		},
+		{
+			name:         "no_tags",
+			body:         "A description.",
+			mockPlatform: &platform.MockPlatform{},
+			expComment: `<!-- tagrep:summary -->
+#### tagrep
+
+No tags were recognized.
+`,
+		},
+		{
+			name:         "invalid_tag",
+			body:         "DRAFT=maybe\nOWNER=`<b>`",
+			mockPlatform: &platform.MockPlatform{},
+			expComment: `<!-- tagrep:summary -->
+#### tagrep
+
+Recognized 2 tags, 1 with errors.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| DRAFT | bool | maybe | failed to parse maybe as bool: strconv.ParseBool: parsing "maybe": invalid syntax |
+| OWNER | string | \` + "`" + `&lt;b&gt;\` + "`" + ` |  |
+`,
+			err: "failed to parse bool",
+		},
+		{
+			name: "comment_error",
+			body: "OWNER=alice",
+			mockPlatform: &platform.MockPlatform{
+				UpsertRequestCommentErr: fmt.Errorf("forbidden"),
+			},
+			expComment: `<!-- tagrep:summary -->
+#### tagrep
+
+Recognized 1 tags.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| OWNER | string | alice |  |
+`,
+			expStdout: "OWNER=alice",
+			err:       "failed to post summary comment: forbidden",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.mockPlatform.GetRequestBodyResponse = tc.body
+			c := &ParseCommand{
+				FlagType:           TypeRequest,
+				FlagSummaryComment: true,
+				platformClient:     tc.mockPlatform,
+				tagParser: tags.NewTagParser(ctx, &tags.Config{
+					Format:     tags.FormatRaw,
+					ArrayTags:  []string{"REVIEWERS"},
+					StringTags: []string{"OWNER"},
+					BoolTags:   []string{"DRAFT"},
+				}),
+			}
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			expReqs := []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "UpsertRequestComment", Params: []any{"<!-- tagrep:summary -->", tc.expComment}},
+			}
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, expReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := strings.TrimSpace(stdout.String()), strings.TrimSpace(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestParse_ProcessRequestStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name         string
+		err          string
+		body         string
+		requiredTags []string
+		mockPlatform *platform.MockPlatform
+		expStatus    *platform.Status
+	}{
+		{
+			name:         "valid",
+			body:         "A description.\n\nOWNER=alice\nDRAFT=no",
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "All 2 tags are valid",
+				Summary: `Recognized 2 tags.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| DRAFT | bool | false |  |
+| OWNER | string | alice |  |
+`,
+			},
+		},
+		{
+			name:         "invalid",
+			body:         "A description.\n\nDRAFT=maybe\nOWNER=alice\nOWNER=bob",
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:  "tagrep",
+				Title: "1 of 2 tags are invalid",
+				Summary: `Recognized 2 tags, 1 with errors, 1 with warnings.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| DRAFT | bool | maybe | failed to parse maybe as bool: strconv.ParseBool: parsing "maybe": invalid syntax |
+| OWNER | string | bob | Warning: found 2 values for a tag that is not in -array-tags, using the last one |
+`,
+				Annotations: []*platform.Annotation{
+					{Line: 3, Message: `DRAFT: failed to parse maybe as bool: strconv.ParseBool: parsing "maybe": invalid syntax`},
+					{Line: 5, Message: "OWNER: found 2 values for a tag that is not in -array-tags, using the last one", Warning: true},
+				},
+			},
+			err: "failed to parse bool",
+		},
+		{
+			// Repeated values are only logged by parse, so they do not fail the
+			// status.
+			name:         "repeated_value",
+			body:         "A description.\n\nOWNER=alice\nOWNER=bob",
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "All 1 tags are valid, 1 with warnings",
+				Summary: `Recognized 1 tags, 1 with warnings.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| OWNER | string | bob | Warning: found 2 values for a tag that is not in -array-tags, using the last one |
+`,
+				Annotations: []*platform.Annotation{
+					{Line: 4, Message: "OWNER: found 2 values for a tag that is not in -array-tags, using the last one", Warning: true},
+				},
+			},
+		},
+		{
+			// The line of the value that is not allowed is annotated.
+			name:         "array_enum",
+			body:         "A description.\n\nREVIEWERS=alice\nREVIEWERS=mallory\nREVIEWERS=bob",
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:  "tagrep",
+				Title: "1 of 1 tags are invalid",
+				Summary: `Recognized 1 tags, 1 with errors.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| REVIEWERS | array | alice<br>mallory<br>bob | value "mallory" is not one of ["alice" "bob"] |
+`,
+				Annotations: []*platform.Annotation{
+					{Line: 4, Message: `REVIEWERS: value "mallory" is not one of ["alice" "bob"]`},
+				},
+			},
+			err: `value "mallory" is not one of`,
+		},
+		{
+			// Tags in both -string-tags and -bool-tags are parsed as strings.
+			name:         "string_and_bool_tag",
+			body:         "A description.\n\nREVIEW=maybe",
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "All 1 tags are valid",
+				Summary: `Recognized 1 tags.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| REVIEW | string | maybe |  |
+`,
+			},
+		},
+		{
+			name:         "missing_required_tag",
+			body:         "A description.\n\nOWNER=alice",
+			requiredTags: []string{"OWNER", "DRAFT"},
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:  "tagrep",
+				Title: "1 of 2 tags are invalid",
+				Summary: `Recognized 2 tags, 1 with errors.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| DRAFT | bool |  | missing required tag |
+| OWNER | string | alice |  |
+`,
+				Annotations: []*platform.Annotation{
+					{Message: "DRAFT: missing required tag"},
+				},
+			},
+			err: "missing required tag DRAFT",
+		},
+		{
+			name:         "no_tags",
+			body:         "A description.",
+			mockPlatform: &platform.MockPlatform{},
+			expStatus: &platform.Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "No tags were recognized",
+				Summary: "No tags were recognized.\n",
+			},
+		},
+		{
+			name: "publish_error",
+			body: "OWNER=alice",
+			mockPlatform: &platform.MockPlatform{
+				PublishRequestStatusErr: fmt.Errorf("forbidden"),
+			},
+			expStatus: &platform.Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "All 1 tags are valid",
+				Summary: `Recognized 1 tags.
+
+| Tag | Type | Values | Error |
+| --- | --- | --- | --- |
+| OWNER | string | alice |  |
+`,
+			},
+			err: "failed to publish status: forbidden",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.mockPlatform.GetRequestBodyResponse = tc.body
+			c := &ParseCommand{
+				FlagType:          TypeRequest,
+				FlagPublishStatus: true,
+				FlagStatusName:    "tagrep",
+				platformClient:    tc.mockPlatform,
+				tagParser: tags.NewTagParser(ctx, &tags.Config{
+					Format:       tags.FormatRaw,
+					ArrayTags:    []string{"REVIEWERS"},
+					StringTags:   []string{"OWNER", "REVIEW"},
+					BoolTags:     []string{"DRAFT", "REVIEW"},
+					RequiredTags: tc.requiredTags,
+					Enums:        map[string][]string{"REVIEWERS": {"alice", "bob"}},
+				}),
+			}
+
+			_, _, _ = c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			expReqs := []*platform.Request{
+				{Name: "GetRequestBody", Params: []any{}},
+				{Name: "PublishRequestStatus", Params: []any{tc.expStatus}},
+			}
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, expReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
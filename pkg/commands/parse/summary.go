@@ -0,0 +1,161 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+// SummaryCommentMarker identifies the summary comment, so later runs update it
+// instead of adding a new comment. It is hidden when the comment is rendered.
+const SummaryCommentMarker = "<!-- tagrep:summary -->"
+
+// markdownCellReplacer escapes text for a Markdown table cell.
+var markdownCellReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"`", "\\`",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r", "",
+	"\n", " ",
+)
+
+// publishSummary publishes the tags recognized in the request body as the
+// summary comment and the status, as configured by the flags. Required tags
+// are also looked up in the linked bodies.
+func (c *ParseCommand) publishSummary(ctx context.Context, body string, linked []*tags.LinkedBody) (merr error) {
+	summaries := c.tagParser.SummarizeLinkedTags(ctx, body, linked)
+
+	if c.FlagSummaryComment {
+		comment := FormatSummaryComment(summaries)
+		if commenter, ok := c.platformClient.(platform.Commenter); !ok {
+			merr = errors.Join(merr, fmt.Errorf("comments are not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported))
+		} else if err := commenter.UpsertRequestComment(ctx, SummaryCommentMarker, comment); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to post summary comment: %w", err))
+		}
+	}
+
+	if c.FlagPublishStatus {
+		if publisher, ok := c.platformClient.(platform.StatusPublisher); !ok {
+			merr = errors.Join(merr, fmt.Errorf("statuses are not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported))
+		} else if err := publisher.PublishRequestStatus(ctx, NewSummaryStatus(c.FlagStatusName, summaries)); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to publish status: %w", err))
+		}
+	}
+
+	return merr
+}
+
+// FormatSummaryComment renders the summaries as the Markdown body of the
+// summary comment.
+func FormatSummaryComment(summaries []*tags.TagSummary) string {
+	return SummaryCommentMarker + "\n#### tagrep\n\n" + formatSummary(summaries)
+}
+
+// NewSummaryStatus returns the status for the summaries, which fails if any
+// tag is invalid. Warnings are annotated without failing the status, like
+// parsing only logs them.
+func NewSummaryStatus(name string, summaries []*tags.TagSummary) *platform.Status {
+	status := &platform.Status{
+		Name:    name,
+		Success: true,
+		Summary: formatSummary(summaries),
+	}
+	var invalid, warnings int
+	for _, s := range summaries {
+		if s.Err != nil {
+			invalid++
+			status.Success = false
+			status.Annotations = append(status.Annotations, &platform.Annotation{
+				Line:    s.Line,
+				Message: fmt.Sprintf("%s: %s", s.Name, s.Err),
+			})
+		}
+		if s.Warning != nil {
+			warnings++
+			status.Annotations = append(status.Annotations, &platform.Annotation{
+				Line:    s.Line,
+				Message: fmt.Sprintf("%s: %s", s.Name, s.Warning),
+				Warning: true,
+			})
+		}
+	}
+
+	switch {
+	case len(summaries) == 0:
+		status.Title = "No tags were recognized"
+	case status.Success && warnings > 0:
+		status.Title = fmt.Sprintf("All %d tags are valid, %d with warnings", len(summaries), warnings)
+	case status.Success:
+		status.Title = fmt.Sprintf("All %d tags are valid", len(summaries))
+	default:
+		status.Title = fmt.Sprintf("%d of %d tags are invalid", invalid, len(summaries))
+	}
+	return status
+}
+
+// formatSummary renders the summaries as a Markdown table.
+func formatSummary(summaries []*tags.TagSummary) string {
+	var b strings.Builder
+
+	if len(summaries) == 0 {
+		b.WriteString("No tags were recognized.\n")
+		return b.String()
+	}
+
+	var invalid, warnings int
+	for _, s := range summaries {
+		if s.Err != nil {
+			invalid++
+		}
+		if s.Warning != nil {
+			warnings++
+		}
+	}
+	fmt.Fprintf(&b, "Recognized %d tags", len(summaries))
+	if invalid > 0 {
+		fmt.Fprintf(&b, ", %d with errors", invalid)
+	}
+	if warnings > 0 {
+		fmt.Fprintf(&b, ", %d with warnings", warnings)
+	}
+	b.WriteString(".\n\n")
+
+	b.WriteString("| Tag | Type | Values | Error |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, s := range summaries {
+		values := make([]string, len(s.Values))
+		for i, v := range s.Values {
+			values[i] = markdownCellReplacer.Replace(v)
+		}
+		var problems []string
+		if s.Err != nil {
+			problems = append(problems, markdownCellReplacer.Replace(s.Err.Error()))
+		}
+		if s.Warning != nil {
+			problems = append(problems, "Warning: "+markdownCellReplacer.Replace(s.Warning.Error()))
+		}
+		errText := strings.Join(problems, "<br>")
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", s.Name, s.Type, strings.Join(values, "<br>"), errText)
+	}
+	return b.String()
+}
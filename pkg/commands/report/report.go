@@ -0,0 +1,320 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report parses every github pull request or gitlab merge request
+// merged in a range of commits and prints the tags of each along with per-tag
+// aggregates.
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+var _ cli.Command = (*ReportCommand)(nil)
+
+// ReportCommand lists the requests merged in a range of commits and prints
+// out the tags of each.
+type ReportCommand struct {
+	cli.BaseCommand
+
+	platformConfig platform.Config
+	tagsConfig     tags.Config
+
+	platformClient platform.Platform
+	tagParser      tags.TagParser
+
+	FlagBase string
+	FlagHead string
+}
+
+// Report is the output of the report command.
+type Report struct {
+	// Requests are the parsed tags of each request keyed by request number.
+	Requests map[int]*RequestReport `json:"requests"`
+
+	// Tags are the aggregated values of each tag across all requests.
+	Tags map[string]*TagAggregate `json:"tags"`
+}
+
+// RequestReport is the parsed tags of a single request.
+type RequestReport struct {
+	Number int            `json:"number"`
+	URL    string         `json:"url"`
+	Title  string         `json:"title"`
+	Tags   map[string]any `json:"tags"`
+
+	// Error is why the tags of the request could not be parsed, e.g. a missing
+	// required tag. Its tags are not aggregated.
+	Error string `json:"error,omitempty"`
+}
+
+// TagAggregate is the aggregated values of a single tag.
+type TagAggregate struct {
+	// Count is the number of requests the tag was found in.
+	Count int `json:"count"`
+
+	// Values are the request numbers each value was found in, keyed by value.
+	Values map[string][]int `json:"values"`
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *ReportCommand) Desc() string {
+	return "Report tags of all requests merged in a range of commits"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *ReportCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options]
+
+	Parse the tags of every pull request or merge request merged between two
+	revisions and output a report keyed by request number, including the
+	aggregated values of each tag.
+
+	With -format=json the report is printed as a single JSON object. With
+	-format=raw the report is printed as a table.
+`
+}
+
+func (c *ReportCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+
+	c.platformConfig.RegisterFlagsContext(ctx, set)
+	c.tagsConfig.RegisterFlags(set)
+
+	f := set.NewSection("REPORT OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "base",
+		Target:  &c.FlagBase,
+		Example: "v1.0.0",
+		Usage:   "The base revision (tag, branch or SHA) of the range, exclusive.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "head",
+		Target:  &c.FlagHead,
+		Example: "v1.1.0",
+		Usage:   "The head revision (tag, branch or SHA) of the range, inclusive.",
+	})
+
+	set.AfterParse(func(merr error) error {
+		c.FlagBase = strings.TrimSpace(c.FlagBase)
+		c.FlagHead = strings.TrimSpace(c.FlagHead)
+
+		if c.FlagBase == "" {
+			merr = errors.Join(merr, fmt.Errorf("base is required"))
+		}
+		if c.FlagHead == "" {
+			merr = errors.Join(merr, fmt.Errorf("head is required"))
+		}
+
+		return merr
+	})
+
+	return set
+}
+
+func (c *ReportCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_report", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) > 0 {
+		return flag.ErrHelp
+	}
+
+	platform, err := platform.NewPlatform(ctx, &c.platformConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create platform: %w", err)
+	}
+	c.platformClient = platform
+	c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+	return c.Process(ctx)
+}
+
+// Process handles the main logic for the tagrep report.
+func (c *ReportCommand) Process(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.DebugContext(ctx, "starting tagrep report",
+		"platform", c.platformConfig.Type,
+		"base", c.FlagBase,
+		"head", c.FlagHead)
+
+	lister, ok := c.platformClient.(platform.RangeLister)
+	if !ok {
+		return fmt.Errorf("listing requests is not supported by the %s platform: %w", c.platformConfig.Type, errors.ErrUnsupported)
+	}
+
+	items, err := lister.ListRequestsInRange(ctx, c.FlagBase, c.FlagHead)
+	if err != nil {
+		return fmt.Errorf("failed to list requests: %w", err)
+	}
+
+	r := &Report{
+		Requests: make(map[int]*RequestReport, len(items)),
+		Tags:     make(map[string]*TagAggregate),
+	}
+	for _, item := range items {
+		req := &RequestReport{
+			Number: item.Number,
+			URL:    item.URL,
+			Title:  item.Title,
+		}
+		r.Requests[item.Number] = req
+
+		// One request with invalid tags should not hide the others.
+		ts, err := c.tagParser.ParseTagValues(ctx, item.Body)
+		if err != nil {
+			logger.WarnContext(ctx, "failed to parse tags for request",
+				"number", item.Number,
+				"error", err)
+			req.Error = err.Error()
+			continue
+		}
+		req.Tags = ts
+
+		for k, v := range ts {
+			agg, ok := r.Tags[k]
+			if !ok {
+				agg = &TagAggregate{Values: make(map[string][]int)}
+				r.Tags[k] = agg
+			}
+			agg.Count++
+			for _, s := range stringifyValues(v) {
+				// A value repeated in the request counts the request once.
+				if nums := agg.Values[s]; len(nums) > 0 && nums[len(nums)-1] == item.Number {
+					continue
+				}
+				agg.Values[s] = append(agg.Values[s], item.Number)
+			}
+		}
+	}
+
+	logger.DebugContext(ctx, "parsed tags from requests",
+		"requests", len(r.Requests))
+
+	out, err := c.format(r)
+	if err != nil {
+		return fmt.Errorf("failed to format report: %w", err)
+	}
+	c.Outf("%s", out)
+
+	return nil
+}
+
+func (c *ReportCommand) format(r *Report) (string, error) {
+	switch c.tagsConfig.Format {
+	case tags.FormatJSON:
+		var b []byte
+		var err error
+		if c.tagsConfig.PrettyPrint {
+			b, err = json.MarshalIndent(r, "", "  ")
+		} else {
+			b, err = json.Marshal(r)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse as json: %w", err)
+		}
+		return string(b), nil
+	case tags.FormatRaw:
+		return formatTable(r)
+	default:
+		return "", fmt.Errorf("format '%s' is invalid", c.tagsConfig.Format)
+	}
+}
+
+// formatTable renders the report as a table of requests followed by a table
+// of the aggregated tag values.
+func formatTable(r *Report) (string, error) {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(w, "NUMBER\tTITLE\tTAGS")
+	numbers := maps.Keys(r.Requests)
+	sort.Ints(numbers)
+	for _, n := range numbers {
+		req := r.Requests[n]
+		if req.Error != "" {
+			fmt.Fprintf(w, "#%d\t%s\terror: %s\n", n, req.Title, strings.ReplaceAll(req.Error, "\n", "; "))
+			continue
+		}
+		keys := maps.Keys(req.Tags)
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, fmt.Sprintf("%s=%s", k, strings.Join(stringifyValues(req.Tags[k]), ",")))
+		}
+		fmt.Fprintf(w, "#%d\t%s\t%s\n", n, req.Title, strings.Join(parts, " "))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "TAG\tVALUE\tCOUNT\tREQUESTS")
+	tagKeys := maps.Keys(r.Tags)
+	sort.Strings(tagKeys)
+	for _, k := range tagKeys {
+		agg := r.Tags[k]
+		values := maps.Keys(agg.Values)
+		sort.Strings(values)
+		for _, v := range values {
+			refs := make([]string, 0, len(agg.Values[v]))
+			for _, n := range agg.Values[v] {
+				refs = append(refs, "#"+strconv.Itoa(n))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", k, v, len(agg.Values[v]), strings.Join(refs, ","))
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write table: %w", err)
+	}
+	return builder.String(), nil
+}
+
+// stringifyValues returns the individual string values of a processed tag
+// value, one per element for array tags.
+func stringifyValues(v any) []string {
+	switch t := v.(type) {
+	case []string:
+		return t
+	case bool:
+		return []string{strconv.FormatBool(t)}
+	case string:
+		return []string{t}
+	default:
+		return []string{fmt.Sprintf("%v", t)}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func TestReport_Process(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	items := []*platform.Item{
+		{
+			Number: 12,
+			URL:    "https://github.com/owner/repo/pull/12",
+			Title:  "fix: some bug",
+			Body:   "fix: some bug\n\nWANT_LGTM=all\nREVIEWERS=alice\nREVIEWERS=bob\n",
+		},
+		{
+			Number: 15,
+			URL:    "https://github.com/owner/repo/pull/15",
+			Title:  "feat: a feature",
+			Body:   "feat: a feature\n\nWANT_LGTM=any\nREVIEWERS=alice\n",
+		},
+		{
+			Number: 17,
+			URL:    "https://github.com/owner/repo/pull/17",
+			Title:  "chore: no tags",
+			Body:   "chore: no tags\n",
+		},
+	}
+
+	cases := []struct {
+		name                  string
+		err                   string
+		tagsConfig            tags.Config
+		mockPlatform          *platform.MockPlatform
+		expPlatformClientReqs []*platform.Request
+		expStdout             string
+	}{
+		{
+			name: "json",
+			tagsConfig: tags.Config{
+				Format:    tags.FormatJSON,
+				ArrayTags: []string{"REVIEWERS"},
+				OutputAll: true,
+			},
+			mockPlatform: &platform.MockPlatform{
+				ListRequestsInRangeResponse: items,
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "ListRequestsInRange",
+					Params: []any{"v1.0.0", "v1.1.0"},
+				},
+			},
+			expStdout: `{"requests":{` +
+				`"12":{"number":12,"url":"https://github.com/owner/repo/pull/12","title":"fix: some bug","tags":{"REVIEWERS":["alice","bob"],"WANT_LGTM":"all"}},` +
+				`"15":{"number":15,"url":"https://github.com/owner/repo/pull/15","title":"feat: a feature","tags":{"REVIEWERS":["alice"],"WANT_LGTM":"any"}},` +
+				`"17":{"number":17,"url":"https://github.com/owner/repo/pull/17","title":"chore: no tags","tags":{}}},` +
+				`"tags":{` +
+				`"REVIEWERS":{"count":2,"values":{"alice":[12,15],"bob":[12]}},` +
+				`"WANT_LGTM":{"count":2,"values":{"all":[12],"any":[15]}}}}`,
+		},
+		{
+			name: "table",
+			tagsConfig: tags.Config{
+				Format:     tags.FormatRaw,
+				StringTags: []string{"WANT_LGTM"},
+			},
+			mockPlatform: &platform.MockPlatform{
+				ListRequestsInRangeResponse: items,
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "ListRequestsInRange",
+					Params: []any{"v1.0.0", "v1.1.0"},
+				},
+			},
+			expStdout: `
+NUMBER  TITLE            TAGS
+#12     fix: some bug    WANT_LGTM=all
+#15     feat: a feature  WANT_LGTM=any
+#17     chore: no tags
+
+TAG        VALUE  COUNT  REQUESTS
+WANT_LGTM  all    1      #12
+WANT_LGTM  any    1      #15`,
+		},
+		{
+			name: "missing_required_tag_json",
+			tagsConfig: tags.Config{
+				Format:       tags.FormatJSON,
+				StringTags:   []string{"WANT_LGTM"},
+				RequiredTags: []string{"WANT_LGTM"},
+			},
+			mockPlatform: &platform.MockPlatform{
+				ListRequestsInRangeResponse: items,
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "ListRequestsInRange",
+					Params: []any{"v1.0.0", "v1.1.0"},
+				},
+			},
+			expStdout: `{"requests":{` +
+				`"12":{"number":12,"url":"https://github.com/owner/repo/pull/12","title":"fix: some bug","tags":{"WANT_LGTM":"all"}},` +
+				`"15":{"number":15,"url":"https://github.com/owner/repo/pull/15","title":"feat: a feature","tags":{"WANT_LGTM":"any"}},` +
+				`"17":{"number":17,"url":"https://github.com/owner/repo/pull/17","title":"chore: no tags","tags":null,"error":"missing required tag WANT_LGTM"}},` +
+				`"tags":{` +
+				`"WANT_LGTM":{"count":2,"values":{"all":[12],"any":[15]}}}}`,
+		},
+		{
+			name: "invalid_enum_table",
+			tagsConfig: tags.Config{
+				Format:     tags.FormatRaw,
+				StringTags: []string{"WANT_LGTM"},
+				Enums:      map[string][]string{"WANT_LGTM": {"all"}},
+			},
+			mockPlatform: &platform.MockPlatform{
+				ListRequestsInRangeResponse: items,
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "ListRequestsInRange",
+					Params: []any{"v1.0.0", "v1.1.0"},
+				},
+			},
+			expStdout: `
+NUMBER  TITLE            TAGS
+#12     fix: some bug    WANT_LGTM=all
+#15     feat: a feature  error: failed to process duplicate keys: value "any" is not one of ["all"]
+#17     chore: no tags
+
+TAG        VALUE  COUNT  REQUESTS
+WANT_LGTM  all    1      #12`,
+		},
+		{
+			name: "repeated_array_value",
+			tagsConfig: tags.Config{
+				Format:    tags.FormatJSON,
+				ArrayTags: []string{"REVIEWERS"},
+			},
+			mockPlatform: &platform.MockPlatform{
+				ListRequestsInRangeResponse: []*platform.Item{
+					{
+						Number: 12,
+						URL:    "https://github.com/owner/repo/pull/12",
+						Title:  "fix: some bug",
+						Body:   "REVIEWERS=alice\nREVIEWERS=bob\nREVIEWERS=alice\n",
+					},
+				},
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "ListRequestsInRange",
+					Params: []any{"v1.0.0", "v1.1.0"},
+				},
+			},
+			expStdout: `{"requests":{` +
+				`"12":{"number":12,"url":"https://github.com/owner/repo/pull/12","title":"fix: some bug","tags":{"REVIEWERS":["alice","bob","alice"]}}},` +
+				`"tags":{` +
+				`"REVIEWERS":{"count":1,"values":{"alice":[12],"bob":[12]}}}}`,
+		},
+		{
+			name: "list_error",
+			tagsConfig: tags.Config{
+				Format: tags.FormatJSON,
+			},
+			mockPlatform: &platform.MockPlatform{
+				ListRequestsInRangeErr: fmt.Errorf("boom"),
+			},
+			expPlatformClientReqs: []*platform.Request{
+				{
+					Name:   "ListRequestsInRange",
+					Params: []any{"v1.0.0", "v1.1.0"},
+				},
+			},
+			err: "failed to list requests: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &ReportCommand{
+				FlagBase:       "v1.0.0",
+				FlagHead:       "v1.1.0",
+				tagsConfig:     tc.tagsConfig,
+				platformClient: tc.mockPlatform,
+			}
+			c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+			_, stdout, _ := c.Pipe()
+
+			err := c.Process(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(tc.mockPlatform.Reqs, tc.expPlatformClientReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := trimLines(stdout.String()), trimLines(tc.expStdout); got != want {
+				t.Errorf("expected stdout\n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+// trimLines trims trailing whitespace from each line, as the table writer pads
+// the last column.
+func trimLines(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestReport_ProcessUnsupported(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	// basePlatform only has the methods of platform.Platform.
+	type basePlatform struct{ platform.Platform }
+
+	c := &ReportCommand{
+		platformConfig: platform.Config{Type: platform.TypeLocal},
+		FlagBase:       "v1.0.0",
+		FlagHead:       "v1.1.0",
+		platformClient: basePlatform{&platform.MockPlatform{}},
+	}
+	c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+	err := c.Process(ctx)
+	if diff := testutil.DiffErrString(err, "listing requests is not supported by the local platform"); diff != "" {
+		t.Error(diff)
+	}
+}
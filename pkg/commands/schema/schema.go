@@ -0,0 +1,147 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema prints the JSON Schema of the JSON output of the parse
+// command.
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/commands/parse"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+var _ cli.Command = (*SchemaCommand)(nil)
+
+// SchemaCommand prints the JSON Schema of the tags output by parse with
+// -format=json.
+type SchemaCommand struct {
+	cli.BaseCommand
+
+	tagsConfig tags.Config
+
+	tagParser tags.TagParser
+
+	FlagAnnotateSources bool
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *SchemaCommand) Desc() string {
+	return "Print the JSON Schema of the json output of parse"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *SchemaCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options]
+
+	Print the JSON Schema of the object output by parse with -format=json,
+	for the same tag flags, so consumers can validate it and generate types
+	for it:
+
+	tagrep schema -array-tags=REVIEWERS -string-tags=WANT_LGTM \
+		-required-tags=WANT_LGTM -enum="WANT_LGTM=all|any"
+`
+}
+
+func (c *SchemaCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+
+	c.tagsConfig.RegisterTagFlags(set)
+
+	f := set.NewSection("SCHEMA OPTIONS")
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "annotate-sources",
+		Target:  &c.FlagAnnotateSources,
+		Default: false,
+		Usage:   "Whether parse runs with -annotate-sources, which outputs a TAG_SOURCE tag for every tag.",
+	})
+
+	return set
+}
+
+func (c *SchemaCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_schema", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) > 0 {
+		return flag.ErrHelp
+	}
+
+	c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+	return c.Process(ctx)
+}
+
+// Process handles the main logic for the tagrep schema.
+func (c *SchemaCommand) Process(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	logger.DebugContext(ctx, "starting tagrep schema",
+		"array_tags", c.tagsConfig.ArrayTags,
+		"string_tags", c.tagsConfig.StringTags,
+		"bool_tags", c.tagsConfig.BoolTags,
+		"required_tags", c.tagsConfig.RequiredTags,
+		"output_all", c.tagsConfig.OutputAll)
+
+	opts := &tags.SchemaOptions{}
+	if c.FlagAnnotateSources {
+		opts.Extra = c.sourceProperties()
+	}
+
+	s, err := c.tagParser.JSONSchema(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	c.Outf("%s", strings.TrimSpace(b.String()))
+	return nil
+}
+
+// sourceProperties returns the properties of the sources output by parse
+// with -annotate-sources for the configured tags. The sources of other tags
+// are allowed by -output-all.
+func (c *SchemaCommand) sourceProperties() map[string]*tags.Schema {
+	props := make(map[string]*tags.Schema)
+	for _, list := range [][]string{c.tagsConfig.ArrayTags, c.tagsConfig.StringTags, c.tagsConfig.BoolTags} {
+		for _, tag := range list {
+			props[tag+parse.SourceSuffix] = &tags.Schema{
+				Type:        "string",
+				Description: fmt.Sprintf("Where %s was found, e.g. request, issue:<url> or ticket:<key>.", tag),
+			}
+		}
+	}
+	return props
+}
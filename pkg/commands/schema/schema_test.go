@@ -0,0 +1,171 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestSchema_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name      string
+		args      []string
+		err       string
+		expSchema map[string]any
+	}{
+		{
+			name: "types",
+			args: []string{"-array-tags=REVIEWERS", "-string-tags=WANT_LGTM", "-bool-tags=DRAFT"},
+			expSchema: map[string]any{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title":   "tagrep tags",
+				"type":    "object",
+				"properties": map[string]any{
+					"DRAFT":     map[string]any{"type": "boolean"},
+					"REVIEWERS": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					"WANT_LGTM": map[string]any{"type": "string"},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name: "array_takes_precedence",
+			args: []string{"-array-tags=REVIEWERS", "-string-tags=REVIEWERS"},
+			expSchema: map[string]any{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title":   "tagrep tags",
+				"type":    "object",
+				"properties": map[string]any{
+					"REVIEWERS": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name: "required_and_enums",
+			args: []string{
+				"-array-tags=REVIEWERS", "-string-tags=WANT_LGTM",
+				"-required-tags=WANT_LGTM",
+				"-enum=WANT_LGTM=all|any", "-enum=REVIEWERS=bob|carol",
+			},
+			expSchema: map[string]any{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title":   "tagrep tags",
+				"type":    "object",
+				"properties": map[string]any{
+					"REVIEWERS": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"type": "string", "enum": []any{"bob", "carol"}},
+					},
+					"WANT_LGTM": map[string]any{"type": "string", "enum": []any{"all", "any"}},
+				},
+				"required":             []any{"WANT_LGTM"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name: "output_all",
+			args: []string{"-bool-tags=DRAFT", "-output-all", "-required-tags=OWNER", "-enum=TEAM=a|b"},
+			expSchema: map[string]any{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title":   "tagrep tags",
+				"type":    "object",
+				"properties": map[string]any{
+					"DRAFT": map[string]any{"type": "boolean"},
+					"TEAM":  map[string]any{"type": "string", "enum": []any{"a", "b"}},
+				},
+				"required":             []any{"OWNER"},
+				"additionalProperties": map[string]any{"type": "string"},
+			},
+		},
+		{
+			name: "annotate_sources",
+			args: []string{"-string-tags=WANT_LGTM", "-annotate-sources"},
+			expSchema: map[string]any{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title":   "tagrep tags",
+				"type":    "object",
+				"properties": map[string]any{
+					"WANT_LGTM": map[string]any{"type": "string"},
+					"WANT_LGTM_SOURCE": map[string]any{
+						"type":        "string",
+						"description": "Where WANT_LGTM was found, e.g. request, issue:<url> or ticket:<key>.",
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			name: "annotate_sources_conflict",
+			args: []string{"-string-tags=JUSTIFICATION,JUSTIFICATION_SOURCE", "-annotate-sources"},
+			err:  "property JUSTIFICATION_SOURCE is already a tag",
+		},
+		{
+			name: "unknown_required_and_enum",
+			args: []string{"-string-tags=WANT_LGTM", "-required-tags=OWNER", "-enum=TEAM=a|b"},
+			err:  "required tag OWNER is not in -array-tags, -string-tags or -bool-tags\nenum tag TEAM is not in -array-tags, -string-tags or -bool-tags",
+		},
+		{
+			name: "bool_enum",
+			args: []string{"-bool-tags=DRAFT", "-enum=DRAFT=true"},
+			err:  "enum is not supported for bool tag DRAFT",
+		},
+		{
+			name: "invalid_enum",
+			args: []string{"-string-tags=WANT_LGTM", "-enum=WANT_LGTM", "-enum=WANT_LGTM=a", "-enum=WANT_LGTM=b"},
+			err:  "invalid enum \"WANT_LGTM\", expected TAG=VALUE|VALUE\nduplicate enum for tag WANT_LGTM",
+		},
+		{
+			name: "unexpected_argument",
+			args: []string{"-string-tags=WANT_LGTM", "extra"},
+			err:  "flag: help requested",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &SchemaCommand{}
+			_, stdout, _ := c.Pipe()
+
+			err := c.Run(ctx, tc.args)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if err != nil {
+				return
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal schema %q: %v", stdout.String(), err)
+			}
+			if diff := cmp.Diff(got, tc.expSchema); diff != "" {
+				t.Errorf("Schema not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/pkg/commands/labels"
+	"github.com/abcxyz/tagrep/pkg/commands/parse"
+	"github.com/abcxyz/tagrep/pkg/platform"
+)
+
+// forwardPayload is the JSON object the tags of an event are forwarded as.
+type forwardPayload struct {
+	Platform   string         `json:"platform"`
+	Type       string         `json:"type"`
+	Action     string         `json:"action"`
+	Repository string         `json:"repository"`
+	Number     int            `json:"number"`
+	URL        string         `json:"url"`
+	Tags       map[string]any `json:"tags"`
+}
+
+// processEvent runs the configured actions for the event. A failing action
+// does not stop the other actions.
+func (c *ServeCommand) processEvent(ctx context.Context, ev *event) (merr error) {
+	logger := logging.FromContext(ctx)
+
+	needsPlatform := len(c.labelRules) > 0 ||
+		(ev.kind == platform.ItemKindRequest && (c.FlagSummaryComment || c.FlagPublishStatus))
+
+	var client platform.Platform
+	if needsPlatform {
+		var err error
+		if client, err = c.eventPlatform(ctx, ev); err != nil {
+			return fmt.Errorf("failed to create platform: %w", err)
+		}
+	}
+
+	if len(c.labelRules) > 0 {
+		if err := c.syncLabels(ctx, client, ev); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to sync labels: %w", err))
+		}
+	}
+
+	if ev.kind == platform.ItemKindRequest && (c.FlagSummaryComment || c.FlagPublishStatus) {
+		summaries := c.tagParser.SummarizeTags(ctx, ev.body)
+
+		if c.FlagSummaryComment {
+			comment := parse.FormatSummaryComment(summaries)
+			if commenter, ok := client.(platform.Commenter); !ok {
+				merr = errors.Join(merr, fmt.Errorf("comments are not supported by the %s platform: %w", ev.platform, errors.ErrUnsupported))
+			} else if err := commenter.UpsertRequestComment(ctx, parse.SummaryCommentMarker, comment); err != nil {
+				merr = errors.Join(merr, fmt.Errorf("failed to post summary comment: %w", err))
+			}
+		}
+
+		if c.FlagPublishStatus {
+			if publisher, ok := client.(platform.StatusPublisher); !ok {
+				merr = errors.Join(merr, fmt.Errorf("statuses are not supported by the %s platform: %w", ev.platform, errors.ErrUnsupported))
+			} else if err := publisher.PublishRequestStatus(ctx, parse.NewSummaryStatus(c.FlagStatusName, summaries)); err != nil {
+				merr = errors.Join(merr, fmt.Errorf("failed to publish status: %w", err))
+			}
+		}
+	}
+
+	if c.FlagForwardURL != "" {
+		if err := c.forward(ctx, ev); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to forward tags: %w", err))
+		}
+	}
+
+	logger.DebugContext(ctx, "processed webhook event",
+		"repository", ev.repository,
+		"number", ev.number,
+		"error", merr)
+	return merr
+}
+
+// syncLabels syncs the labels of the request or issue of the event with its
+// tags.
+func (c *ServeCommand) syncLabels(ctx context.Context, client platform.Platform, ev *event) error {
+	labeler, ok := client.(platform.Labeler)
+	if !ok {
+		return fmt.Errorf("labels are not supported by the %s platform: %w", ev.platform, errors.ErrUnsupported)
+	}
+	values, err := c.tagParser.Parse(ctx, ev.body)
+	if err != nil {
+		return fmt.Errorf("failed to parse tags: %w", err)
+	}
+	changes, err := labels.PlanChanges(ctx, labeler, ev.kind, values, c.labelRules)
+	if err != nil {
+		return err //nolint:wrapcheck // Want passthrough
+	}
+	return changes.Apply(ctx, labeler, ev.kind) //nolint:wrapcheck // Want passthrough
+}
+
+// eventPlatform creates the platform for the repository and the request or
+// issue of the event, from the platform flags.
+func (c *ServeCommand) eventPlatform(ctx context.Context, ev *event) (platform.Platform, error) {
+	cfg := c.platformConfig
+	cfg.Type = ev.platform
+
+	switch ev.platform {
+	case platform.TypeGitHub:
+		gh := &cfg.GitHub
+		gh.GitHubOwner, gh.GitHubRepo = ev.gitHubOwner, ev.gitHubRepo
+		gh.GitHubPullRequestNumber, gh.GitHubPullRequestBody = 0, ""
+		gh.GitHubIssueNumber, gh.GitHubIssueBody = 0, ""
+		gh.GitHubSHA = ev.sha
+		if ev.kind == platform.ItemKindRequest {
+			gh.GitHubPullRequestNumber, gh.GitHubPullRequestBody = ev.number, ev.body
+		} else {
+			gh.GitHubIssueNumber, gh.GitHubIssueBody = ev.number, ev.body
+		}
+		// A GitHub App authenticates as the installation the event is from.
+		if gh.GitHubAppID != "" && ev.gitHubInstallationID != 0 {
+			gh.GitHubAppInstallationID = strconv.FormatInt(ev.gitHubInstallationID, 10)
+		}
+	case platform.TypeGitLab:
+		gl := &cfg.GitLab
+		gl.GitLabProjectID = ev.gitLabProjectID
+		gl.GitLabMergeRequestIID, gl.GitLabMergeRequestDescription = 0, ""
+		gl.GitLabIssueIID, gl.GitLabIssueDescription = 0, ""
+		gl.GitLabCommitSHA = ev.sha
+		if ev.kind == platform.ItemKindRequest {
+			gl.GitLabMergeRequestIID, gl.GitLabMergeRequestDescription = ev.number, ev.body
+		} else {
+			gl.GitLabIssueIID, gl.GitLabIssueDescription = ev.number, ev.body
+		}
+	default:
+		return nil, fmt.Errorf("unsupported platform %q", ev.platform)
+	}
+
+	if c.newPlatform != nil {
+		return c.newPlatform(ctx, &cfg)
+	}
+	return platform.NewPlatform(ctx, &cfg) //nolint:wrapcheck // Want passthrough
+}
+
+// forward posts the parsed tags of the event to the forward URL.
+func (c *ServeCommand) forward(ctx context.Context, ev *event) error {
+	values, err := c.tagParser.ParseTagValues(ctx, ev.body)
+	if err != nil {
+		return fmt.Errorf("failed to parse tags: %w", err)
+	}
+
+	b, err := json.Marshal(&forwardPayload{
+		Platform:   ev.platform,
+		Type:       string(ev.kind),
+		Action:     ev.action,
+		Repository: ev.repository,
+		Number:     ev.number,
+		URL:        ev.url,
+		Tags:       values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, forwardTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.FlagForwardURL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
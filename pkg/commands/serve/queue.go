@@ -0,0 +1,96 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+// queue processes events in the background, so webhooks are answered before
+// their actions run. GitHub times out webhooks after 10 seconds and GitLab
+// disables webhooks that keep failing.
+//
+// Events act on the body in their payload, so the events of an item are
+// processed by the same worker in the order they were queued. Otherwise an
+// older event could finish last and leave stale labels, statuses or comments.
+type queue struct {
+	// slots limits the number of events waiting or being processed.
+	slots   chan struct{}
+	workers []chan *event
+	wg      sync.WaitGroup
+}
+
+// newQueue starts workers that process the events of a queue of at most size
+// events with process. Failures are logged. The workers keep running when ctx
+// is canceled, until close is called.
+func newQueue(ctx context.Context, size, workers int, process func(ctx context.Context, ev *event) error) *queue {
+	logger := logging.FromContext(ctx)
+	ctx = context.WithoutCancel(ctx)
+
+	q := &queue{
+		slots:   make(chan struct{}, size),
+		workers: make([]chan *event, workers),
+	}
+	for i := range q.workers {
+		// Each worker can hold all the events, the slots limit the total.
+		events := make(chan *event, size)
+		q.workers[i] = events
+
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for ev := range events {
+				if err := process(ctx, ev); err != nil {
+					logger.ErrorContext(ctx, "failed to process webhook event",
+						"platform", ev.platform,
+						"repository", ev.repository,
+						"number", ev.number,
+						"error", err)
+				}
+				<-q.slots
+			}
+		}()
+	}
+	return q
+}
+
+// enqueue adds ev to the queue of the worker of its item, and returns false if
+// the queue is full.
+func (q *queue) enqueue(ev *event) bool {
+	select {
+	case q.slots <- struct{}{}:
+	default:
+		return false
+	}
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d", ev.platform, ev.repository, ev.kind, ev.number)
+	q.workers[h.Sum32()%uint32(len(q.workers))] <- ev
+	return true
+}
+
+// close stops accepting events and waits for the queued events to be
+// processed.
+func (q *queue) close() {
+	for _, events := range q.workers {
+		close(events)
+	}
+	q.wg.Wait()
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/pkg/platform"
+)
+
+func TestQueue_Order(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	var mu sync.Mutex
+	var got []string
+	var running, maxRunning int
+	q := newQueue(ctx, 10, 4, func(ctx context.Context, ev *event) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+
+		// The first edit is slower, so it finishes last if the edits run at the
+		// same time.
+		if ev.body == "WANT_LGTM=any" {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		mu.Lock()
+		running--
+		got = append(got, ev.body)
+		mu.Unlock()
+		return nil
+	})
+
+	for _, body := range []string{"WANT_LGTM=any", "WANT_LGTM=all"} {
+		if !q.enqueue(&event{
+			platform:   platform.TypeGitHub,
+			kind:       platform.ItemKindRequest,
+			action:     "edited",
+			repository: "octo-org/widgets",
+			number:     7,
+			body:       body,
+		}) {
+			t.Fatal("expected event to be queued")
+		}
+	}
+	q.close()
+
+	if diff := cmp.Diff(got, []string{"WANT_LGTM=any", "WANT_LGTM=all"}); diff != "" {
+		t.Errorf("processed events not as expected; (-got,+want): %s", diff)
+	}
+	if maxRunning != 1 {
+		t.Errorf("expected the events of one item to be processed one at a time, got %d at the same time", maxRunning)
+	}
+}
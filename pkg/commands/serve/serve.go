@@ -0,0 +1,326 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package serve receives GitHub and GitLab webhooks and acts on the tags of
+// the pull requests, merge requests and issues they are about.
+package serve
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/healthcheck"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/serving"
+	"github.com/abcxyz/tagrep/internal/metricswrap"
+	"github.com/abcxyz/tagrep/pkg/commands/labels"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+const (
+	gitHubWebhookPath = "/webhooks/github"
+	gitLabWebhookPath = "/webhooks/gitlab"
+	healthCheckPath   = "/healthz"
+
+	// forwardTimeout is the timeout of forwarding the tags of an event.
+	forwardTimeout = 10 * time.Second
+
+	defaultQueueSize = 100
+	defaultWorkers   = 4
+)
+
+var _ cli.Command = (*ServeCommand)(nil)
+
+// ServeCommand receives webhooks and dispatches the tags of their requests
+// and issues to the configured actions.
+type ServeCommand struct {
+	cli.BaseCommand
+
+	platformConfig platform.Config
+	tagsConfig     tags.Config
+
+	tagParser  tags.TagParser
+	labelRules []*labels.Rule
+	httpClient *http.Client
+	queue      *queue
+
+	// newPlatform creates the platform of an event, platform.NewPlatform when
+	// nil.
+	newPlatform func(ctx context.Context, cfg *platform.Config) (platform.Platform, error)
+
+	FlagPort                string
+	FlagGitHubWebhookSecret string
+	FlagGitLabWebhookToken  string
+	FlagLabelMap            []string
+	FlagSummaryComment      bool
+	FlagPublishStatus       bool
+	FlagStatusName          string
+	FlagForwardURL          string
+	FlagQueueSize           int
+	FlagWorkers             int
+}
+
+// Desc provides a short, one-line description of the command.
+func (c *ServeCommand) Desc() string {
+	return "Serve webhooks to act on tags as they change"
+}
+
+// Help is the long-form help output to include usage instructions and flag
+// information.
+func (c *ServeCommand) Help() string {
+	return `
+Usage: {{ COMMAND }} [options]
+
+	Receive GitHub webhooks on /webhooks/github and GitLab webhooks on
+	/webhooks/gitlab, and act on the tags of the pull requests, merge requests
+	and issues that are opened or edited, instead of running a CI job for each:
+
+	tagrep serve -github-webhook-secret=... -label-map="WANT_LGTM=lgtm:*"
+
+	GitHub webhooks are verified with their HMAC signature and GitLab webhooks
+	with their secret token. A platform is only served when its secret is set.
+	Verified events are accepted right away and processed in the background.
+`
+}
+
+func (c *ServeCommand) FlagsContext(ctx context.Context) *cli.FlagSet {
+	set := c.NewFlagSet()
+
+	c.platformConfig.RegisterFlagsContext(ctx, set)
+	c.tagsConfig.RegisterFlags(set)
+
+	f := set.NewSection("SERVE OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "port",
+		EnvVar:  "PORT",
+		Target:  &c.FlagPort,
+		Default: "8080",
+		Usage:   "The port to serve webhooks on.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "github-webhook-secret",
+		EnvVar:  "TAGREP_GITHUB_WEBHOOK_SECRET",
+		Target:  &c.FlagGitHubWebhookSecret,
+		Example: "my-secret",
+		Usage: "The secret of the GitHub webhook, used to verify the signature of " +
+			"its payloads. GitHub webhooks are rejected if empty.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitlab-webhook-token",
+		EnvVar:  "TAGREP_GITLAB_WEBHOOK_TOKEN",
+		Target:  &c.FlagGitLabWebhookToken,
+		Example: "my-token",
+		Usage: "The secret token of the GitLab webhook, used to verify its payloads. " +
+			"GitLab webhooks are rejected if empty.",
+	})
+
+	f.StringSliceVar(&cli.StringSliceVar{
+		Name:    "label-map",
+		Target:  &c.FlagLabelMap,
+		Example: "WANT_LGTM=lgtm:*",
+		Usage: "Mapping from tag values to a label to sync on each event, repeat for " +
+			"multiple mappings. Uses the syntax of the sync-labels command.",
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "summary-comment",
+		Target:  &c.FlagSummaryComment,
+		Default: false,
+		Usage: "Whether to create or update a comment on each request with a table of " +
+			"the recognized tags and their errors.",
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "publish-status",
+		Target:  &c.FlagPublishStatus,
+		Default: false,
+		Usage: "Whether to publish the validation result of the tags of each request as " +
+			"a GitHub check run or GitLab commit status on its head commit.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "status-name",
+		Target:  &c.FlagStatusName,
+		Default: "tagrep",
+		Usage:   "The name of the status published with -publish-status.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "forward-url",
+		EnvVar:  "TAGREP_FORWARD_URL",
+		Target:  &c.FlagForwardURL,
+		Example: "https://example.com/tags",
+		Usage: "The URL to POST the parsed tags of each event to as a JSON object " +
+			"with the platform, type, repository, number and url of the event.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "queue-size",
+		Target:  &c.FlagQueueSize,
+		Default: defaultQueueSize,
+		Usage: "The maximum number of events waiting or being processed. Webhooks " +
+			"are answered with 503 when the queue is full.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "workers",
+		Target:  &c.FlagWorkers,
+		Default: defaultWorkers,
+		Usage: "The number of events to process at the same time. The events of " +
+			"one item are processed one at a time, in the order they were received.",
+	})
+
+	set.AfterParse(func(merr error) error {
+		if c.FlagGitHubWebhookSecret == "" && c.FlagGitLabWebhookToken == "" {
+			merr = errors.Join(merr, fmt.Errorf("one of github-webhook-secret or gitlab-webhook-token is required"))
+		}
+
+		if len(c.FlagLabelMap) == 0 && !c.FlagSummaryComment && !c.FlagPublishStatus && c.FlagForwardURL == "" {
+			merr = errors.Join(merr, fmt.Errorf("at least one of label-map, summary-comment, publish-status or forward-url is required"))
+		}
+
+		rules, err := labels.ParseRules(c.FlagLabelMap)
+		if err != nil {
+			merr = errors.Join(merr, err)
+		}
+		c.labelRules = rules
+
+		if err := labels.ValidateRules(rules, &c.tagsConfig); err != nil {
+			merr = errors.Join(merr, err)
+		}
+
+		if c.FlagPublishStatus && strings.TrimSpace(c.FlagStatusName) == "" {
+			merr = errors.Join(merr, fmt.Errorf("status-name flag is required with publish-status"))
+		}
+
+		if c.FlagForwardURL != "" {
+			if u, err := url.Parse(c.FlagForwardURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				merr = errors.Join(merr, fmt.Errorf("forward-url flag must be an http or https URL: %s", c.FlagForwardURL))
+			}
+		}
+
+		if c.FlagQueueSize < 1 {
+			merr = errors.Join(merr, fmt.Errorf("queue-size must be at least 1"))
+		}
+		if c.FlagWorkers < 1 {
+			merr = errors.Join(merr, fmt.Errorf("workers must be at least 1"))
+		}
+
+		return merr
+	})
+
+	return set
+}
+
+func (c *ServeCommand) Run(ctx context.Context, args []string) error {
+	metricswrap.WriteMetric(ctx, "command_serve", 1)
+
+	f := c.FlagsContext(ctx)
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	parsedArgs := f.Args()
+	if len(parsedArgs) > 0 {
+		return flag.ErrHelp
+	}
+
+	c.tagParser = tags.NewTagParser(ctx, &c.tagsConfig)
+
+	server, err := serving.New(c.FlagPort)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	// Finish the queued events when the server stops.
+	c.queue = newQueue(ctx, c.FlagQueueSize, c.FlagWorkers, c.processEvent)
+	defer c.queue.close()
+
+	if err := server.StartHTTPHandler(ctx, c.Handler(ctx)); err != nil {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the handler of the webhooks and the health check. Events are
+// added to the queue started by Run.
+func (c *ServeCommand) Handler(ctx context.Context) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(healthCheckPath, healthcheck.HandleHTTPHealthCheck())
+	mux.Handle("POST "+gitHubWebhookPath, c.handleWebhook(ctx, c.FlagGitHubWebhookSecret, parseGitHubWebhook))
+	mux.Handle("POST "+gitLabWebhookPath, c.handleWebhook(ctx, c.FlagGitLabWebhookToken, parseGitLabWebhook))
+	return mux
+}
+
+// handleWebhook verifies and parses webhooks with parse, and queues their
+// events. Webhooks are rejected if secret is empty.
+func (c *ServeCommand) handleWebhook(ctx context.Context, secret string, parse func(r *http.Request, secret string) (*event, error)) http.Handler {
+	logger := logging.FromContext(ctx)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.WithLogger(r.Context(), logger)
+
+		if secret == "" {
+			http.Error(w, "webhooks of this platform are not enabled", http.StatusNotFound)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxPayloadBytes)
+		ev, err := parse(r, secret)
+		if errors.Is(err, errUnauthorized) {
+			logger.WarnContext(ctx, "rejected webhook", "path", r.URL.Path, "error", err)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			logger.WarnContext(ctx, "invalid webhook", "path", r.URL.Path, "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if ev == nil {
+			logger.DebugContext(ctx, "ignored webhook", "path", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if !c.queue.enqueue(ev) {
+			logger.ErrorContext(ctx, "dropped webhook event, the queue is full",
+				"platform", ev.platform,
+				"repository", ev.repository,
+				"number", ev.number)
+			http.Error(w, "too many webhook events", http.StatusServiceUnavailable)
+			return
+		}
+
+		metricswrap.WriteMetric(ctx, "serve_events", 1)
+		logger.InfoContext(ctx, "queued webhook event",
+			"platform", ev.platform,
+			"type", ev.kind,
+			"action", ev.action,
+			"repository", ev.repository,
+			"number", ev.number)
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
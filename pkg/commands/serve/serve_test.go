@@ -0,0 +1,316 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/tagrep/pkg/commands/labels"
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func TestServe_Handler(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name            string
+		req             *http.Request
+		labelMap        []string
+		summaryComment  bool
+		publishStatus   bool
+		forward         bool
+		forwardStatus   int
+		gitLabToken     string
+		mockPlatform    *platform.MockPlatform
+		expStatus       int
+		expPlatformReqs []string
+		expLabelReqs    []*platform.Request
+		expConfig       func(cfg *platform.Config) any
+		expConfigValue  any
+		expForwarded    []*forwardPayload
+	}{
+		{
+			name:           "github_pull_request_all_actions",
+			req:            newGitHubRequest(t, "pull_request", "github_pull_request_opened.json", "secret"),
+			labelMap:       []string{"WANT_LGTM=lgtm:*"},
+			summaryComment: true,
+			publishStatus:  true,
+			forward:        true,
+			mockPlatform: &platform.MockPlatform{
+				ListLabelsResponse: []string{"bug", "lgtm:some"},
+			},
+			expStatus:       http.StatusAccepted,
+			expPlatformReqs: []string{"ListLabels", "AddLabels", "RemoveLabels", "UpsertRequestComment", "PublishRequestStatus"},
+			expLabelReqs: []*platform.Request{
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:all"}}},
+				{Name: "RemoveLabels", Params: []any{platform.ItemKindRequest, []string{"lgtm:some"}}},
+			},
+			expConfig: func(cfg *platform.Config) any {
+				return []any{
+					cfg.Type,
+					cfg.GitHub.GitHubOwner,
+					cfg.GitHub.GitHubRepo,
+					cfg.GitHub.GitHubPullRequestNumber,
+					cfg.GitHub.GitHubIssueNumber,
+					cfg.GitHub.GitHubSHA,
+					cfg.GitHub.GitHubAppInstallationID,
+				}
+			},
+			expConfigValue: []any{"github", "octo-org", "widgets", 7, 0, "6dcb09b5b57875f334f61aebed695e2e4193db5e", "4242"},
+			expForwarded: []*forwardPayload{{
+				Platform:   "github",
+				Type:       "request",
+				Action:     "opened",
+				Repository: "octo-org/widgets",
+				Number:     7,
+				URL:        "https://github.com/octo-org/widgets/pull/7",
+				Tags: map[string]any{
+					"REVIEWERS": []any{"bob", "carol"},
+					"WANT_LGTM": "all",
+					"DRAFT":     false,
+				},
+			}},
+		},
+		{
+			name:           "gitlab_issue_skips_request_actions",
+			req:            newGitLabRequest(t, "Issue Hook", "gitlab_issue_open.json", "token"),
+			labelMap:       []string{"PRIORITY:p1=urgent"},
+			summaryComment: true,
+			publishStatus:  true,
+			gitLabToken:    "token",
+			mockPlatform:   &platform.MockPlatform{},
+			expStatus:      http.StatusAccepted,
+			expPlatformReqs: []string{
+				"ListLabels",
+				"AddLabels",
+			},
+			expLabelReqs: []*platform.Request{
+				{Name: "ListLabels", Params: []any{platform.ItemKindIssue}},
+				{Name: "AddLabels", Params: []any{platform.ItemKindIssue, []string{"urgent"}}},
+			},
+			expConfig: func(cfg *platform.Config) any {
+				return []any{
+					cfg.Type,
+					cfg.GitLab.GitLabProjectID,
+					cfg.GitLab.GitLabMergeRequestIID,
+					cfg.GitLab.GitLabIssueIID,
+					cfg.GitLab.GitLabIssueDescription,
+				}
+			},
+			expConfigValue: []any{"gitlab", 278964, 0, 23, "Please grant access.\n\nPRIORITY=p1\n"},
+		},
+		{
+			name:         "ignored_event",
+			req:          newGitHubRequest(t, "pull_request", "github_pull_request_edited_title.json", "secret"),
+			labelMap:     []string{"WANT_LGTM=lgtm:*"},
+			forward:      true,
+			mockPlatform: &platform.MockPlatform{},
+			expStatus:    http.StatusNoContent,
+		},
+		{
+			name:         "invalid_signature",
+			req:          newGitHubRequest(t, "pull_request", "github_pull_request_opened.json", "other"),
+			labelMap:     []string{"WANT_LGTM=lgtm:*"},
+			mockPlatform: &platform.MockPlatform{},
+			expStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:         "platform_not_enabled",
+			req:          newGitLabRequest(t, "Issue Hook", "gitlab_issue_open.json", ""),
+			labelMap:     []string{"PRIORITY:p1=urgent"},
+			mockPlatform: &platform.MockPlatform{},
+			expStatus:    http.StatusNotFound,
+		},
+		{
+			name:     "failed_action_runs_others",
+			req:      newGitHubRequest(t, "pull_request", "github_pull_request_opened.json", "secret"),
+			labelMap: []string{"WANT_LGTM=lgtm:*"},
+			forward:  true,
+			mockPlatform: &platform.MockPlatform{
+				ListLabelsErr: fmt.Errorf("boom"),
+			},
+			expStatus:       http.StatusAccepted,
+			expPlatformReqs: []string{"ListLabels"},
+			expLabelReqs: []*platform.Request{
+				{Name: "ListLabels", Params: []any{platform.ItemKindRequest}},
+			},
+			expForwarded: []*forwardPayload{{
+				Platform:   "github",
+				Type:       "request",
+				Action:     "opened",
+				Repository: "octo-org/widgets",
+				Number:     7,
+				URL:        "https://github.com/octo-org/widgets/pull/7",
+				Tags: map[string]any{
+					"REVIEWERS": []any{"bob", "carol"},
+					"WANT_LGTM": "all",
+					"DRAFT":     false,
+				},
+			}},
+		},
+		{
+			name:          "forward_error",
+			req:           newGitLabRequest(t, "Issue Hook", "gitlab_issue_open.json", "token"),
+			forward:       true,
+			forwardStatus: http.StatusBadGateway,
+			gitLabToken:   "token",
+			mockPlatform:  &platform.MockPlatform{},
+			expStatus:     http.StatusAccepted,
+			expForwarded: []*forwardPayload{{
+				Platform:   "gitlab",
+				Type:       "issue",
+				Action:     "open",
+				Repository: "octo-group/widgets",
+				Number:     23,
+				URL:        "https://gitlab.example.com/octo-group/widgets/-/issues/23",
+				Tags:       map[string]any{"PRIORITY": "p1"},
+			}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var forwarded []*forwardPayload
+			forwardSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var p forwardPayload
+				if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+					t.Error(err)
+				}
+				mu.Lock()
+				forwarded = append(forwarded, &p)
+				mu.Unlock()
+				if tc.forwardStatus != 0 {
+					w.WriteHeader(tc.forwardStatus)
+				}
+			}))
+			t.Cleanup(forwardSrv.Close)
+
+			rules, err := labels.ParseRules(tc.labelMap)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotConfig *platform.Config
+			c := &ServeCommand{
+				tagParser: tags.NewTagParser(ctx, &tags.Config{
+					ArrayTags:  []string{"REVIEWERS"},
+					StringTags: []string{"WANT_LGTM", "PRIORITY"},
+					BoolTags:   []string{"DRAFT"},
+				}),
+				labelRules: rules,
+				newPlatform: func(ctx context.Context, cfg *platform.Config) (platform.Platform, error) {
+					gotConfig = cfg
+					return tc.mockPlatform, nil
+				},
+				FlagGitHubWebhookSecret: "secret",
+				FlagGitLabWebhookToken:  tc.gitLabToken,
+				FlagSummaryComment:      tc.summaryComment,
+				FlagPublishStatus:       tc.publishStatus,
+				FlagStatusName:          "tagrep",
+			}
+			c.platformConfig.GitHub.GitHubAppID = "123"
+			if tc.forward {
+				c.FlagForwardURL = forwardSrv.URL
+			}
+
+			c.queue = newQueue(ctx, 1, 1, c.processEvent)
+
+			w := httptest.NewRecorder()
+			c.Handler(ctx).ServeHTTP(w, tc.req)
+
+			// Wait for the event to be processed.
+			c.queue.close()
+
+			if got, want := w.Code, tc.expStatus; got != want {
+				t.Errorf("expected status %d, got %d: %s", want, got, w.Body.String())
+			}
+
+			var gotReqs []string
+			var gotLabelReqs []*platform.Request
+			for _, r := range tc.mockPlatform.Reqs {
+				gotReqs = append(gotReqs, r.Name)
+				switch r.Name {
+				case "ListLabels", "AddLabels", "RemoveLabels":
+					gotLabelReqs = append(gotLabelReqs, r)
+				}
+			}
+			if diff := cmp.Diff(gotReqs, tc.expPlatformReqs); diff != "" {
+				t.Errorf("Platform calls not as expected; (-got,+want): %s", diff)
+			}
+			if diff := cmp.Diff(gotLabelReqs, tc.expLabelReqs); diff != "" {
+				t.Errorf("label calls not as expected; (-got,+want): %s", diff)
+			}
+
+			if tc.expConfig != nil {
+				if gotConfig == nil {
+					t.Fatal("expected a platform to be created")
+				}
+				if diff := cmp.Diff(tc.expConfig(gotConfig), tc.expConfigValue); diff != "" {
+					t.Errorf("platform config not as expected; (-got,+want): %s", diff)
+				}
+			}
+
+			if diff := cmp.Diff(forwarded, tc.expForwarded); diff != "" {
+				t.Errorf("forwarded tags not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestServe_HandlerQueueFull(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	c := &ServeCommand{
+		tagParser:               tags.NewTagParser(ctx, &tags.Config{}),
+		FlagGitHubWebhookSecret: "secret",
+	}
+	// The first event blocks the only slot of the queue until it is released.
+	release := make(chan struct{})
+	c.queue = newQueue(ctx, 1, 2, func(ctx context.Context, ev *event) error {
+		<-release
+		return nil
+	})
+
+	var got []int
+	for range 2 {
+		w := httptest.NewRecorder()
+		c.Handler(ctx).ServeHTTP(w, newGitHubRequest(t, "pull_request", "github_pull_request_opened.json", "secret"))
+		got = append(got, w.Code)
+	}
+	close(release)
+	c.queue.close()
+
+	if diff := cmp.Diff(got, []int{http.StatusAccepted, http.StatusServiceUnavailable}); diff != "" {
+		t.Errorf("statuses not as expected; (-got,+want): %s", diff)
+	}
+}
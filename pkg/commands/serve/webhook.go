@@ -0,0 +1,184 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/google/go-github/v53/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/abcxyz/tagrep/pkg/platform"
+)
+
+const (
+	// maxPayloadBytes is the size of the largest payload GitHub sends.
+	maxPayloadBytes = 25 << 20
+
+	gitLabTokenHeader = "X-Gitlab-Token"
+)
+
+// errUnauthorized is returned for webhooks that fail verification.
+var errUnauthorized = errors.New("webhook verification failed")
+
+var (
+	// gitHubActions are the actions of pull_request and issues events that can
+	// change the tags or, for pull requests, the head commit.
+	gitHubActions = []string{"opened", "edited", "reopened", "synchronize", "ready_for_review"}
+
+	// gitLabActions are the actions of merge request and issue events that can
+	// change the tags or, for merge requests, the head commit.
+	gitLabActions = []string{"open", "reopen", "update"}
+)
+
+// event is a Pull Request, Merge Request or issue event to process.
+type event struct {
+	// platform is platform.TypeGitHub or platform.TypeGitLab.
+	platform string
+	kind     platform.ItemKind
+	action   string
+	// repository is owner/repo on GitHub and the project path on GitLab.
+	repository string
+	number     int
+	url        string
+	body       string
+	// sha is the head commit of a Pull Request or Merge Request.
+	sha string
+
+	gitHubOwner          string
+	gitHubRepo           string
+	gitHubInstallationID int64
+
+	gitLabProjectID int
+}
+
+// parseGitHubWebhook verifies the signature of a GitHub webhook and returns
+// its event. It returns nil for events that cannot change the tags.
+func parseGitHubWebhook(r *http.Request, secret string) (*event, error) {
+	payload, err := github.ValidatePayload(r, []byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnauthorized, err)
+	}
+
+	raw, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github webhook: %w", err)
+	}
+
+	switch e := raw.(type) {
+	case *github.PullRequestEvent:
+		if !slices.Contains(gitHubActions, e.GetAction()) ||
+			e.GetAction() == "edited" && e.GetChanges().GetBody() == nil {
+			return nil, nil
+		}
+		pr := e.GetPullRequest()
+		return &event{
+			platform:             platform.TypeGitHub,
+			kind:                 platform.ItemKindRequest,
+			action:               e.GetAction(),
+			repository:           e.GetRepo().GetFullName(),
+			number:               pr.GetNumber(),
+			url:                  pr.GetHTMLURL(),
+			body:                 pr.GetBody(),
+			sha:                  pr.GetHead().GetSHA(),
+			gitHubOwner:          e.GetRepo().GetOwner().GetLogin(),
+			gitHubRepo:           e.GetRepo().GetName(),
+			gitHubInstallationID: e.GetInstallation().GetID(),
+		}, nil
+	case *github.IssuesEvent:
+		if !slices.Contains(gitHubActions, e.GetAction()) ||
+			e.GetAction() == "edited" && e.GetChanges().GetBody() == nil {
+			return nil, nil
+		}
+		issue := e.GetIssue()
+		return &event{
+			platform:             platform.TypeGitHub,
+			kind:                 platform.ItemKindIssue,
+			action:               e.GetAction(),
+			repository:           e.GetRepo().GetFullName(),
+			number:               issue.GetNumber(),
+			url:                  issue.GetHTMLURL(),
+			body:                 issue.GetBody(),
+			gitHubOwner:          e.GetRepo().GetOwner().GetLogin(),
+			gitHubRepo:           e.GetRepo().GetName(),
+			gitHubInstallationID: e.GetInstallation().GetID(),
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// parseGitLabWebhook verifies the secret token of a GitLab webhook and returns
+// its event. It returns nil for events that cannot change the tags.
+func parseGitLabWebhook(r *http.Request, token string) (*event, error) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(gitLabTokenHeader)), []byte(token)) != 1 {
+		return nil, fmt.Errorf("%w: invalid %s header", errUnauthorized, gitLabTokenHeader)
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gitlab webhook: %w", err)
+	}
+
+	raw, err := gitlab.ParseWebhook(gitlab.HookEventType(r), payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gitlab webhook: %w", err)
+	}
+
+	switch e := raw.(type) {
+	case *gitlab.MergeEvent:
+		attrs := e.ObjectAttributes
+		// Updates also include changes such as labels, which would otherwise make
+		// the labels set for the tags trigger another run.
+		if !slices.Contains(gitLabActions, attrs.Action) ||
+			attrs.Action == "update" && attrs.OldRev == "" && e.Changes.Description.Previous == e.Changes.Description.Current {
+			return nil, nil
+		}
+		return &event{
+			platform:        platform.TypeGitLab,
+			kind:            platform.ItemKindRequest,
+			action:          attrs.Action,
+			repository:      e.Project.PathWithNamespace,
+			number:          attrs.IID,
+			url:             attrs.URL,
+			body:            attrs.Description,
+			sha:             attrs.LastCommit.ID,
+			gitLabProjectID: e.Project.ID,
+		}, nil
+	case *gitlab.IssueEvent:
+		attrs := e.ObjectAttributes
+		if !slices.Contains(gitLabActions, attrs.Action) ||
+			attrs.Action == "update" && e.Changes.Description.Previous == e.Changes.Description.Current {
+			return nil, nil
+		}
+		return &event{
+			platform:        platform.TypeGitLab,
+			kind:            platform.ItemKindIssue,
+			action:          attrs.Action,
+			repository:      e.Project.PathWithNamespace,
+			number:          attrs.IID,
+			url:             attrs.URL,
+			body:            attrs.Description,
+			gitLabProjectID: e.Project.ID,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
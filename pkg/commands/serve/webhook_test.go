@@ -0,0 +1,226 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serve
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/abcxyz/tagrep/pkg/platform"
+)
+
+// newGitHubRequest returns a GitHub webhook request for the recorded payload,
+// signed with secret.
+func newGitHubRequest(tb testing.TB, eventType, payloadFile, secret string) *http.Request {
+	tb.Helper()
+
+	payload := readPayload(tb, payloadFile)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	r := httptest.NewRequest(http.MethodPost, gitHubWebhookPath, bytes.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-GitHub-Event", eventType)
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+// newGitLabRequest returns a GitLab webhook request for the recorded payload,
+// with the secret token.
+func newGitLabRequest(tb testing.TB, eventType, payloadFile, token string) *http.Request {
+	tb.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, gitLabWebhookPath, bytes.NewReader(readPayload(tb, payloadFile)))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Gitlab-Event", eventType)
+	r.Header.Set(gitLabTokenHeader, token)
+	return r
+}
+
+func readPayload(tb testing.TB, name string) []byte {
+	tb.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return b
+}
+
+func TestParseGitHubWebhook(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		req       *http.Request
+		exp       *event
+		err       string
+		expUnauth bool
+	}{
+		{
+			name: "pull_request_opened",
+			req:  newGitHubRequest(t, "pull_request", "github_pull_request_opened.json", "secret"),
+			exp: &event{
+				platform:             platform.TypeGitHub,
+				kind:                 platform.ItemKindRequest,
+				action:               "opened",
+				repository:           "octo-org/widgets",
+				number:               7,
+				url:                  "https://github.com/octo-org/widgets/pull/7",
+				body:                 "Adds a cache for widgets.\r\n\r\nWANT_LGTM=all\r\nREVIEWERS=bob\r\nREVIEWERS=carol\r\nDRAFT=no\r\n",
+				sha:                  "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+				gitHubOwner:          "octo-org",
+				gitHubRepo:           "widgets",
+				gitHubInstallationID: 4242,
+			},
+		},
+		{
+			name: "issues_edited",
+			req:  newGitHubRequest(t, "issues", "github_issues_edited.json", "secret"),
+			exp: &event{
+				platform:    platform.TypeGitHub,
+				kind:        platform.ItemKindIssue,
+				action:      "edited",
+				repository:  "octo-org/widgets",
+				number:      12,
+				url:         "https://github.com/octo-org/widgets/issues/12",
+				body:        "Please grant access.\n\nPRIORITY=p1\n",
+				gitHubOwner: "octo-org",
+				gitHubRepo:  "widgets",
+			},
+		},
+		{
+			name: "pull_request_title_edited",
+			req:  newGitHubRequest(t, "pull_request", "github_pull_request_edited_title.json", "secret"),
+		},
+		{
+			name: "ping",
+			req:  newGitHubRequest(t, "ping", "github_ping.json", "secret"),
+		},
+		{
+			name:      "invalid_signature",
+			req:       newGitHubRequest(t, "pull_request", "github_pull_request_opened.json", "other"),
+			err:       "webhook verification failed",
+			expUnauth: true,
+		},
+		{
+			name: "unknown_event",
+			req:  newGitHubRequest(t, "not_an_event", "github_ping.json", "secret"),
+			err:  "failed to parse github webhook",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseGitHubWebhook(tc.req, "secret")
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if got, want := errors.Is(err, errUnauthorized), tc.expUnauth; got != want {
+				t.Errorf("expected unauthorized to be %t, got %t", want, got)
+			}
+			if diff := cmp.Diff(got, tc.exp, cmp.AllowUnexported(event{})); diff != "" {
+				t.Errorf("event not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseGitLabWebhook(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		req       *http.Request
+		exp       *event
+		err       string
+		expUnauth bool
+	}{
+		{
+			name: "merge_request_description_updated",
+			req:  newGitLabRequest(t, "Merge Request Hook", "gitlab_merge_request_update.json", "token"),
+			exp: &event{
+				platform:        platform.TypeGitLab,
+				kind:            platform.ItemKindRequest,
+				action:          "update",
+				repository:      "octo-group/widgets",
+				number:          7,
+				url:             "https://gitlab.example.com/octo-group/widgets/-/merge_requests/7",
+				body:            "Adds a cache for widgets.\n\nWANT_LGTM=any\nREVIEWERS=bob\n",
+				sha:             "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+				gitLabProjectID: 278964,
+			},
+		},
+		{
+			name: "issue_opened",
+			req:  newGitLabRequest(t, "Issue Hook", "gitlab_issue_open.json", "token"),
+			exp: &event{
+				platform:        platform.TypeGitLab,
+				kind:            platform.ItemKindIssue,
+				action:          "open",
+				repository:      "octo-group/widgets",
+				number:          23,
+				url:             "https://gitlab.example.com/octo-group/widgets/-/issues/23",
+				body:            "Please grant access.\n\nPRIORITY=p1\n",
+				gitLabProjectID: 278964,
+			},
+		},
+		{
+			name: "merge_request_labels_updated",
+			req:  newGitLabRequest(t, "Merge Request Hook", "gitlab_merge_request_update_labels.json", "token"),
+		},
+		{
+			name:      "invalid_token",
+			req:       newGitLabRequest(t, "Merge Request Hook", "gitlab_merge_request_update.json", "other"),
+			err:       "webhook verification failed: invalid X-Gitlab-Token header",
+			expUnauth: true,
+		},
+		{
+			name: "unknown_event",
+			req:  newGitLabRequest(t, "Not An Event Hook", "gitlab_issue_open.json", "token"),
+			err:  "failed to parse gitlab webhook",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseGitLabWebhook(tc.req, "token")
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if got, want := errors.Is(err, errUnauthorized), tc.expUnauth; got != want {
+				t.Errorf("expected unauthorized to be %t, got %t", want, got)
+			}
+			if diff := cmp.Diff(got, tc.exp, cmp.AllowUnexported(event{})); diff != "" {
+				t.Errorf("event not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,704 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+	"golang.org/x/net/html"
+
+	"github.com/abcxyz/pkg/cli"
+)
+
+const (
+	// azureDevOpsAPIVersion is the REST API version sent with every request.
+	azureDevOpsAPIVersion = "7.1"
+
+	// azureDevOpsPageSize is the number of commits requested per page.
+	azureDevOpsPageSize = 100
+
+	// azureDevOpsWorkItemBatchSize is the maximum number of work items that can
+	// be fetched in a single batch request.
+	azureDevOpsWorkItemBatchSize = 200
+)
+
+var (
+	_ Platform          = (*AzureDevOps)(nil)
+	_ RangeLister       = (*AzureDevOps)(nil)
+	_ Searcher          = (*AzureDevOps)(nil)
+	_ LinkedIssueLister = (*AzureDevOps)(nil)
+	_ BodyUpdater       = (*AzureDevOps)(nil)
+
+	// azureDevOpsCommitSHARegexp matches full commit SHAs, any other revision
+	// is treated as a branch name.
+	azureDevOpsCommitSHARegexp = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+	// htmlWhitespaceReplacer normalizes whitespace in HTML text.
+	htmlWhitespaceReplacer = strings.NewReplacer("\r", " ", "\n", " ", "\t", " ", "\u00a0", " ")
+
+	// htmlBlockElements are the HTML elements that start a new line of text, br
+	// is handled separately since it always adds a line break.
+	htmlBlockElements = map[string]struct{}{
+		"div": {}, "p": {}, "li": {}, "tr": {}, "pre": {},
+		"h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {},
+		"ul": {}, "ol": {}, "table": {}, "blockquote": {}, "hr": {},
+	}
+)
+
+// AzureDevOps implements the Platform interface for Azure Repos and Azure
+// Boards.
+type AzureDevOps struct {
+	cfg    *AzureDevOpsConfig
+	client *restClient
+}
+
+// AzureDevOpsConfig is the config values for the Azure DevOps client.
+type AzureDevOpsConfig struct {
+	// Retry
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+
+	// Auth
+	AzureDevOpsToken string
+
+	AzureDevOpsOrganizationURL string
+	AzureDevOpsProject         string
+	AzureDevOpsRepository      string
+	AzureDevOpsPullRequestID   int
+	AzureDevOpsWorkItemID      int
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
+}
+
+type azureDevOpsPredefinedConfig struct {
+	OrganizationURL string
+	Project         string
+	Repository      string
+	PullRequestID   int
+}
+
+// Load retrieves the predefined Azure Pipelines variables from the
+// environment. See
+// https://learn.microsoft.com/en-us/azure/devops/pipelines/build/variables.
+func (c *azureDevOpsPredefinedConfig) Load(getenv func(string) string) {
+	if v := getenv("SYSTEM_COLLECTIONURI"); v != "" {
+		c.OrganizationURL = v
+	}
+
+	if v := getenv("SYSTEM_TEAMPROJECT"); v != "" {
+		c.Project = v
+	}
+
+	// The repository name is only meaningful for Azure Repos, pipelines can
+	// also build repositories hosted elsewhere.
+	if getenv("BUILD_REPOSITORY_PROVIDER") == "TfsGit" {
+		c.Repository = getenv("BUILD_REPOSITORY_NAME")
+	}
+
+	if v, err := strconv.Atoi(getenv("SYSTEM_PULLREQUEST_PULLREQUESTID")); err == nil {
+		c.PullRequestID = v
+	}
+}
+
+func (c *AzureDevOpsConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	f := set.NewSection("AZURE DEVOPS OPTIONS")
+
+	cfgDefaults := &azureDevOpsPredefinedConfig{}
+	cfgDefaults.Load(set.GetEnv)
+
+	f.StringVar(&cli.StringVar{
+		Name:   "azure-devops-token",
+		EnvVar: "AZURE_DEVOPS_TOKEN",
+		Target: &c.AzureDevOpsToken,
+		// System.AccessToken is only exposed to scripts when it is mapped
+		// explicitly, by convention as SYSTEM_ACCESSTOKEN.
+		Default: set.GetEnv("SYSTEM_ACCESSTOKEN"),
+		Usage:   "The Azure DevOps personal access token or System.AccessToken to make API calls.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "azure-devops-organization-url",
+		Target:  &c.AzureDevOpsOrganizationURL,
+		Default: cfgDefaults.OrganizationURL,
+		Example: "https://dev.azure.com/organization-name",
+		Usage:   "The URL of the Azure DevOps organization or Azure DevOps Server collection.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "azure-devops-project",
+		Target:  &c.AzureDevOpsProject,
+		Default: cfgDefaults.Project,
+		Example: "project-name",
+		Usage:   "The Azure DevOps project.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "azure-devops-repository",
+		Target:  &c.AzureDevOpsRepository,
+		Default: cfgDefaults.Repository,
+		Example: "repository-name",
+		Usage:   "The Azure Repos repository name or ID.",
+		Hidden:  true,
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "azure-devops-pull-request-id",
+		EnvVar:  "AZURE_DEVOPS_PULL_REQUEST_ID",
+		Target:  &c.AzureDevOpsPullRequestID,
+		Default: cfgDefaults.PullRequestID,
+		Usage:   "The Azure Repos pull request ID.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:   "azure-devops-work-item-id",
+		EnvVar: "AZURE_DEVOPS_WORK_ITEM_ID",
+		Target: &c.AzureDevOpsWorkItemID,
+		Usage:  "The Azure Boards work item ID, parsed when the issue type is requested.",
+	})
+}
+
+// isAzurePipelines reports whether the environment is an Azure Pipelines run.
+func isAzurePipelines(getenv func(string) string) bool {
+	v, _ := strconv.ParseBool(getenv("TF_BUILD"))
+	return v
+}
+
+// NewAzureDevOps creates a new Azure DevOps client.
+func NewAzureDevOps(ctx context.Context, cfg *AzureDevOpsConfig) (*AzureDevOps, error) {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.InitialRetryDelay <= 0 {
+		cfg.InitialRetryDelay = 1 * time.Second
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = 20 * time.Second
+	}
+
+	if cfg.AzureDevOpsOrganizationURL == "" {
+		return nil, fmt.Errorf("azure devops organization url is required")
+	}
+
+	return &AzureDevOps{
+		cfg: cfg,
+		client: &restClient{
+			httpClient: cfg.httpClient,
+			baseURL:    strings.TrimSuffix(cfg.AzureDevOpsOrganizationURL, "/"),
+			auth: func(r *http.Request) {
+				// Both personal access tokens and System.AccessToken are accepted as
+				// the password of basic auth with an empty username.
+				if cfg.AzureDevOpsToken != "" {
+					r.SetBasicAuth("", cfg.AzureDevOpsToken)
+				}
+			},
+		},
+	}, nil
+}
+
+// azureDevOpsPullRequest is the subset of an Azure Repos pull request used by
+// tagrep.
+type azureDevOpsPullRequest struct {
+	PullRequestID int    `json:"pullRequestId"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Status        string `json:"status"`
+}
+
+// azureDevOpsWorkItem is the subset of an Azure Boards work item used by
+// tagrep.
+type azureDevOpsWorkItem struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Title       string `json:"System.Title"`
+		Description string `json:"System.Description"`
+	} `json:"fields"`
+}
+
+// GetRequestBody gets the Pull Request description.
+func (a *AzureDevOps) GetRequestBody(ctx context.Context) (string, error) {
+	if err := validateAzureDevOpsRepoInputs(a.cfg); err != nil {
+		return "", fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if a.cfg.AzureDevOpsPullRequestID <= 0 {
+		return "", fmt.Errorf("failed to validate inputs: azure devops pull request id is required")
+	}
+
+	var pr azureDevOpsPullRequest
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(ctx, http.MethodGet, a.repoPath("pullrequests", strconv.Itoa(a.cfg.AzureDevOpsPullRequestID)), nil, nil, &pr)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get pull request body: %w", err)
+	}
+
+	return pr.Description, nil
+}
+
+// GetIssueBody gets the description of the work item as plain text.
+func (a *AzureDevOps) GetIssueBody(ctx context.Context) (string, error) {
+	if err := validateAzureDevOpsInputs(a.cfg); err != nil {
+		return "", fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if a.cfg.AzureDevOpsWorkItemID <= 0 {
+		return "", fmt.Errorf("failed to validate inputs: azure devops work item id is required")
+	}
+
+	var workItem azureDevOpsWorkItem
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(ctx, http.MethodGet, a.projectPath("wit", "workitems", strconv.Itoa(a.cfg.AzureDevOpsWorkItemID)), nil, nil, &workItem)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get work item: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get work item body: %w", err)
+	}
+
+	return htmlToText(workItem.Fields.Description), nil
+}
+
+// ListRequestsInRange lists the completed Pull Requests associated with the
+// commits between base and head, ordered by ID. The revisions must be branch
+// names or full commit SHAs.
+func (a *AzureDevOps) ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error) {
+	if err := validateAzureDevOpsRepoInputs(a.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var shas []string
+	for skip := 0; ; skip += azureDevOpsPageSize {
+		q := url.Values{}
+		q.Set("searchCriteria.itemVersion.version", head)
+		q.Set("searchCriteria.itemVersion.versionType", azureDevOpsVersionType(head))
+		q.Set("searchCriteria.compareVersion.version", base)
+		q.Set("searchCriteria.compareVersion.versionType", azureDevOpsVersionType(base))
+		q.Set("searchCriteria.$top", strconv.Itoa(azureDevOpsPageSize))
+		q.Set("searchCriteria.$skip", strconv.Itoa(skip))
+
+		var result struct {
+			Value []struct {
+				CommitID string `json:"commitId"`
+			} `json:"value"`
+		}
+		if err := a.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := a.do(ctx, http.MethodGet, a.repoPath("commits"), q, nil, &result)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to list commits: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list commits: %w", err)
+		}
+
+		for _, c := range result.Value {
+			shas = append(shas, c.CommitID)
+		}
+		if len(result.Value) < azureDevOpsPageSize {
+			break
+		}
+	}
+
+	if len(shas) == 0 {
+		return []*Item{}, nil
+	}
+
+	// The merge commit of a pull request is matched by lastMergeCommit, while
+	// commit matches pull requests whose source branch contained the commit.
+	req := map[string]any{
+		"queries": []map[string]any{
+			{"type": "lastMergeCommit", "items": shas},
+			{"type": "commit", "items": shas},
+		},
+	}
+	var result struct {
+		Results []map[string][]*azureDevOpsPullRequest `json:"results"`
+	}
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(ctx, http.MethodPost, a.repoPath("pullrequestquery"), nil, req, &result)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to query pull requests: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	seen := make(map[int]struct{})
+	items := make([]*Item, 0, len(shas))
+	for _, r := range result.Results {
+		for _, prs := range r {
+			for _, pr := range prs {
+				if pr.Status != "completed" {
+					continue
+				}
+				if _, ok := seen[pr.PullRequestID]; ok {
+					continue
+				}
+				seen[pr.PullRequestID] = struct{}{}
+
+				items = append(items, &Item{
+					Number: pr.PullRequestID,
+					URL:    a.webURL("_git", a.cfg.AzureDevOpsRepository, "pullrequest", strconv.Itoa(pr.PullRequestID)),
+					Title:  pr.Title,
+					Body:   pr.Description,
+				})
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Number < items[j].Number
+	})
+
+	return items, nil
+}
+
+// SearchItems calls fn for every work item that matches the WIQL query, e.g.
+// `SELECT [System.Id] FROM WorkItems WHERE [System.State] = 'Active'`. See
+// https://learn.microsoft.com/en-us/azure/devops/boards/queries/wiql-syntax.
+func (a *AzureDevOps) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	if err := validateAzureDevOpsInputs(a.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	// WIQL only returns the IDs of the matching work items, the fields are
+	// fetched in batches afterwards.
+	var result struct {
+		WorkItems []struct {
+			ID int `json:"id"`
+		} `json:"workItems"`
+	}
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(ctx, http.MethodPost, a.projectPath("wit", "wiql"), nil, map[string]string{"query": query}, &result)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to query work items: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	ids := make([]int, 0, len(result.WorkItems))
+	for _, w := range result.WorkItems {
+		ids = append(ids, w.ID)
+	}
+	lastPage := (len(ids) + azureDevOpsWorkItemBatchSize - 1) / azureDevOpsWorkItemBatchSize
+
+	fn = serialize(fn)
+
+	if err := fetchPages(ctx, concurrency, func(ctx context.Context, page int) (int, error) {
+		start := (page - 1) * azureDevOpsWorkItemBatchSize
+		if start >= len(ids) {
+			return lastPage, nil
+		}
+		end := min(start+azureDevOpsWorkItemBatchSize, len(ids))
+
+		items, err := a.getWorkItems(ctx, ids[start:end])
+		if err != nil {
+			return 0, err
+		}
+
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return 0, err
+			}
+		}
+
+		return lastPage, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	return nil
+}
+
+// ListLinkedIssues lists the work items linked to the Pull Request.
+func (a *AzureDevOps) ListLinkedIssues(ctx context.Context) ([]*Item, error) {
+	if err := validateAzureDevOpsRepoInputs(a.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if a.cfg.AzureDevOpsPullRequestID <= 0 {
+		return nil, fmt.Errorf("failed to validate inputs: azure devops pull request id is required")
+	}
+
+	var result struct {
+		Value []struct {
+			ID string `json:"id"`
+		} `json:"value"`
+	}
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(ctx, http.MethodGet, a.repoPath("pullrequests", strconv.Itoa(a.cfg.AzureDevOpsPullRequestID), "workitems"), nil, nil, &result)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to list pull request work items: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list linked issues: %w", err)
+	}
+
+	items := make([]*Item, 0, len(result.Value))
+	for start := 0; start < len(result.Value); start += azureDevOpsWorkItemBatchSize {
+		end := min(start+azureDevOpsWorkItemBatchSize, len(result.Value))
+
+		ids := make([]int, 0, end-start)
+		for _, w := range result.Value[start:end] {
+			id, err := strconv.Atoi(w.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse work item id %q: %w", w.ID, err)
+			}
+			ids = append(ids, id)
+		}
+
+		batch, err := a.getWorkItems(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list linked issues: %w", err)
+		}
+		items = append(items, batch...)
+	}
+
+	return items, nil
+}
+
+// getWorkItems gets a batch of at most azureDevOpsWorkItemBatchSize work
+// items.
+func (a *AzureDevOps) getWorkItems(ctx context.Context, ids []int) ([]*Item, error) {
+	req := map[string]any{
+		"ids":    ids,
+		"fields": []string{"System.Title", "System.Description"},
+	}
+	var batch struct {
+		Value []*azureDevOpsWorkItem `json:"value"`
+	}
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(ctx, http.MethodPost, a.projectPath("wit", "workitemsbatch"), nil, req, &batch)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get work items: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get work items: %w", err)
+	}
+
+	items := make([]*Item, 0, len(batch.Value))
+	for _, w := range batch.Value {
+		items = append(items, &Item{
+			Number: w.ID,
+			URL:    a.webURL("_workitems", "edit", strconv.Itoa(w.ID)),
+			Title:  w.Fields.Title,
+			Body:   htmlToText(w.Fields.Description),
+		})
+	}
+	return items, nil
+}
+
+// UpdateRequestBody replaces the Pull Request description if it was not
+// changed since it was fetched.
+func (a *AzureDevOps) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	if err := validateAzureDevOpsRepoInputs(a.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if a.cfg.AzureDevOpsPullRequestID <= 0 {
+		return fmt.Errorf("failed to validate inputs: azure devops pull request id is required")
+	}
+	path := a.repoPath("pullrequests", strconv.Itoa(a.cfg.AzureDevOpsPullRequestID))
+
+	var pr azureDevOpsPullRequest
+	if err := a.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := a.do(withCacheRevalidation(ctx), http.MethodGet, path, nil, nil, &pr)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get pull request body: %w", err)
+	}
+
+	if err := compareAndSwapBody(pr.Description, oldBody, newBody, func() error {
+		return a.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := a.do(ctx, http.MethodPatch, path, nil, map[string]string{"description": newBody}, nil)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to update pull request: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to update pull request body: %w", err)
+	}
+	return nil
+}
+
+// UpdateIssueBody is not supported, work item descriptions are HTML and tagrep
+// only reads them as plain text.
+func (a *AzureDevOps) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	return fmt.Errorf("updating azure devops work items is not supported: %w", errors.ErrUnsupported)
+}
+
+// do sends a request with the API version required by Azure DevOps.
+func (a *AzureDevOps) do(ctx context.Context, method, path string, query url.Values, in, out any) (*http.Response, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsAPIVersion)
+	return a.client.do(ctx, method, path, query, in, out)
+}
+
+// projectPath returns the API path of the configured project joined with the
+// escaped path segments.
+func (a *AzureDevOps) projectPath(segments ...string) string {
+	parts := []string{url.PathEscape(a.cfg.AzureDevOpsProject), "_apis"}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
+
+// repoPath returns the API path of the configured repository joined with the
+// escaped path segments.
+func (a *AzureDevOps) repoPath(segments ...string) string {
+	return a.projectPath(append([]string{"git", "repositories", a.cfg.AzureDevOpsRepository}, segments...)...)
+}
+
+// webURL returns the web URL of the project joined with the escaped path
+// segments.
+func (a *AzureDevOps) webURL(segments ...string) string {
+	parts := []string{strings.TrimSuffix(a.cfg.AzureDevOpsOrganizationURL, "/"), url.PathEscape(a.cfg.AzureDevOpsProject)}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (a *AzureDevOps) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	backoff := retry.NewFibonacci(a.cfg.InitialRetryDelay)
+	backoff = retry.WithMaxRetries(retryLimit(a.cfg.MaxRetries), backoff)
+	backoff = retry.WithCappedDuration(a.cfg.MaxRetryDelay, backoff)
+
+	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
+		return fmt.Errorf("failed to execute retriable function: %w", err)
+	}
+	return nil
+}
+
+// azureDevOpsVersionType returns the Azure DevOps version type of the
+// revision.
+func azureDevOpsVersionType(rev string) string {
+	if azureDevOpsCommitSHARegexp.MatchString(rev) {
+		return "commit"
+	}
+	return "branch"
+}
+
+// htmlToText converts an HTML document, such as a work item description, to
+// plain text with one line per block element so tags on separate lines in the
+// editor are parsed as separate tags.
+func htmlToText(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	skip := 0
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			lines := strings.Split(b.String(), "\n")
+			out := make([]string, 0, len(lines))
+			for _, l := range lines {
+				l = strings.TrimSpace(l)
+				if l == "" && (len(out) == 0 || out[len(out)-1] == "") {
+					continue
+				}
+				out = append(out, l)
+			}
+			return strings.TrimSpace(strings.Join(out, "\n"))
+		case html.TextToken:
+			if skip == 0 {
+				// Line breaks in the markup are whitespace, non-breaking spaces are
+				// inserted by the rich text editor.
+				b.WriteString(htmlWhitespaceReplacer.Replace(string(z.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style":
+				skip++
+			case "br":
+				b.WriteByte('\n')
+			}
+			if _, ok := htmlBlockElements[string(name)]; ok {
+				softLineBreak(&b)
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if string(name) == "script" || string(name) == "style" {
+				skip = max(skip-1, 0)
+			}
+			if _, ok := htmlBlockElements[string(name)]; ok {
+				softLineBreak(&b)
+			}
+		}
+	}
+}
+
+// softLineBreak starts a new line unless the text already ends with one, so
+// adjacent block elements do not produce empty lines.
+func softLineBreak(b *strings.Builder) {
+	if s := strings.TrimRight(b.String(), " "); s != "" && !strings.HasSuffix(s, "\n") {
+		b.WriteByte('\n')
+	}
+}
+
+// validateAzureDevOpsInputs validates the inputs required for project level
+// requests.
+func validateAzureDevOpsInputs(cfg *AzureDevOpsConfig) error {
+	var merr error
+	if cfg.AzureDevOpsProject == "" {
+		merr = errors.Join(merr, fmt.Errorf("azure devops project is required"))
+	}
+
+	if cfg.AzureDevOpsToken == "" {
+		merr = errors.Join(merr, fmt.Errorf("azure devops token is required"))
+	}
+
+	return merr
+}
+
+// validateAzureDevOpsRepoInputs validates the inputs required for repository
+// level requests.
+func validateAzureDevOpsRepoInputs(cfg *AzureDevOpsConfig) error {
+	merr := validateAzureDevOpsInputs(cfg)
+
+	if cfg.AzureDevOpsRepository == "" {
+		merr = errors.Join(merr, fmt.Errorf("azure devops repository is required"))
+	}
+
+	return merr
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestAzureDevOpsPredefinedConfig_Load(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		exp  *azureDevOpsPredefinedConfig
+	}{
+		{
+			name: "azure_repos_pull_request",
+			env: map[string]string{
+				"SYSTEM_COLLECTIONURI":             "https://dev.azure.com/org/",
+				"SYSTEM_TEAMPROJECT":               "project",
+				"BUILD_REPOSITORY_PROVIDER":        "TfsGit",
+				"BUILD_REPOSITORY_NAME":            "repo",
+				"SYSTEM_PULLREQUEST_PULLREQUESTID": "42",
+			},
+			exp: &azureDevOpsPredefinedConfig{
+				OrganizationURL: "https://dev.azure.com/org/",
+				Project:         "project",
+				Repository:      "repo",
+				PullRequestID:   42,
+			},
+		},
+		{
+			name: "github_repository",
+			env: map[string]string{
+				"SYSTEM_COLLECTIONURI":      "https://dev.azure.com/org/",
+				"SYSTEM_TEAMPROJECT":        "project",
+				"BUILD_REPOSITORY_PROVIDER": "GitHub",
+				"BUILD_REPOSITORY_NAME":     "owner/repo",
+			},
+			exp: &azureDevOpsPredefinedConfig{
+				OrganizationURL: "https://dev.azure.com/org/",
+				Project:         "project",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &azureDevOpsPredefinedConfig{}
+			c.Load(func(k string) string { return tc.env[k] })
+
+			if diff := cmp.Diff(c, tc.exp); diff != "" {
+				t.Errorf("azureDevOpsPredefinedConfig not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			exp:  "",
+		},
+		{
+			name: "plain_text",
+			in:   "TAG_1=value",
+			exp:  "TAG_1=value",
+		},
+		{
+			name: "divs",
+			in:   "<div>Some description</div><div>TAG_1=value</div><div><br></div><div>TAG_2=a,b</div>",
+			exp:  "Some description\nTAG_1=value\n\nTAG_2=a,b",
+		},
+		{
+			name: "entities_and_inline_elements",
+			in:   "<p><b>TAG_1</b>=a&amp;b&nbsp;</p><p>TAG_2=&quot;quoted&quot;</p>",
+			exp:  "TAG_1=a&b\nTAG_2=\"quoted\"",
+		},
+		{
+			name: "markup_whitespace",
+			in:   "<ul>\n  <li>TAG_1=one</li>\n  <li>TAG_2=two</li>\n</ul>",
+			exp:  "TAG_1=one\nTAG_2=two",
+		},
+		{
+			name: "skips_style",
+			in:   "<style>div { color: red; }</style><div>TAG_1=value</div>",
+			exp:  "TAG_1=value",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(htmlToText(tc.in), tc.exp); diff != "" {
+				t.Errorf("text not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAzureDevOps_GetBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, ok := r.BasicAuth(); !ok || pass != "my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if got, want := r.URL.Query().Get("api-version"), azureDevOpsAPIVersion; got != want {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/org/project/_apis/git/repositories/repo/pullrequests/1":
+			fmt.Fprint(w, `{"pullRequestId":1,"description":"TAG_1=value"}`)
+		case "/org/project/_apis/wit/workitems/2":
+			fmt.Fprint(w, `{"id":2,"fields":{"System.Description":"<div>TAG_2=value</div><div>TAG_3=value</div>"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name   string
+		cfg    *AzureDevOpsConfig
+		issue  bool
+		expErr string
+		exp    string
+	}{
+		{
+			name: "pull_request",
+			cfg: &AzureDevOpsConfig{
+				AzureDevOpsRepository:    "repo",
+				AzureDevOpsPullRequestID: 1,
+			},
+			exp: "TAG_1=value",
+		},
+		{
+			name: "work_item",
+			cfg: &AzureDevOpsConfig{
+				AzureDevOpsWorkItemID: 2,
+			},
+			issue: true,
+			exp:   "TAG_2=value\nTAG_3=value",
+		},
+		{
+			name: "not_found",
+			cfg: &AzureDevOpsConfig{
+				AzureDevOpsWorkItemID: 3,
+			},
+			issue:  true,
+			expErr: "unexpected status code 404",
+		},
+		{
+			name:   "missing_repository",
+			cfg:    &AzureDevOpsConfig{},
+			expErr: "azure devops repository is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.AzureDevOpsOrganizationURL = srv.URL + "/org/"
+			tc.cfg.AzureDevOpsProject = "project"
+			tc.cfg.AzureDevOpsToken = "my-token"
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+
+			a, err := NewAzureDevOps(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			if tc.issue {
+				got, err = a.GetIssueBody(ctx)
+			} else {
+				got, err = a.GetRequestBody(ctx)
+			}
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,764 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/cli"
+)
+
+const (
+	// bitbucketCloudAPIURL is the API URL of Bitbucket Cloud. Any other server
+	// URL is treated as a Bitbucket Data Center instance.
+	bitbucketCloudAPIURL = "https://api.bitbucket.org/2.0"
+
+	// bitbucketPageSize is the number of results requested per page.
+	bitbucketPageSize = 50
+)
+
+var (
+	_ Platform         = (*Bitbucket)(nil)
+	_ RangeLister      = (*Bitbucket)(nil)
+	_ Searcher         = (*Bitbucket)(nil)
+	_ BodyUpdater      = (*Bitbucket)(nil)
+	_ TicketReferencer = (*Bitbucket)(nil)
+)
+
+// Bitbucket implements the Platform interface for Bitbucket Cloud and
+// Bitbucket Data Center.
+type Bitbucket struct {
+	cfg    *BitbucketConfig
+	client *restClient
+}
+
+// BitbucketConfig is the config values for the Bitbucket client.
+type BitbucketConfig struct {
+	// Retry
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+
+	// Auth
+	BitbucketUsername    string
+	BitbucketAppPassword string
+	BitbucketAccessToken string
+
+	BitbucketServerURL string
+
+	// BitbucketWorkspace is the workspace for Bitbucket Cloud or the project
+	// key for Bitbucket Data Center.
+	BitbucketWorkspace     string
+	BitbucketRepoSlug      string
+	BitbucketPullRequestID int
+	BitbucketIssueID       int
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
+}
+
+type bitbucketPredefinedConfig struct {
+	Workspace     string
+	RepoSlug      string
+	PullRequestID int
+}
+
+// Load retrieves the predefined Bitbucket Pipelines variables from the
+// environment. See
+// https://support.atlassian.com/bitbucket-cloud/docs/variables-and-secrets/.
+func (c *bitbucketPredefinedConfig) Load(getenv func(string) string) {
+	if v := getenv("BITBUCKET_WORKSPACE"); v != "" {
+		c.Workspace = v
+	}
+
+	if v := getenv("BITBUCKET_REPO_SLUG"); v != "" {
+		c.RepoSlug = v
+	}
+
+	if v, err := strconv.Atoi(getenv("BITBUCKET_PR_ID")); err == nil {
+		c.PullRequestID = v
+	}
+}
+
+func (c *BitbucketConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	f := set.NewSection("BITBUCKET OPTIONS")
+
+	cfgDefaults := &bitbucketPredefinedConfig{}
+	cfgDefaults.Load(set.GetEnv)
+
+	f.StringVar(&cli.StringVar{
+		Name:   "bitbucket-username",
+		EnvVar: "BITBUCKET_USERNAME",
+		Target: &c.BitbucketUsername,
+		Usage:  "The Bitbucket username to use with -bitbucket-app-password.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "bitbucket-app-password",
+		EnvVar: "BITBUCKET_APP_PASSWORD",
+		Target: &c.BitbucketAppPassword,
+		Usage:  "The Bitbucket app password, or Data Center password, to make API calls.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "bitbucket-access-token",
+		EnvVar: "BITBUCKET_ACCESS_TOKEN",
+		Target: &c.BitbucketAccessToken,
+		Usage:  "The Bitbucket repository, project or workspace access token to make API calls. Takes precedence over -bitbucket-app-password.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "bitbucket-server-url",
+		EnvVar:  "BITBUCKET_SERVER_URL",
+		Target:  &c.BitbucketServerURL,
+		Default: bitbucketCloudAPIURL,
+		Example: "https://bitbucket.mydomain.com",
+		Usage:   "The API URL of Bitbucket Cloud or the URL of the Bitbucket Data Center instance.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "bitbucket-workspace",
+		Target:  &c.BitbucketWorkspace,
+		Default: cfgDefaults.Workspace,
+		Example: "workspace-name",
+		Usage:   "The Bitbucket Cloud workspace or the Bitbucket Data Center project key.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "bitbucket-repo-slug",
+		Target:  &c.BitbucketRepoSlug,
+		Default: cfgDefaults.RepoSlug,
+		Example: "repository-name",
+		Usage:   "The Bitbucket repository slug.",
+		Hidden:  true,
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "bitbucket-pull-request-id",
+		EnvVar:  "BITBUCKET_PULL_REQUEST_ID",
+		Target:  &c.BitbucketPullRequestID,
+		Default: cfgDefaults.PullRequestID,
+		Usage:   "The Bitbucket pull request ID.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:   "bitbucket-issue-id",
+		EnvVar: "BITBUCKET_ISSUE_ID",
+		Target: &c.BitbucketIssueID,
+		Usage:  "The Bitbucket Cloud issue ID.",
+	})
+}
+
+// isBitbucketPipelines reports whether the environment is a Bitbucket
+// Pipelines build.
+func isBitbucketPipelines(getenv func(string) string) bool {
+	return getenv("BITBUCKET_BUILD_NUMBER") != "" || getenv("BITBUCKET_PR_ID") != ""
+}
+
+// NewBitbucket creates a new Bitbucket client.
+func NewBitbucket(ctx context.Context, cfg *BitbucketConfig) (*Bitbucket, error) {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.InitialRetryDelay <= 0 {
+		cfg.InitialRetryDelay = 1 * time.Second
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = 20 * time.Second
+	}
+	if cfg.BitbucketServerURL == "" {
+		cfg.BitbucketServerURL = bitbucketCloudAPIURL
+	}
+
+	b := &Bitbucket{
+		cfg: cfg,
+		client: &restClient{
+			httpClient: cfg.httpClient,
+			baseURL:    strings.TrimSuffix(cfg.BitbucketServerURL, "/"),
+			auth: func(r *http.Request) {
+				switch {
+				case cfg.BitbucketAccessToken != "":
+					r.Header.Set("Authorization", "Bearer "+cfg.BitbucketAccessToken)
+				case cfg.BitbucketAppPassword != "":
+					r.SetBasicAuth(cfg.BitbucketUsername, cfg.BitbucketAppPassword)
+				}
+			},
+		},
+	}
+	if !b.isCloud() {
+		b.client.baseURL += "/rest/api/latest"
+	}
+
+	return b, nil
+}
+
+// bitbucketCloudPullRequest is the subset of a Bitbucket Cloud pull request
+// used by tagrep.
+type bitbucketCloudPullRequest struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Source      struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"source"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+// bitbucketCloudIssue is the subset of a Bitbucket Cloud issue used by tagrep.
+type bitbucketCloudIssue struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+// bitbucketDataCenterPullRequest is the subset of a Bitbucket Data Center pull
+// request used by tagrep.
+type bitbucketDataCenterPullRequest struct {
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	FromRef     struct {
+		DisplayID string `json:"displayId"`
+	} `json:"fromRef"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+func (pr *bitbucketDataCenterPullRequest) url() string {
+	if len(pr.Links.Self) > 0 {
+		return pr.Links.Self[0].Href
+	}
+	return ""
+}
+
+// bitbucketPullRequest is the pull request of the config, on Bitbucket Cloud
+// or Bitbucket Data Center.
+type bitbucketPullRequest struct {
+	title       string
+	description string
+	branch      string
+}
+
+// GetRequestBody gets the Pull Request description.
+func (b *Bitbucket) GetRequestBody(ctx context.Context) (string, error) {
+	pr, err := b.getPullRequest(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request body: %w", err)
+	}
+	return pr.description, nil
+}
+
+// ListTicketKeys lists the Jira issue keys in the title and source branch of
+// the Pull Request, which Bitbucket links to Jira.
+func (b *Bitbucket) ListTicketKeys(ctx context.Context) ([]string, error) {
+	pr, err := b.getPullRequest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ticket keys: %w", err)
+	}
+	return findJiraKeys(pr.title, pr.branch), nil
+}
+
+// getPullRequest gets the pull request of the config.
+func (b *Bitbucket) getPullRequest(ctx context.Context) (*bitbucketPullRequest, error) {
+	if err := validateBitbucketInputs(b.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if b.cfg.BitbucketPullRequestID <= 0 {
+		return nil, fmt.Errorf("failed to validate inputs: bitbucket pull request id is required")
+	}
+
+	var pr *bitbucketPullRequest
+	if err := b.withRetries(ctx, func(ctx context.Context) error {
+		id := strconv.Itoa(b.cfg.BitbucketPullRequestID)
+		if b.isCloud() {
+			var cloudPR bitbucketCloudPullRequest
+			resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("pullrequests", id), nil, nil, &cloudPR)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+			}
+			pr = &bitbucketPullRequest{
+				title:       cloudPR.Title,
+				description: cloudPR.Description,
+				branch:      cloudPR.Source.Branch.Name,
+			}
+			return nil
+		}
+
+		var dcPR bitbucketDataCenterPullRequest
+		resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("pull-requests", id), nil, nil, &dcPR)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+		}
+		pr = &bitbucketPullRequest{
+			title:       dcPR.Title,
+			description: dcPR.Description,
+			branch:      dcPR.FromRef.DisplayID,
+		}
+		return nil
+	}); err != nil {
+		return nil, err //nolint:wrapcheck // Want passthrough
+	}
+	return pr, nil
+}
+
+// GetIssueBody gets the body of the issue. Issues are only supported by
+// Bitbucket Cloud, Bitbucket Data Center delegates issue tracking to Jira.
+func (b *Bitbucket) GetIssueBody(ctx context.Context) (string, error) {
+	if !b.isCloud() {
+		return "", fmt.Errorf("issues are not supported by bitbucket data center: %w", errors.ErrUnsupported)
+	}
+	if err := validateBitbucketInputs(b.cfg); err != nil {
+		return "", fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if b.cfg.BitbucketIssueID <= 0 {
+		return "", fmt.Errorf("failed to validate inputs: bitbucket issue id is required")
+	}
+
+	var issue bitbucketCloudIssue
+	if err := b.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("issues", strconv.Itoa(b.cfg.BitbucketIssueID)), nil, nil, &issue)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get issue body: %w", err)
+	}
+
+	return issue.Content.Raw, nil
+}
+
+// ListRequestsInRange lists the merged Pull Requests associated with the
+// commits between base and head, ordered by ID.
+func (b *Bitbucket) ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error) {
+	if err := validateBitbucketInputs(b.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	shas, err := b.listCommits(ctx, base, head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	seen := make(map[int]struct{})
+	items := make([]*Item, 0, len(shas))
+	for _, sha := range shas {
+		prs, err := b.listCommitPullRequests(ctx, sha)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+
+		for _, pr := range prs {
+			if _, ok := seen[pr.Number]; ok {
+				continue
+			}
+			seen[pr.Number] = struct{}{}
+			items = append(items, pr)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Number < items[j].Number
+	})
+
+	return items, nil
+}
+
+// SearchItems calls fn for every Bitbucket Cloud issue that matches the query,
+// written in the Bitbucket query language, e.g.
+// `state="open" AND content.raw ~ "JUSTIFICATION"`. See
+// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#filtering.
+func (b *Bitbucket) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	if !b.isCloud() {
+		return fmt.Errorf("issues are not supported by bitbucket data center: %w", errors.ErrUnsupported)
+	}
+	if err := validateBitbucketInputs(b.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	fn = serialize(fn)
+
+	if err := fetchPages(ctx, concurrency, func(ctx context.Context, page int) (int, error) {
+		q := url.Values{}
+		q.Set("q", query)
+		q.Set("page", strconv.Itoa(page))
+		q.Set("pagelen", strconv.Itoa(bitbucketPageSize))
+
+		var result struct {
+			Size   int                    `json:"size"`
+			Values []*bitbucketCloudIssue `json:"values"`
+		}
+		if err := b.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("issues"), q, nil, &result)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to list issues: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return 0, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		for _, issue := range result.Values {
+			if err := fn(&Item{
+				Number: issue.ID,
+				URL:    issue.Links.HTML.Href,
+				Title:  issue.Title,
+				Body:   issue.Content.Raw,
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		return (result.Size + bitbucketPageSize - 1) / bitbucketPageSize, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	return nil
+}
+
+// listCommits lists the SHAs of the commits reachable from head but not base.
+func (b *Bitbucket) listCommits(ctx context.Context, base, head string) ([]string, error) {
+	var shas []string
+
+	if b.isCloud() {
+		// Bitbucket Cloud paginates commits with opaque next links.
+		next := b.repoPath("commits", head)
+		q := url.Values{}
+		q.Set("exclude", base)
+		q.Set("pagelen", strconv.Itoa(bitbucketPageSize))
+		for next != "" {
+			var result struct {
+				Next   string `json:"next"`
+				Values []struct {
+					Hash string `json:"hash"`
+				} `json:"values"`
+			}
+			if err := b.withRetries(ctx, func(ctx context.Context) error {
+				resp, err := b.client.do(ctx, http.MethodGet, next, q, nil, &result)
+				if err != nil {
+					return restMaybeRetryable(resp, fmt.Errorf("failed to list commits: %w", err))
+				}
+				return nil
+			}); err != nil {
+				return nil, err //nolint:wrapcheck // Want passthrough
+			}
+
+			for _, c := range result.Values {
+				shas = append(shas, c.Hash)
+			}
+			// The next link already contains the query.
+			next, q = result.Next, nil
+		}
+		return shas, nil
+	}
+
+	start := 0
+	for {
+		q := url.Values{}
+		q.Set("since", base)
+		q.Set("until", head)
+		q.Set("start", strconv.Itoa(start))
+		q.Set("limit", strconv.Itoa(bitbucketPageSize))
+
+		var result struct {
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+			Values        []struct {
+				ID string `json:"id"`
+			} `json:"values"`
+		}
+		if err := b.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("commits"), q, nil, &result)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to list commits: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return nil, err //nolint:wrapcheck // Want passthrough
+		}
+
+		for _, c := range result.Values {
+			shas = append(shas, c.ID)
+		}
+		if result.IsLastPage {
+			return shas, nil
+		}
+		start = result.NextPageStart
+	}
+}
+
+// listCommitPullRequests lists the merged pull requests containing the commit.
+func (b *Bitbucket) listCommitPullRequests(ctx context.Context, sha string) ([]*Item, error) {
+	var items []*Item
+
+	if err := b.withRetries(ctx, func(ctx context.Context) error {
+		items = items[:0]
+
+		if b.isCloud() {
+			var result struct {
+				Values []*bitbucketCloudPullRequest `json:"values"`
+			}
+			resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("commit", sha, "pullrequests"), nil, nil, &result)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to list pull requests for commit %s: %w", sha, err))
+			}
+			for _, pr := range result.Values {
+				if pr.State != "MERGED" {
+					continue
+				}
+				items = append(items, &Item{
+					Number: pr.ID,
+					URL:    pr.Links.HTML.Href,
+					Title:  pr.Title,
+					Body:   pr.Description,
+				})
+			}
+			return nil
+		}
+
+		var result struct {
+			Values []*bitbucketDataCenterPullRequest `json:"values"`
+		}
+		resp, err := b.client.do(ctx, http.MethodGet, b.repoPath("commits", sha, "pull-requests"), nil, nil, &result)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to list pull requests for commit %s: %w", sha, err))
+		}
+		for _, pr := range result.Values {
+			if pr.State != "MERGED" {
+				continue
+			}
+			items = append(items, &Item{
+				Number: pr.ID,
+				URL:    pr.url(),
+				Title:  pr.Title,
+				Body:   pr.Description,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err //nolint:wrapcheck // Want passthrough
+	}
+
+	return items, nil
+}
+
+// UpdateRequestBody replaces the Pull Request description if it was not
+// changed since it was fetched. Bitbucket Data Center rejects the update if the
+// pull request changed in the meantime.
+func (b *Bitbucket) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	if err := validateBitbucketInputs(b.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if b.cfg.BitbucketPullRequestID <= 0 {
+		return fmt.Errorf("failed to validate inputs: bitbucket pull request id is required")
+	}
+	id := strconv.Itoa(b.cfg.BitbucketPullRequestID)
+
+	if b.isCloud() {
+		var pr bitbucketCloudPullRequest
+		if err := b.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := b.client.do(withCacheRevalidation(ctx), http.MethodGet, b.repoPath("pullrequests", id), nil, nil, &pr)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to get pull request body: %w", err)
+		}
+
+		if err := compareAndSwapBody(pr.Description, oldBody, newBody, func() error {
+			return b.withRetries(ctx, func(ctx context.Context) error {
+				resp, err := b.client.do(ctx, http.MethodPut, b.repoPath("pullrequests", id), nil, map[string]string{
+					"title":       pr.Title,
+					"description": newBody,
+				}, nil)
+				if err != nil {
+					return restMaybeRetryable(resp, fmt.Errorf("failed to update pull request: %w", err))
+				}
+				return nil
+			})
+		}); err != nil {
+			return fmt.Errorf("failed to update pull request body: %w", err)
+		}
+		return nil
+	}
+
+	var pr bitbucketDataCenterPullRequest
+	get := func() error {
+		return b.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := b.client.do(withCacheRevalidation(ctx), http.MethodGet, b.repoPath("pull-requests", id), nil, nil, &pr)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+			}
+			return nil
+		})
+	}
+	if err := get(); err != nil {
+		return fmt.Errorf("failed to get pull request body: %w", err)
+	}
+
+	if err := compareAndSwapBody(pr.Description, oldBody, newBody, func() error {
+		return b.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := b.client.do(ctx, http.MethodPut, b.repoPath("pull-requests", id), nil, map[string]any{
+				"version":     pr.Version,
+				"title":       pr.Title,
+				"description": newBody,
+			}, nil)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to update pull request: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		// The version is outdated, the pull request changed since it was
+		// fetched.
+		var rErr *restError
+		if errors.As(err, &rErr) && rErr.StatusCode == http.StatusConflict {
+			if err := get(); err != nil {
+				return fmt.Errorf("failed to get pull request body: %w", err)
+			}
+			return &BodyChangedError{Current: pr.Description}
+		}
+		return fmt.Errorf("failed to update pull request body: %w", err)
+	}
+	return nil
+}
+
+// UpdateIssueBody replaces the issue content if it was not changed since it
+// was fetched. Issues are only supported by Bitbucket Cloud.
+func (b *Bitbucket) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	if !b.isCloud() {
+		return fmt.Errorf("issues are not supported by bitbucket data center: %w", errors.ErrUnsupported)
+	}
+	if err := validateBitbucketInputs(b.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if b.cfg.BitbucketIssueID <= 0 {
+		return fmt.Errorf("failed to validate inputs: bitbucket issue id is required")
+	}
+	path := b.repoPath("issues", strconv.Itoa(b.cfg.BitbucketIssueID))
+
+	var issue bitbucketCloudIssue
+	if err := b.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := b.client.do(withCacheRevalidation(ctx), http.MethodGet, path, nil, nil, &issue)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get issue body: %w", err)
+	}
+
+	if err := compareAndSwapBody(issue.Content.Raw, oldBody, newBody, func() error {
+		return b.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := b.client.do(ctx, http.MethodPut, path, nil, map[string]any{
+				"content": map[string]string{"raw": newBody},
+			}, nil)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to update issue: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to update issue body: %w", err)
+	}
+	return nil
+}
+
+// isCloud reports whether the client targets Bitbucket Cloud rather than a
+// Bitbucket Data Center instance.
+func (b *Bitbucket) isCloud() bool {
+	return strings.TrimSuffix(b.cfg.BitbucketServerURL, "/") == bitbucketCloudAPIURL
+}
+
+// repoPath returns the API path of the configured repository joined with the
+// escaped path segments.
+func (b *Bitbucket) repoPath(segments ...string) string {
+	var parts []string
+	if b.isCloud() {
+		parts = []string{"repositories", url.PathEscape(b.cfg.BitbucketWorkspace), url.PathEscape(b.cfg.BitbucketRepoSlug)}
+	} else {
+		parts = []string{"projects", url.PathEscape(b.cfg.BitbucketWorkspace), "repos", url.PathEscape(b.cfg.BitbucketRepoSlug)}
+	}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (b *Bitbucket) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	backoff := retry.NewFibonacci(b.cfg.InitialRetryDelay)
+	backoff = retry.WithMaxRetries(retryLimit(b.cfg.MaxRetries), backoff)
+	backoff = retry.WithCappedDuration(b.cfg.MaxRetryDelay, backoff)
+
+	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
+		return fmt.Errorf("failed to execute retriable function: %w", err)
+	}
+	return nil
+}
+
+// validateBitbucketInputs validates the inputs required for repository level
+// requests.
+func validateBitbucketInputs(cfg *BitbucketConfig) error {
+	var merr error
+	if cfg.BitbucketWorkspace == "" {
+		merr = errors.Join(merr, fmt.Errorf("bitbucket workspace is required"))
+	}
+
+	if cfg.BitbucketRepoSlug == "" {
+		merr = errors.Join(merr, fmt.Errorf("bitbucket repo slug is required"))
+	}
+
+	if cfg.BitbucketAccessToken == "" && cfg.BitbucketAppPassword == "" {
+		merr = errors.Join(merr, fmt.Errorf("one of bitbucket access token or bitbucket app password is required"))
+	}
+
+	return merr
+}
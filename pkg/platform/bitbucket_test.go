@@ -0,0 +1,304 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestBitbucketPredefinedConfig_Load(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		exp  *bitbucketPredefinedConfig
+	}{
+		{
+			name: "pull_request_pipeline",
+			env: map[string]string{
+				"BITBUCKET_WORKSPACE": "workspace",
+				"BITBUCKET_REPO_SLUG": "repo",
+				"BITBUCKET_PR_ID":     "12",
+			},
+			exp: &bitbucketPredefinedConfig{
+				Workspace:     "workspace",
+				RepoSlug:      "repo",
+				PullRequestID: 12,
+			},
+		},
+		{
+			name: "branch_pipeline",
+			env: map[string]string{
+				"BITBUCKET_WORKSPACE": "workspace",
+				"BITBUCKET_REPO_SLUG": "repo",
+			},
+			exp: &bitbucketPredefinedConfig{
+				Workspace: "workspace",
+				RepoSlug:  "repo",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &bitbucketPredefinedConfig{}
+			c.Load(func(k string) string { return tc.env[k] })
+
+			if diff := cmp.Diff(c, tc.exp); diff != "" {
+				t.Errorf("bitbucketPredefinedConfig not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestBitbucket_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, basic := r.BasicAuth()
+		if r.Header.Get("Authorization") != "Bearer my-token" && !(basic && user == "user" && pass == "my-password") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/rest/api/latest/projects/PROJ/repos/repo/pull-requests/1":
+			fmt.Fprint(w, `{"id":1,"description":"TAG_1=value"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"message":"not found"}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name   string
+		cfg    *BitbucketConfig
+		expErr string
+		exp    string
+	}{
+		{
+			name: "access_token",
+			cfg: &BitbucketConfig{
+				BitbucketAccessToken:   "my-token",
+				BitbucketWorkspace:     "PROJ",
+				BitbucketRepoSlug:      "repo",
+				BitbucketPullRequestID: 1,
+			},
+			exp: "TAG_1=value",
+		},
+		{
+			name: "app_password",
+			cfg: &BitbucketConfig{
+				BitbucketUsername:      "user",
+				BitbucketAppPassword:   "my-password",
+				BitbucketWorkspace:     "PROJ",
+				BitbucketRepoSlug:      "repo",
+				BitbucketPullRequestID: 1,
+			},
+			exp: "TAG_1=value",
+		},
+		{
+			name: "not_found",
+			cfg: &BitbucketConfig{
+				BitbucketAccessToken:   "my-token",
+				BitbucketWorkspace:     "PROJ",
+				BitbucketRepoSlug:      "repo",
+				BitbucketPullRequestID: 2,
+			},
+			expErr: "unexpected status code 404",
+		},
+		{
+			name: "missing_inputs",
+			cfg:  &BitbucketConfig{},
+			expErr: "bitbucket workspace is required\n" +
+				"bitbucket repo slug is required\n" +
+				"one of bitbucket access token or bitbucket app password is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.BitbucketServerURL = srv.URL
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+
+			b, err := NewBitbucket(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := b.GetRequestBody(ctx)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestBitbucket_ListTicketKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws/repo/pullrequests/1":
+			fmt.Fprint(w, `{"id":1,"title":"PROJ-1: Add a cache","source":{"branch":{"name":"feature/PROJ-2-cache"}}}`)
+		case "/rest/api/latest/projects/PROJ/repos/repo/pull-requests/1":
+			fmt.Fprint(w, `{"id":1,"title":"Add a cache for OPS-7 and PROJ-1","fromRef":{"displayId":"PROJ-1-cache"}}`)
+		case "/rest/api/latest/projects/PROJ/repos/repo/pull-requests/2":
+			fmt.Fprint(w, `{"id":2,"title":"Add a cache","fromRef":{"displayId":"main"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name      string
+		cloud     bool
+		workspace string
+		id        int
+		exp       []string
+	}{
+		{
+			name:      "cloud",
+			cloud:     true,
+			workspace: "ws",
+			id:        1,
+			exp:       []string{"PROJ-1", "PROJ-2"},
+		},
+		{
+			name:      "data_center",
+			workspace: "PROJ",
+			id:        1,
+			exp:       []string{"OPS-7", "PROJ-1"},
+		},
+		{
+			name:      "no_keys",
+			workspace: "PROJ",
+			id:        2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &BitbucketConfig{
+				BitbucketAccessToken:   "my-token",
+				BitbucketWorkspace:     tc.workspace,
+				BitbucketRepoSlug:      "repo",
+				BitbucketPullRequestID: tc.id,
+				MaxRetries:             1,
+				InitialRetryDelay:      1,
+			}
+			if !tc.cloud {
+				cfg.BitbucketServerURL = srv.URL
+			}
+
+			b, err := NewBitbucket(ctx, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.cloud {
+				b.client.baseURL = srv.URL
+			}
+
+			got, err := b.ListTicketKeys(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("ticket keys not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestBitbucket_ListRequestsInRange(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws/repo/commits/head":
+			if got, want := r.URL.Query().Get("exclude"), "base"; got != want {
+				t.Errorf("exclude not as expected; got %q, want %q", got, want)
+			}
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(w, `{"values":[{"hash":"c1"}]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"values":[{"hash":"c3"},{"hash":"c2"}],"next":"%s/repositories/ws/repo/commits/head?exclude=base&page=2"}`, srv.URL)
+		case "/repositories/ws/repo/commit/c3/pullrequests":
+			fmt.Fprint(w, `{"values":[{"id":5,"title":"five","description":"TAG=5","state":"MERGED"},{"id":6,"state":"OPEN"}]}`)
+		case "/repositories/ws/repo/commit/c2/pullrequests":
+			fmt.Fprint(w, `{"values":[{"id":5,"title":"five","description":"TAG=5","state":"MERGED"}]}`)
+		case "/repositories/ws/repo/commit/c1/pullrequests":
+			fmt.Fprint(w, `{"values":[{"id":4,"title":"four","description":"TAG=4","state":"MERGED"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	b, err := NewBitbucket(ctx, &BitbucketConfig{
+		BitbucketAccessToken: "my-token",
+		BitbucketWorkspace:   "ws",
+		BitbucketRepoSlug:    "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bitbucket Cloud is detected from the server URL, so point the client at
+	// the test server after it has been created.
+	b.client.baseURL = srv.URL
+
+	got, err := b.ListRequestsInRange(ctx, "base", "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Item{
+		{Number: 4, Title: "four", Body: "TAG=4"},
+		{Number: 5, Title: "five", Body: "TAG=5"},
+	}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Errorf("items not as expected; (-got,+want): %s", diff)
+	}
+}
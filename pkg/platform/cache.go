@@ -0,0 +1,324 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+// cacheRateLimitHeaderPrefixes are the prefixes of rate limit headers, which
+// are not stored because they are stale when the response is served from the
+// cache.
+var cacheRateLimitHeaderPrefixes = []string{"X-Ratelimit-", "Ratelimit-"}
+
+// cacheRevalidateKey is the context key to revalidate fresh cached responses.
+type cacheRevalidateKey struct{}
+
+// withCacheRevalidation returns a context whose requests revalidate cached
+// responses even if they are fresh, e.g. to fetch the current body before
+// updating it.
+func withCacheRevalidation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheRevalidateKey{}, true)
+}
+
+// cacheEntry is the file format of a cached response.
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StoredAt   time.Time   `json:"stored_at"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// cacheTransport is an http.RoundTripper that caches successful GET responses
+// on disk. Responses younger than the TTL are served from the cache without a
+// request. Older responses are revalidated with If-None-Match or
+// If-Modified-Since, and served from the cache if the platform answers 304 Not
+// Modified. GitHub does not count those against the rate limit, see
+// https://docs.github.com/en/rest/using-the-rest-api/best-practices-for-using-the-rest-api#use-conditional-requests-if-appropriate.
+type cacheTransport struct {
+	dir  string
+	ttl  time.Duration
+	key  string
+	next http.RoundTripper
+
+	now func() time.Time
+}
+
+// newCacheTransport creates a transport that caches responses in dir and sends
+// requests with next, or http.DefaultTransport if next is nil. Responses are
+// keyed by key instead of the credentials of the requests if key is not
+// empty.
+func newCacheTransport(dir string, ttl time.Duration, key string, next http.RoundTripper) *cacheTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{
+		dir:  dir,
+		ttl:  ttl,
+		key:  key,
+		next: next,
+		now:  time.Now,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	logger := logging.FromContext(ctx)
+
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			t.invalidate(req)
+		}
+		return resp, err //nolint:wrapcheck // Want passthrough
+	}
+	if req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	path := filepath.Join(t.dir, cacheKey(req, t.key)+".json")
+	entry, err := readCacheEntry(path)
+	if err != nil {
+		logger.DebugContext(ctx, "ignoring unreadable cache entry", "path", path, "error", err)
+	}
+
+	_, revalidate := ctx.Value(cacheRevalidateKey{}).(bool)
+	if entry != nil && !revalidate && t.now().Sub(entry.StoredAt) < t.ttl {
+		logger.DebugContext(ctx, "serving response from cache", "url", entry.URL)
+		return entry.response(req), nil
+	}
+
+	out := req
+	if entry != nil {
+		out = req.Clone(ctx)
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			out.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Want passthrough
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+
+		// The headers of a 304 response update the cached headers.
+		for k, v := range resp.Header {
+			entry.Header[k] = v
+		}
+		entry.StoredAt = t.now()
+		t.store(req, path, entry)
+
+		logger.DebugContext(ctx, "revalidated cached response", "url", entry.URL)
+		cached := entry.response(req)
+		// Pass the fresh rate limit headers through to the platform client.
+		for k, v := range resp.Header {
+			cached.Header[k] = v
+		}
+		return cached, nil
+	}
+
+	// Responses without validators can only be served while they are fresh.
+	validated := resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+	if resp.StatusCode != http.StatusOK || (!validated && t.ttl <= 0) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(req, path, &cacheEntry{
+		URL:        req.URL.Redacted(),
+		StoredAt:   t.now(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+	})
+	return resp, nil
+}
+
+// store writes the entry to the cache. Failing to cache a response does not
+// fail the request.
+func (t *cacheTransport) store(req *http.Request, path string, entry *cacheEntry) {
+	ctx := req.Context()
+
+	for k := range entry.Header {
+		for _, prefix := range cacheRateLimitHeaderPrefixes {
+			if strings.HasPrefix(http.CanonicalHeaderKey(k), prefix) {
+				delete(entry.Header, k)
+			}
+		}
+	}
+
+	if err := writeCacheEntry(t.dir, path, entry); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "failed to cache response", "url", entry.URL, "error", err)
+	}
+}
+
+// invalidate removes the cached responses of the resource changed by req, so
+// the change is visible to later requests.
+func (t *cacheTransport) invalidate(req *http.Request) {
+	ctx := req.Context()
+
+	paths, err := filepath.Glob(filepath.Join(t.dir, cacheResourceKey(req)+"-*.json"))
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logging.FromContext(ctx).WarnContext(ctx, "failed to invalidate cached response", "path", path, "error", err)
+		}
+	}
+}
+
+// response returns the cached response to req.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheResourceKey identifies the resource of a request, regardless of the
+// query, method or headers.
+func cacheResourceKey(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	u.Fragment = ""
+	sum := sha256.Sum256([]byte(u.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cacheKey identifies a response by the request URL and the request headers
+// that change the response. Credentials are part of the key so responses are
+// never served to a client with different permissions, but only a hash of them
+// is stored. If credentialsKey is not empty, it replaces the credentials, so
+// responses are shared by credentials with the same access that change per
+// run. The key starts with the resource key, so all cached responses of a
+// resource can be invalidated.
+func cacheKey(req *http.Request, credentialsKey string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", req.Method, req.URL.String())
+	if credentialsKey != "" {
+		fmt.Fprintf(h, "credentials: %s\n", credentialsKey)
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		lower := strings.ToLower(k)
+		if lower == "accept" || lower == "x-github-api-version" {
+			names = append(names, k)
+			continue
+		}
+		if credentialsKey != "" {
+			continue
+		}
+		for _, part := range cassetteSensitiveHeaderParts {
+			if strings.Contains(lower, part) {
+				names = append(names, k)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		fmt.Fprintf(h, "%s: %s\n", strings.ToLower(k), strings.Join(req.Header.Values(k), ", "))
+	}
+
+	return cacheResourceKey(req) + "-" + hex.EncodeToString(h.Sum(nil))
+}
+
+// readCacheEntry reads the entry at path, returning nil if there is none.
+func readCacheEntry(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse cache entry: %w", err)
+	}
+	if entry.Header == nil {
+		entry.Header = make(http.Header)
+	}
+	return &entry, nil
+}
+
+// writeCacheEntry atomically writes the entry to path, so concurrent runs
+// sharing the cache directory never read a partial entry.
+func writeCacheEntry(dir, path string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close cache entry: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename cache entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+func TestCacheTransport(t *testing.T) {
+	t.Parallel()
+
+	type served struct {
+		Path        string
+		Token       string
+		IfNoneMatch string
+	}
+
+	cases := []struct {
+		name string
+		ttl  time.Duration
+		// requests are sent in order, advancing the clock by step before each.
+		requests []string
+		step     time.Duration
+		exp      []served
+		expBody  string
+	}{
+		{
+			name:     "fresh",
+			ttl:      time.Minute,
+			requests: []string{"token-a /body", "token-a /body"},
+			step:     time.Second,
+			exp: []served{
+				{Path: "/body", Token: "token-a"},
+			},
+			expBody: "body 1",
+		},
+		{
+			name:     "revalidated",
+			ttl:      time.Minute,
+			requests: []string{"token-a /body", "token-a /body", "token-a /body"},
+			step:     2 * time.Minute,
+			exp: []served{
+				{Path: "/body", Token: "token-a"},
+				{Path: "/body", Token: "token-a", IfNoneMatch: `"etag-/body"`},
+				{Path: "/body", Token: "token-a", IfNoneMatch: `"etag-/body"`},
+			},
+			expBody: "body 1",
+		},
+		{
+			name:     "other_token",
+			ttl:      time.Minute,
+			requests: []string{"token-a /body", "token-b /body"},
+			step:     time.Second,
+			exp: []served{
+				{Path: "/body", Token: "token-a"},
+				{Path: "/body", Token: "token-b"},
+			},
+			expBody: "body 2",
+		},
+		{
+			name:     "errors_not_cached",
+			ttl:      time.Minute,
+			requests: []string{"token-a /missing", "token-a /missing"},
+			step:     time.Second,
+			exp: []served{
+				{Path: "/missing", Token: "token-a"},
+				{Path: "/missing", Token: "token-a"},
+			},
+			expBody: "not found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+			var mu sync.Mutex
+			var got []served
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				got = append(got, served{
+					Path:        r.URL.Path,
+					Token:       strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+					IfNoneMatch: r.Header.Get("If-None-Match"),
+				})
+
+				if r.URL.Path == "/missing" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, "not found")
+					return
+				}
+
+				etag := fmt.Sprintf("%q", "etag-"+r.URL.Path)
+				w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", 100-len(got)))
+				if r.Header.Get("If-None-Match") == etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", etag)
+				fmt.Fprintf(w, "body %d", len(got))
+			}))
+			t.Cleanup(srv.Close)
+
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			transport := newCacheTransport(t.TempDir(), tc.ttl, "", nil)
+			transport.now = func() time.Time { return now }
+			client := &http.Client{Transport: transport}
+
+			var body string
+			for _, r := range tc.requests {
+				now = now.Add(tc.step)
+
+				token, path, _ := strings.Cut(r, " ")
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = string(b)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("requests to the server not as expected; (-got,+want): %s", diff)
+			}
+			if got, want := body, tc.expBody; got != want {
+				t.Errorf("body not as expected; got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestCacheTransport_Runs covers runs that share a cache directory, each with
+// its own token like GITHUB_TOKEN in separate jobs.
+func TestCacheTransport_Runs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		key         string
+		expRequests int
+	}{
+		{
+			name:        "credentials_key",
+			expRequests: 2,
+		},
+		{
+			name:        "cache_key",
+			key:         "octo-org/widgets",
+			expRequests: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+			var requests atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("ETag", `"etag"`)
+				fmt.Fprint(w, "body")
+			}))
+			t.Cleanup(srv.Close)
+
+			dir := t.TempDir()
+			for _, token := range []string{"token-run-1", "token-run-2"} {
+				client := &http.Client{Transport: newCacheTransport(dir, time.Minute, tc.key, nil)}
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+			}
+
+			if got, want := requests.Load(), int64(tc.expRequests); got != want {
+				t.Errorf("requests to the server not as expected; got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestCacheTransport_NoCredentialsStored(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag"`)
+		fmt.Fprint(w, "body")
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: newCacheTransport(dir, time.Minute, "", nil)}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer my-secret-token")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(entries), 1; got != want {
+		t.Fatalf("cache entries not as expected; got %d, want %d", got, want)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "my-secret-token") {
+		t.Errorf("cache entry contains the token:\n%s", data)
+	}
+}
+
+func TestCacheTransport_Updates(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	var served []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = append(served, strings.TrimSpace(r.Method+" "+r.Header.Get("If-None-Match")))
+		if r.Header.Get("If-None-Match") == `"etag"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		fmt.Fprint(w, "body")
+	}))
+	t.Cleanup(srv.Close)
+
+	client := &http.Client{Transport: newCacheTransport(t.TempDir(), time.Hour, "", nil)}
+	send := func(ctx context.Context, method string) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(ctx, method, srv.URL+"/issues/1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// Fresh responses are served from the cache unless revalidation is
+	// requested, and changing the resource invalidates them.
+	send(ctx, http.MethodGet)
+	send(ctx, http.MethodGet)
+	send(withCacheRevalidation(ctx), http.MethodGet)
+	send(ctx, http.MethodPatch)
+	send(ctx, http.MethodGet)
+
+	exp := []string{
+		"GET",
+		`GET "etag"`,
+		"PATCH",
+		"GET",
+	}
+	if diff := cmp.Diff(served, exp); diff != "" {
+		t.Errorf("requests to the server not as expected; (-got,+want): %s", diff)
+	}
+}
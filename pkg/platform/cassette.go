@@ -0,0 +1,295 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// CassetteModeRecord sends requests to the platform and stores the
+	// responses in the cassette.
+	CassetteModeRecord = "record"
+	// CassetteModeReplay serves requests from the responses stored in the
+	// cassette without sending them to the platform.
+	CassetteModeReplay = "replay"
+)
+
+// cassetteModes are the allowed values of the http-cassette-mode flag.
+var cassetteModes = []string{CassetteModeRecord, CassetteModeReplay}
+
+// cassetteRedacted replaces secrets in recorded interactions.
+const cassetteRedacted = "REDACTED"
+
+// cassetteSensitiveQueryParams are query parameters that carry credentials and
+// are removed from recorded URLs.
+var cassetteSensitiveQueryParams = []string{"access_token", "private_token", "token"}
+
+// cassetteSensitiveHeaderParts are parts of response header names that may
+// carry credentials. Matching headers are not recorded.
+var cassetteSensitiveHeaderParts = []string{"auth", "cookie", "token"}
+
+// cassette is the file format of recorded HTTP interactions.
+type cassette struct {
+	Interactions []*cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Request  *cassetteRequest  `json:"request"`
+	Response *cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// cassetteTransport is an http.RoundTripper that records the requests sent to
+// a platform with their responses into a cassette file, or replays the
+// responses of a previously recorded cassette. Credentials are stripped from
+// recorded interactions so cassettes can be shared to reproduce issues.
+type cassetteTransport struct {
+	path    string
+	mode    string
+	next    http.RoundTripper
+	secrets []string
+
+	mu       sync.Mutex
+	cassette *cassette
+	replayed map[*cassetteInteraction]struct{}
+}
+
+// newCassetteTransport creates a transport that records to or replays from the
+// cassette at path. Occurrences of secrets are redacted from recordings.
+func newCassetteTransport(path, mode string, secrets []string) (*cassetteTransport, error) {
+	t := &cassetteTransport{
+		path:     path,
+		mode:     mode,
+		next:     http.DefaultTransport,
+		cassette: &cassette{},
+		replayed: make(map[*cassetteInteraction]struct{}),
+	}
+	for _, s := range secrets {
+		if s != "" {
+			t.secrets = append(t.secrets, s)
+		}
+	}
+
+	switch mode {
+	case CassetteModeRecord:
+	case CassetteModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, t.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode: %s", mode)
+	}
+
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	recorded := &cassetteRequest{
+		Method: req.Method,
+		URL:    t.sanitizeURL(req.URL),
+		Body:   t.redact(canonicalBody(body)),
+	}
+
+	if t.mode == CassetteModeReplay {
+		return t.replay(req, recorded)
+	}
+	return t.record(req, body, recorded)
+}
+
+func (t *cassetteTransport) replay(req *http.Request, recorded *cassetteRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Identical requests are answered with their recorded responses in order.
+	for _, i := range t.cassette.Interactions {
+		if _, ok := t.replayed[i]; ok {
+			continue
+		}
+		if i.Request.Method != recorded.Method || i.Request.URL != recorded.URL || i.Request.Body != recorded.Body {
+			continue
+		}
+		t.replayed[i] = struct{}{}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response in cassette %s for %s %s", t.path, recorded.Method, recorded.URL)
+}
+
+func (t *cassetteTransport) record(req *http.Request, body []byte, recorded *cassetteRequest) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cassette.Interactions = append(t.cassette.Interactions, &cassetteInteraction{
+		Request: recorded,
+		Response: &cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     t.sanitizeHeader(resp.Header),
+			Body:       t.redact(string(respBody)),
+		},
+	})
+
+	// The cassette is written after every interaction so it is complete even
+	// when the command fails.
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(t.path, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// sanitizeURL removes credentials from the URL.
+func (t *cassetteTransport) sanitizeURL(u *url.URL) string {
+	sanitized := *u
+	sanitized.User = nil
+
+	query := sanitized.Query()
+	for _, p := range cassetteSensitiveQueryParams {
+		query.Del(p)
+	}
+	sanitized.RawQuery = query.Encode()
+
+	return t.redact(sanitized.String())
+}
+
+// sanitizeHeader removes the headers that may carry credentials.
+func (t *cassetteTransport) sanitizeHeader(header http.Header) http.Header {
+	sanitized := make(http.Header, len(header))
+	for k, v := range header {
+		lower := strings.ToLower(k)
+		sensitive := false
+		for _, part := range cassetteSensitiveHeaderParts {
+			if strings.Contains(lower, part) {
+				sensitive = true
+				break
+			}
+		}
+		if sensitive {
+			continue
+		}
+
+		values := make([]string, 0, len(v))
+		for _, s := range v {
+			values = append(values, t.redact(s))
+		}
+		sanitized[k] = values
+	}
+	return sanitized
+}
+
+// redact replaces all occurrences of the secrets in s.
+func (t *cassetteTransport) redact(s string) string {
+	for _, secret := range t.secrets {
+		s = strings.ReplaceAll(s, secret, cassetteRedacted)
+	}
+	return s
+}
+
+// canonicalBody returns JSON bodies re-encoded with sorted keys and without
+// insignificant whitespace so requests match regardless of encoding details.
+func canonicalBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return string(body)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// cassetteHTTPClient returns an HTTP client using a cassette transport, or nil
+// when no cassette is configured.
+func cassetteHTTPClient(path, mode string, secrets []string) (*http.Client, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	t, err := newCassetteTransport(path, mode, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette transport: %w", err)
+	}
+	return &http.Client{Transport: t}, nil
+}
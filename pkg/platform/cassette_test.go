@@ -0,0 +1,141 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestCassetteTransport(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=my-session")
+		w.Header().Set("X-Request-Id", "my-secret-token")
+		fmt.Fprintf(w, `{"request":%d,"echo":"my-secret-token"}`, requests)
+	}))
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	secrets := []string{"my-secret-token", ""}
+
+	send := func(tb testing.TB, client *http.Client, method, target, body string) (string, error) {
+		tb.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), method, target, strings.NewReader(body))
+		if err != nil {
+			tb.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer my-secret-token")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		return string(b), nil
+	}
+
+	recorder, err := cassetteHTTPClient(path, CassetteModeRecord, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []string{`{"b":1,"a":2}`, `{"b":1,"a":2}`} {
+		if _, err := send(t, recorder, http.MethodPost, srv.URL+"/path?private_token=my-secret-token&page=2", body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"my-secret-token", "my-session", "Bearer"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	player, err := cassetteHTTPClient(path, CassetteModeReplay, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		url    string
+		body   string
+		exp    string
+		expErr string
+	}{
+		{
+			name: "first_response",
+			url:  srv.URL + "/path?page=2&private_token=my-secret-token",
+			body: `{"a": 2, "b": 1}`,
+			exp:  `{"request":1,"echo":"REDACTED"}`,
+		},
+		{
+			name: "second_response",
+			url:  srv.URL + "/path?page=2",
+			body: `{"a":2,"b":1}`,
+			exp:  `{"request":2,"echo":"REDACTED"}`,
+		},
+		{
+			name:   "exhausted",
+			url:    srv.URL + "/path?page=2",
+			body:   `{"a":2,"b":1}`,
+			expErr: "no recorded response in cassette",
+		},
+		{
+			name:   "other_body",
+			url:    srv.URL + "/path?page=2",
+			body:   `{"a":3}`,
+			expErr: "no recorded response in cassette",
+		},
+	}
+
+	// Cases replay in order as identical requests consume the responses in
+	// recorded order.
+	for _, tc := range cases {
+		got, err := send(t, player, http.MethodPost, tc.url, tc.body)
+		if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+			t.Errorf("%s: %s", tc.name, diff)
+		}
+		if got != tc.exp {
+			t.Errorf("%s: body not as expected; got %q, want %q", tc.name, got, tc.exp)
+		}
+	}
+
+	if got, want := requests, 2; got != want {
+		t.Errorf("requests to the server not as expected; got %d, want %d", got, want)
+	}
+}
@@ -18,8 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/posener/complete/v2"
 
@@ -30,8 +32,35 @@ import (
 type Config struct {
 	Type string
 
-	GitHub gitHubConfig
-	GitLab gitLabConfig
+	// Retry, a MaxRetries of 0 uses the default of each platform, use
+	// NoRetries to disable retries.
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+	MaxRateLimitWait  time.Duration
+
+	// Caching
+	CacheDir string
+	CacheTTL time.Duration
+	CacheKey string
+
+	// Debugging
+	HTTPCassette     string
+	HTTPCassetteMode string
+
+	GitHub      GitHubConfig
+	GitLab      GitLabConfig
+	Git         GitConfig
+	Local       LocalConfig
+	Gitea       GiteaConfig
+	Bitbucket   BitbucketConfig
+	AzureDevOps AzureDevOpsConfig
+	Gerrit      GerritConfig
+	Jira        JiraConfig
+
+	// httpClient is the HTTP client of all platforms, built from the caching
+	// and recording options or set with WithHTTPClient.
+	httpClient *http.Client
 }
 
 func (c *Config) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
@@ -51,13 +80,108 @@ func (c *Config) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
 		}),
 	})
 
+	f.Uint64Var(&cli.Uint64Var{
+		Name:    "max-retries",
+		EnvVar:  "TAGREP_MAX_RETRIES",
+		Target:  &c.MaxRetries,
+		Default: 3,
+		Usage:   "The maximum number of times a failed platform API request is retried, 0 disables retries.",
+	})
+
+	f.DurationVar(&cli.DurationVar{
+		Name:    "initial-retry-delay",
+		EnvVar:  "TAGREP_INITIAL_RETRY_DELAY",
+		Target:  &c.InitialRetryDelay,
+		Default: 1 * time.Second,
+		Usage:   "The delay before the first retry of a failed platform API request.",
+	})
+
+	f.DurationVar(&cli.DurationVar{
+		Name:    "max-retry-delay",
+		EnvVar:  "TAGREP_MAX_RETRY_DELAY",
+		Target:  &c.MaxRetryDelay,
+		Default: 20 * time.Second,
+		Usage:   "The maximum delay between retries of a failed platform API request.",
+	})
+
+	f.DurationVar(&cli.DurationVar{
+		Name:    "max-rate-limit-wait",
+		EnvVar:  "TAGREP_MAX_RATE_LIMIT_WAIT",
+		Target:  &c.MaxRateLimitWait,
+		Default: 5 * time.Minute,
+		Usage: "The maximum time to wait for the rate limit to reset before retrying a " +
+			"rate limited GitHub or GitLab API request. Requests rate limited for longer fail.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "cache-dir",
+		EnvVar:  "TAGREP_CACHE_DIR",
+		Target:  &c.CacheDir,
+		Example: "${{ runner.temp }}/tagrep-cache",
+		Usage: "The directory to cache platform API responses in, so repeated runs " +
+			"revalidate them with conditional requests instead of fetching them again. " +
+			"Caching is disabled if empty.",
+	})
+
+	f.DurationVar(&cli.DurationVar{
+		Name:    "cache-ttl",
+		EnvVar:  "TAGREP_CACHE_TTL",
+		Target:  &c.CacheTTL,
+		Default: 1 * time.Minute,
+		Usage: "How long cached platform API responses are used without revalidating " +
+			"them. Older responses are revalidated. Only used with -cache-dir.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "cache-key",
+		EnvVar:  "TAGREP_CACHE_KEY",
+		Target:  &c.CacheKey,
+		Example: "${{ github.repository }}",
+		Usage: "Identifies the access of the credentials in the cache instead of a hash " +
+			"of the credentials, so the cache is shared by credentials that change per " +
+			"run, e.g. GITHUB_TOKEN. Only set it to a value that is the same for " +
+			"credentials with the same access. Only used with -cache-dir.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "http-cassette",
+		EnvVar:  "TAGREP_HTTP_CASSETTE",
+		Target:  &c.HTTPCassette,
+		Example: "cassette.json",
+		Usage: "The file to record the platform API requests and responses to, or to " +
+			"replay them from, see -http-cassette-mode. Credentials are not recorded.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "http-cassette-mode",
+		EnvVar:  "TAGREP_HTTP_CASSETTE_MODE",
+		Target:  &c.HTTPCassetteMode,
+		Default: CassetteModeRecord,
+		Usage:   fmt.Sprintf("Whether to record or replay the -http-cassette. Allowed values are %q.", cassetteModes),
+		Predict: complete.PredictFunc(func(prefix string) []string {
+			return cassetteModes
+		}),
+	})
+
 	// leave last to put help under platform options
 	c.GitHub.RegisterFlagsContext(ctx, set)
 	c.GitLab.RegisterFlagsContext(ctx, set)
+	c.Git.RegisterFlagsContext(ctx, set)
+	c.Local.RegisterFlagsContext(ctx, set)
+	c.Gitea.RegisterFlagsContext(ctx, set)
+	c.Bitbucket.RegisterFlagsContext(ctx, set)
+	c.AzureDevOps.RegisterFlagsContext(ctx, set)
+	c.Gerrit.RegisterFlagsContext(ctx, set)
+	c.Jira.RegisterFlagsContext(ctx, set)
 
 	set.AfterParse(func(merr error) error {
 		c.Type = strings.ToLower(strings.TrimSpace(c.Type))
 
+		// The flag defaults to 3, so 0 was set explicitly.
+		if c.MaxRetries == 0 {
+			c.MaxRetries = NoRetries
+		}
+
 		if _, ok := allowedTypes[c.Type]; !ok && c.Type != TypeUnspecified {
 			merr = errors.Join(merr, fmt.Errorf("unsupported value for platform flag: %s", c.Type))
 		}
@@ -69,8 +193,111 @@ func (c *Config) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
 			if v, _ := strconv.ParseBool(set.GetEnv("GITLAB_CI")); v {
 				c.Type = TypeGitLab
 			}
+			// Gitea and Forgejo Actions also set GITHUB_ACTIONS for compatibility,
+			// so they must be checked after GitHub.
+			if isGiteaActions(set.GetEnv) {
+				c.Type = TypeGitea
+			}
+			if isBitbucketPipelines(set.GetEnv) {
+				c.Type = TypeBitbucket
+			}
+			if isAzurePipelines(set.GetEnv) {
+				c.Type = TypeAzureDevOps
+			}
+			// Gerrit changes are commonly built by Jenkins or Zuul, which are not
+			// otherwise detected.
+			if c.Type == TypeUnspecified && isGerritCI(set.GetEnv) {
+				c.Type = TypeGerrit
+			}
+		}
+
+		if c.Type == TypeUnspecified {
+			c.Type = TypeLocal
+		}
+
+		c.applyRetryOptions()
+
+		if err := c.applyHTTPClient(); err != nil {
+			merr = errors.Join(merr, err)
 		}
 
 		return merr
 	})
 }
+
+// applyRetryOptions sets the retry options of all platforms to the shared
+// retry options that are set. Options that are not set keep the value of the
+// platform config, or its default.
+func (c *Config) applyRetryOptions() {
+	c.setRetryOptions(&c.GitHub.MaxRetries, &c.GitHub.InitialRetryDelay, &c.GitHub.MaxRetryDelay)
+	c.setRetryOptions(&c.GitLab.MaxRetries, &c.GitLab.InitialRetryDelay, &c.GitLab.MaxRetryDelay)
+	c.setRetryOptions(&c.Gitea.MaxRetries, &c.Gitea.InitialRetryDelay, &c.Gitea.MaxRetryDelay)
+	c.setRetryOptions(&c.Bitbucket.MaxRetries, &c.Bitbucket.InitialRetryDelay, &c.Bitbucket.MaxRetryDelay)
+	c.setRetryOptions(&c.AzureDevOps.MaxRetries, &c.AzureDevOps.InitialRetryDelay, &c.AzureDevOps.MaxRetryDelay)
+	c.setRetryOptions(&c.Gerrit.MaxRetries, &c.Gerrit.InitialRetryDelay, &c.Gerrit.MaxRetryDelay)
+	c.setRetryOptions(&c.Jira.MaxRetries, &c.Jira.InitialRetryDelay, &c.Jira.MaxRetryDelay)
+
+	if c.MaxRateLimitWait > 0 {
+		c.GitHub.MaxRateLimitWait = c.MaxRateLimitWait
+		c.GitLab.MaxRateLimitWait = c.MaxRateLimitWait
+	}
+}
+
+// setRetryOptions sets the retry options of a platform to the shared retry
+// options that are set.
+func (c *Config) setRetryOptions(maxRetries *uint64, initialRetryDelay, maxRetryDelay *time.Duration) {
+	if c.MaxRetries > 0 {
+		*maxRetries = c.MaxRetries
+	}
+	if c.InitialRetryDelay > 0 {
+		*initialRetryDelay = c.InitialRetryDelay
+	}
+	if c.MaxRetryDelay > 0 {
+		*maxRetryDelay = c.MaxRetryDelay
+	}
+}
+
+// applyHTTPClient sets the HTTP client of all platforms to cache responses in
+// the cache directory and to record to or replay from the HTTP cassette, if
+// any. The platforms share the client so a single cassette holds all requests.
+func (c *Config) applyHTTPClient() error {
+	var transport http.RoundTripper
+	if c.HTTPCassette != "" {
+		t, err := newCassetteTransport(c.HTTPCassette, c.HTTPCassetteMode, []string{
+			c.GitHub.GitHubToken,
+			c.GitHub.GitHubAppPrivateKeyPEM,
+			c.GitLab.TagrepGitLabToken,
+			c.Gitea.GiteaToken,
+			c.Bitbucket.BitbucketAppPassword,
+			c.Bitbucket.BitbucketAccessToken,
+			c.AzureDevOps.AzureDevOpsToken,
+			c.Gerrit.GerritPassword,
+			c.Jira.JiraToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create cassette transport: %w", err)
+		}
+		transport = t
+	}
+	if c.CacheDir != "" {
+		transport = newCacheTransport(c.CacheDir, c.CacheTTL, c.CacheKey, transport)
+	}
+	if transport == nil {
+		return nil
+	}
+
+	c.setHTTPClient(&http.Client{Transport: transport})
+	return nil
+}
+
+// setHTTPClient sets the HTTP client of all platforms.
+func (c *Config) setHTTPClient(client *http.Client) {
+	c.httpClient = client
+	c.GitHub.httpClient = client
+	c.GitLab.httpClient = client
+	c.Gitea.httpClient = client
+	c.Bitbucket.httpClient = client
+	c.AzureDevOps.httpClient = client
+	c.Gerrit.httpClient = client
+	c.Jira.httpClient = client
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/abcxyz/tagrep/pkg/platform"
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func ExampleNewPlatform() {
+	ctx := context.Background()
+
+	client, err := platform.NewPlatform(ctx, &platform.Config{
+		Type:       platform.TypeGitHub,
+		MaxRetries: 5,
+		CacheDir:   os.TempDir(),
+		GitHub: platform.GitHubConfig{
+			GitHubToken:             os.Getenv("GITHUB_TOKEN"),
+			GitHubOwner:             "abcxyz",
+			GitHubRepo:              "tagrep",
+			GitHubPullRequestNumber: 1,
+		},
+	}, platform.WithHTTPClient(&http.Client{Timeout: 30 * time.Second}))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	body, err := client.GetRequestBody(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	parser := tags.NewTagParser(ctx, &tags.Config{StringTags: []string{"WANT_LGTM"}})
+	result, err := parser.Parse(ctx, body)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if tv, ok := result["WANT_LGTM"]; ok {
+		fmt.Printf("WANT_LGTM=%s\n", tv.Value)
+	}
+
+	// Features beyond reading bodies are implemented by the platforms that
+	// support them.
+	if labeler, ok := client.(platform.Labeler); ok {
+		if err := labeler.AddLabels(ctx, platform.ItemKindRequest, []string{"tagged"}); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+}
+
+func ExampleNewGitLab() {
+	ctx := context.Background()
+
+	client, err := platform.NewGitLab(ctx, &platform.GitLabConfig{
+		TagrepGitLabToken:     os.Getenv("GITLAB_TOKEN"),
+		GitLabBaseURL:         "https://gitlab.com/api/v4",
+		GitLabProjectID:       278964,
+		GitLabMergeRequestIID: 7,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	labels, err := client.ListLabels(ctx, platform.ItemKindRequest)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(labels)
+}
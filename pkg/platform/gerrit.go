@@ -0,0 +1,368 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/cli"
+)
+
+const (
+	// gerritResponsePrefix is prepended to every JSON response of the Gerrit
+	// REST API to prevent cross site script inclusion.
+	gerritResponsePrefix = ")]}'"
+
+	// gerritPatchsetLevelPath is the path of comments on the change itself
+	// rather than on a file.
+	gerritPatchsetLevelPath = "/PATCHSET_LEVEL"
+
+	// gerritPageSize is the number of changes requested per page.
+	gerritPageSize = 100
+)
+
+var (
+	_ Platform = (*Gerrit)(nil)
+	_ Searcher = (*Gerrit)(nil)
+
+	// gerritChangeURLSuffixRegexp matches the path of a change in a change URL,
+	// e.g. "/c/project/+/123" or "/123/".
+	gerritChangeURLSuffixRegexp = regexp.MustCompile(`(/c/.*|/[0-9]+/?)$`)
+)
+
+// Gerrit implements the Platform interface for Gerrit Code Review.
+type Gerrit struct {
+	cfg    *GerritConfig
+	client *restClient
+}
+
+// GerritConfig is the config values for the Gerrit client.
+type GerritConfig struct {
+	// Retry
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+
+	// Auth
+	GerritUsername string
+	GerritPassword string
+
+	GerritURL      string
+	GerritProject  string
+	GerritChange   string
+	GerritRevision string
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
+}
+
+type gerritPredefinedConfig struct {
+	URL      string
+	Project  string
+	Change   string
+	Revision string
+}
+
+// Load retrieves the change from the environment variables set by the Jenkins
+// Gerrit Trigger plugin and by Zuul.
+func (c *gerritPredefinedConfig) Load(getenv func(string) string) {
+	if v := getenv("GERRIT_CHANGE_URL"); v != "" {
+		c.URL = gerritChangeURLSuffixRegexp.ReplaceAllString(v, "")
+	}
+
+	for _, k := range []string{"GERRIT_PROJECT", "ZUUL_PROJECT"} {
+		if v := getenv(k); v != "" {
+			c.Project = v
+			break
+		}
+	}
+
+	for _, k := range []string{"GERRIT_CHANGE_NUMBER", "ZUUL_CHANGE"} {
+		if v := getenv(k); v != "" {
+			c.Change = v
+			break
+		}
+	}
+
+	for _, k := range []string{"GERRIT_PATCHSET_REVISION", "ZUUL_PATCHSET"} {
+		if v := getenv(k); v != "" {
+			c.Revision = v
+			break
+		}
+	}
+}
+
+func (c *GerritConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	f := set.NewSection("GERRIT OPTIONS")
+
+	cfgDefaults := &gerritPredefinedConfig{}
+	cfgDefaults.Load(set.GetEnv)
+
+	f.StringVar(&cli.StringVar{
+		Name:   "gerrit-username",
+		EnvVar: "GERRIT_USERNAME",
+		Target: &c.GerritUsername,
+		Usage:  "The Gerrit username to use with -gerrit-password.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "gerrit-password",
+		EnvVar: "GERRIT_PASSWORD",
+		Target: &c.GerritPassword,
+		Usage:  "The Gerrit HTTP password to make authenticated API calls. Anonymous API calls are made when empty.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gerrit-url",
+		EnvVar:  "GERRIT_URL",
+		Target:  &c.GerritURL,
+		Default: cfgDefaults.URL,
+		Example: "https://gerrit-review.googlesource.com",
+		Usage:   "The URL of the Gerrit instance.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gerrit-project",
+		Target:  &c.GerritProject,
+		Default: cfgDefaults.Project,
+		Example: "project-name",
+		Usage:   "The Gerrit project, used to disambiguate change numbers.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gerrit-change",
+		EnvVar:  "GERRIT_CHANGE",
+		Target:  &c.GerritChange,
+		Default: cfgDefaults.Change,
+		Example: "12345",
+		Usage:   "The Gerrit change number or Change-Id.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gerrit-revision",
+		EnvVar:  "GERRIT_REVISION",
+		Target:  &c.GerritRevision,
+		Default: cfgDefaults.Revision,
+		Usage:   "The patchset number or commit SHA of the change to parse. Defaults to the current patchset.",
+		Hidden:  true,
+	})
+}
+
+// isGerritCI reports whether the environment is a CI build of a Gerrit change.
+func isGerritCI(getenv func(string) string) bool {
+	return getenv("GERRIT_CHANGE_NUMBER") != "" || getenv("ZUUL_CHANGE") != ""
+}
+
+// NewGerrit creates a new Gerrit client.
+func NewGerrit(ctx context.Context, cfg *GerritConfig) (*Gerrit, error) {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.InitialRetryDelay <= 0 {
+		cfg.InitialRetryDelay = 1 * time.Second
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = 20 * time.Second
+	}
+	if cfg.GerritRevision == "" {
+		cfg.GerritRevision = "current"
+	}
+
+	if cfg.GerritURL == "" {
+		return nil, fmt.Errorf("gerrit url is required")
+	}
+
+	baseURL := strings.TrimSuffix(cfg.GerritURL, "/")
+	if cfg.GerritPassword != "" {
+		// Authenticated endpoints are served under the /a/ prefix.
+		baseURL += "/a"
+	}
+
+	return &Gerrit{
+		cfg: cfg,
+		client: &restClient{
+			httpClient:     cfg.httpClient,
+			baseURL:        baseURL,
+			responsePrefix: gerritResponsePrefix,
+			auth: func(r *http.Request) {
+				if cfg.GerritPassword != "" {
+					r.SetBasicAuth(cfg.GerritUsername, cfg.GerritPassword)
+				}
+			},
+		},
+	}, nil
+}
+
+// gerritChange is the subset of a Gerrit ChangeInfo used by tagrep.
+type gerritChange struct {
+	Number          int    `json:"_number"`
+	Project         string `json:"project"`
+	Subject         string `json:"subject"`
+	CurrentRevision string `json:"current_revision"`
+	Revisions       map[string]struct {
+		Commit struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+	} `json:"revisions"`
+	MoreChanges bool `json:"_more_changes"`
+}
+
+// gerritComment is the subset of a Gerrit CommentInfo used by tagrep.
+type gerritComment struct {
+	Message string `json:"message"`
+	Updated string `json:"updated"`
+}
+
+// GetRequestBody gets the commit message of the patchset followed by the
+// change level comments.
+func (g *Gerrit) GetRequestBody(ctx context.Context) (string, error) {
+	if g.cfg.GerritChange == "" {
+		return "", fmt.Errorf("failed to validate inputs: gerrit change is required")
+	}
+
+	var commit struct {
+		Message string `json:"message"`
+	}
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.client.do(ctx, http.MethodGet, g.changePath("revisions", g.cfg.GerritRevision, "commit"), nil, nil, &commit)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get commit: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get change commit message: %w", err)
+	}
+
+	var comments map[string][]*gerritComment
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.client.do(ctx, http.MethodGet, g.changePath("comments"), nil, nil, &comments)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to list comments: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get change comments: %w", err)
+	}
+
+	changeComments := comments[gerritPatchsetLevelPath]
+	// Timestamps are formatted as "2006-01-02 15:04:05.000000000" in UTC, so
+	// they sort lexically.
+	sort.SliceStable(changeComments, func(i, j int) bool {
+		return changeComments[i].Updated < changeComments[j].Updated
+	})
+
+	parts := make([]string, 0, len(changeComments)+1)
+	parts = append(parts, strings.TrimSpace(commit.Message))
+	for _, c := range changeComments {
+		parts = append(parts, strings.TrimSpace(c.Message))
+	}
+
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// GetIssueBody is not supported, Gerrit has no issue tracker.
+func (g *Gerrit) GetIssueBody(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("gerrit has no issues: %w", errors.ErrUnsupported)
+}
+
+// SearchItems calls fn for every change that matches the Gerrit search query,
+// e.g. `status:open project:my-project`. See
+// https://gerrit-review.googlesource.com/Documentation/user-search.html.
+// Gerrit does not report the number of results, so pages are always fetched
+// sequentially and concurrency is ignored. The body of each item is the commit
+// message of the current patchset.
+func (g *Gerrit) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	for start := 0; ; start += gerritPageSize {
+		q := url.Values{}
+		q.Set("q", query)
+		q.Set("n", strconv.Itoa(gerritPageSize))
+		q.Set("S", strconv.Itoa(start))
+		q["o"] = []string{"CURRENT_REVISION", "CURRENT_COMMIT"}
+
+		var changes []*gerritChange
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := g.client.do(ctx, http.MethodGet, "changes/", q, nil, &changes)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to query changes: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to search items: %w", err)
+		}
+
+		for _, c := range changes {
+			if err := fn(&Item{
+				Number: c.Number,
+				URL:    g.changeURL(c),
+				Title:  c.Subject,
+				Body:   c.Revisions[c.CurrentRevision].Commit.Message,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Only the last change of a page reports whether there are more.
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			return nil
+		}
+	}
+}
+
+// changePath returns the API path of the configured change joined with the
+// escaped path segments.
+func (g *Gerrit) changePath(segments ...string) string {
+	id := g.cfg.GerritChange
+	if _, err := strconv.Atoi(id); err == nil && g.cfg.GerritProject != "" {
+		// Change numbers are only unique within a project on some hosts.
+		id = g.cfg.GerritProject + "~" + id
+	}
+
+	parts := []string{"changes", url.PathEscape(id)}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
+
+// changeURL returns the web URL of the change.
+func (g *Gerrit) changeURL(c *gerritChange) string {
+	return fmt.Sprintf("%s/c/%s/+/%d", strings.TrimSuffix(g.cfg.GerritURL, "/"), c.Project, c.Number)
+}
+
+func (g *Gerrit) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	backoff := retry.NewFibonacci(g.cfg.InitialRetryDelay)
+	backoff = retry.WithMaxRetries(retryLimit(g.cfg.MaxRetries), backoff)
+	backoff = retry.WithCappedDuration(g.cfg.MaxRetryDelay, backoff)
+
+	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
+		return fmt.Errorf("failed to execute retriable function: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestGerritPredefinedConfig_Load(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		exp  *gerritPredefinedConfig
+	}{
+		{
+			name: "gerrit_trigger",
+			env: map[string]string{
+				"GERRIT_CHANGE_URL":        "https://review.example.com/c/my/project/+/123",
+				"GERRIT_PROJECT":           "my/project",
+				"GERRIT_CHANGE_NUMBER":     "123",
+				"GERRIT_PATCHSET_REVISION": "abcdef",
+			},
+			exp: &gerritPredefinedConfig{
+				URL:      "https://review.example.com",
+				Project:  "my/project",
+				Change:   "123",
+				Revision: "abcdef",
+			},
+		},
+		{
+			name: "gerrit_trigger_legacy_url",
+			env: map[string]string{
+				"GERRIT_CHANGE_URL":    "https://review.example.com/gerrit/123",
+				"GERRIT_CHANGE_NUMBER": "123",
+			},
+			exp: &gerritPredefinedConfig{
+				URL:    "https://review.example.com/gerrit",
+				Change: "123",
+			},
+		},
+		{
+			name: "zuul",
+			env: map[string]string{
+				"ZUUL_PROJECT":  "project",
+				"ZUUL_CHANGE":   "456",
+				"ZUUL_PATCHSET": "2",
+			},
+			exp: &gerritPredefinedConfig{
+				Project:  "project",
+				Change:   "456",
+				Revision: "2",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &gerritPredefinedConfig{}
+			c.Load(func(k string) string { return tc.env[k] })
+
+			if diff := cmp.Diff(c, tc.exp); diff != "" {
+				t.Errorf("gerritPredefinedConfig not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGerrit_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/changes/project~1/revisions/current/commit",
+			"/a/changes/project~1/revisions/current/commit":
+			fmt.Fprint(w, ")]}'\n"+`{"message":"Fix the thing\n\nTAG_1=value\n\nChange-Id: I123\n"}`)
+		case "/changes/project~1/comments":
+			fmt.Fprint(w, ")]}'\n"+`{
+				"/PATCHSET_LEVEL": [
+					{"message": "TAG_3=later", "updated": "2025-01-02 00:00:00.000000000"},
+					{"message": "TAG_2=earlier", "updated": "2025-01-01 00:00:00.000000000"}
+				],
+				"main.go": [
+					{"message": "TAG_4=file-comment", "updated": "2025-01-01 00:00:00.000000000"}
+				]
+			}`)
+		case "/a/changes/project~1/comments":
+			if user, pass, _ := r.BasicAuth(); user != "user" || pass != "my-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, ")]}'\n{}")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "Not found: "+r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name   string
+		cfg    *GerritConfig
+		expErr string
+		exp    string
+	}{
+		{
+			name: "anonymous",
+			cfg: &GerritConfig{
+				GerritProject: "project",
+				GerritChange:  "1",
+			},
+			exp: "Fix the thing\n\nTAG_1=value\n\nChange-Id: I123\n\nTAG_2=earlier\n\nTAG_3=later",
+		},
+		{
+			name: "authenticated",
+			cfg: &GerritConfig{
+				GerritUsername: "user",
+				GerritPassword: "my-password",
+				GerritProject:  "project",
+				GerritChange:   "1",
+			},
+			exp: "Fix the thing\n\nTAG_1=value\n\nChange-Id: I123",
+		},
+		{
+			name: "not_found",
+			cfg: &GerritConfig{
+				GerritProject: "project",
+				GerritChange:  "2",
+			},
+			expErr: "unexpected status code 404",
+		},
+		{
+			name:   "missing_change",
+			cfg:    &GerritConfig{},
+			expErr: "gerrit change is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.GerritURL = srv.URL
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+
+			g, err := NewGerrit(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := g.GetRequestBody(ctx)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGerrit_SearchItems(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes/" || r.URL.Query().Get("q") != "status:open" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.URL.Query().Get("S") {
+		case "0":
+			fmt.Fprint(w, ")]}'\n"+`[
+				{"_number":1,"project":"p","subject":"one","current_revision":"a","revisions":{"a":{"commit":{"message":"TAG=1"}}},"_more_changes":true}
+			]`)
+		case "100":
+			fmt.Fprint(w, ")]}'\n"+`[
+				{"_number":2,"project":"p","subject":"two","current_revision":"b","revisions":{"b":{"commit":{"message":"TAG=2"}}}}
+			]`)
+		default:
+			t.Errorf("unexpected page start %q", r.URL.Query().Get("S"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	g, err := NewGerrit(ctx, &GerritConfig{GerritURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*Item
+	if err := g.SearchItems(ctx, "status:open", 4, func(item *Item) error {
+		got = append(got, item)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Item{
+		{Number: 1, URL: srv.URL + "/c/p/+/1", Title: "one", Body: "TAG=1"},
+		{Number: 2, URL: srv.URL + "/c/p/+/2", Title: "two", Body: "TAG=2"},
+	}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Errorf("items not as expected; (-got,+want): %s", diff)
+	}
+}
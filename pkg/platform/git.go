@@ -0,0 +1,180 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+)
+
+const (
+	// gitCommitSeparator separates the message of each commit in the git log
+	// output. The -z flag uses NUL which can never appear in a commit message.
+	gitCommitSeparator = "\x00"
+
+	// gitTrailerSeparator separates the full commit message from its parsed
+	// trailers.
+	gitTrailerSeparator = "\x1f"
+
+	// gitLogFormat prints the raw commit message followed by its unfolded
+	// trailers. See https://git-scm.com/docs/pretty-formats.
+	gitLogFormat = "%B%x1f%(trailers:only,unfold)"
+)
+
+var (
+	_ Platform = (*Git)(nil)
+
+	// gitTrailerPattern is a Regex pattern used to parse a single unfolded git
+	// trailer line, e.g. "Reviewed-by: Jane Doe <jane@example.com>".
+	gitTrailerPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.*)$`)
+)
+
+// Git implements the Platform interface by reading commit messages from a
+// local git checkout.
+type Git struct {
+	cfg *GitConfig
+}
+
+// GitConfig is the config values for the git source.
+type GitConfig struct {
+	GitPath          string
+	GitRevisionRange string
+}
+
+func (c *GitConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	f := set.NewSection("GIT OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "git-path",
+		EnvVar:  "TAGREP_GIT_PATH",
+		Target:  &c.GitPath,
+		Default: ".",
+		Example: "/path/to/checkout",
+		Usage:   "The path to the local git checkout to read commit messages from.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "git-revision-range",
+		EnvVar:  "TAGREP_GIT_REVISION_RANGE",
+		Target:  &c.GitRevisionRange,
+		Default: "HEAD^!",
+		Example: "origin/main..HEAD",
+		Usage: "The git revision range to read commit messages from. Tags in newer " +
+			"commits take precedence over tags in older commits.",
+	})
+}
+
+// NewGit creates a new git source.
+func NewGit(ctx context.Context, cfg *GitConfig) (*Git, error) {
+	if cfg.GitPath == "" {
+		cfg.GitPath = "."
+	}
+	if cfg.GitRevisionRange == "" {
+		return nil, fmt.Errorf("git revision range is required")
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("failed to find git executable: %w", err)
+	}
+
+	return &Git{
+		cfg: cfg,
+	}, nil
+}
+
+// GetRequestBody gets the messages and trailers of every commit in the
+// configured revision range, ordered from oldest to newest.
+func (g *Git) GetRequestBody(ctx context.Context) (string, error) {
+	out, err := g.log(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit messages: %w", err)
+	}
+
+	var builder strings.Builder
+	for _, commit := range strings.Split(out, gitCommitSeparator) {
+		if strings.TrimSpace(commit) == "" {
+			continue
+		}
+
+		message, trailers, _ := strings.Cut(commit, gitTrailerSeparator)
+		builder.WriteString(strings.TrimRight(message, "\n"))
+		builder.WriteString("\n")
+		if t := gitTrailersToTags(trailers); t != "" {
+			builder.WriteString(t)
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String(), nil
+}
+
+// GetIssueBody is not supported for git, there are no issues in a commit log.
+func (g *Git) GetIssueBody(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("issues are not supported by the git platform: %w", errors.ErrUnsupported)
+}
+
+// log runs git log over the configured revision range and returns the raw
+// output.
+func (g *Git) log(ctx context.Context) (string, error) {
+	args := []string{
+		"-C", g.cfg.GitPath,
+		"log",
+		"--reverse",
+		"-z",
+		"--format=" + gitLogFormat,
+		// The revision range comes from flags and environment variables, it must
+		// not be read as an option, e.g. --output=<file>.
+		"--end-of-options",
+		g.cfg.GitRevisionRange,
+		"--",
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, "reading commit messages",
+		"path", g.cfg.GitPath,
+		"revision_range", g.cfg.GitRevisionRange)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run git log: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// gitTrailersToTags converts unfolded git trailers into tag lines, so the
+// trailer "Want-Lgtm: all" is parsed as the tag "Want_Lgtm=all".
+func gitTrailersToTags(trailers string) string {
+	var builder strings.Builder
+	for _, line := range strings.Split(trailers, "\n") {
+		matches := gitTrailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if len(matches) != 3 {
+			continue
+		}
+		key := strings.ReplaceAll(matches[1], "-", "_")
+		builder.WriteString(fmt.Sprintf("%s=%s\n", key, matches[2]))
+	}
+	return builder.String()
+}
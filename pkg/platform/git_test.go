@@ -0,0 +1,162 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestGitTrailersToTags(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		trailers string
+		exp      string
+	}{
+		{
+			name:     "empty",
+			trailers: "",
+			exp:      "",
+		},
+		{
+			name:     "single",
+			trailers: "Want-Lgtm: all\n",
+			exp:      "Want_Lgtm=all\n",
+		},
+		{
+			name:     "multiple",
+			trailers: "Signed-off-by: Jane Doe <jane@example.com>\nJUSTIFICATION: needed for the release\n",
+			exp:      "Signed_off_by=Jane Doe <jane@example.com>\nJUSTIFICATION=needed for the release\n",
+		},
+		{
+			name:     "ignores_invalid",
+			trailers: "not a trailer\n",
+			exp:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(gitTrailersToTags(tc.trailers), tc.exp); diff != "" {
+				t.Errorf("tags not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGit_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=tagrep",
+			"GIT_AUTHOR_EMAIL=tagrep@example.com",
+			"GIT_COMMITTER_NAME=tagrep",
+			"GIT_COMMITTER_EMAIL=tagrep@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("failed to run git %v: %s: %s", args, err, out)
+		}
+	}
+
+	runGit("init", "--quiet")
+	runGit("commit", "--quiet", "--allow-empty", "-m", "first commit\n\nTAG_1=old")
+	runGit("commit", "--quiet", "--allow-empty", "-m", "second commit\n\nTAG_1=new\n\nWant-Lgtm: all")
+
+	output := filepath.Join(t.TempDir(), "output")
+
+	cases := []struct {
+		name          string
+		revisionRange string
+		exp           string
+		err           string
+		// expNoFile is a file that must not be created.
+		expNoFile string
+	}{
+		{
+			name:          "head",
+			revisionRange: "HEAD^!",
+			exp:           "second commit\n\nTAG_1=new\n\nWant-Lgtm: all\nWant_Lgtm=all\n\n",
+		},
+		{
+			name:          "range_oldest_first",
+			revisionRange: "HEAD",
+			exp:           "first commit\n\nTAG_1=old\n\nsecond commit\n\nTAG_1=new\n\nWant-Lgtm: all\nWant_Lgtm=all\n\n",
+		},
+		{
+			name:          "invalid_range",
+			revisionRange: "does-not-exist",
+			err:           "failed to read commit messages",
+		},
+		{
+			name:          "option_range",
+			revisionRange: "--output=" + output,
+			err:           "failed to read commit messages",
+			expNoFile:     output,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := NewGit(ctx, &GitConfig{
+				GitPath:          dir,
+				GitRevisionRange: tc.revisionRange,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := g.GetRequestBody(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+
+			if tc.expNoFile != "" {
+				if _, err := os.Stat(tc.expNoFile); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("expected %s to not exist, got %v", tc.expNoFile, err)
+				}
+			}
+		})
+	}
+}
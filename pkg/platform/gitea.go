@@ -0,0 +1,556 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-githubactions"
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+)
+
+// giteaPageSize is the number of results requested per page. Gitea caps this
+// at the instance's MAX_RESPONSE_ITEMS which defaults to 50.
+const giteaPageSize = 50
+
+var (
+	_ Platform    = (*Gitea)(nil)
+	_ RangeLister = (*Gitea)(nil)
+	_ Searcher    = (*Gitea)(nil)
+	_ BodyUpdater = (*Gitea)(nil)
+)
+
+// Gitea implements the Platform interface for Gitea and Forgejo.
+type Gitea struct {
+	cfg    *GiteaConfig
+	client *restClient
+}
+
+// GiteaConfig is the config values for the Gitea client.
+type GiteaConfig struct {
+	// Retry
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+
+	// Auth
+	GiteaToken string
+	GiteaOwner string
+	GiteaRepo  string
+
+	GiteaServerURL string
+
+	GiteaPullRequestNumber int
+	GiteaPullRequestBody   string
+	GiteaIssueNumber       int
+	GiteaIssueBody         string
+
+	configDefaults *giteaConfigDefaults
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
+}
+
+type giteaConfigDefaults struct {
+	Owner             string
+	Repo              string
+	PullRequestNumber int
+	PullRequestBody   string
+	IssueNumber       int
+	IssueBody         string
+}
+
+// giteaIssue is the subset of a Gitea issue or pull request used by tagrep.
+type giteaIssue struct {
+	Number      int    `json:"number"`
+	Body        string `json:"body"`
+	Title       string `json:"title"`
+	HTMLURL     string `json:"html_url"`
+	Merged      bool   `json:"merged"`
+	PullRequest *struct {
+		Merged bool `json:"merged"`
+	} `json:"pull_request"`
+}
+
+// giteaEvent is the subset of a Gitea or Forgejo Actions event payload used
+// by tagrep. Both are compatible with the GitHub Actions event payloads.
+type giteaEvent struct {
+	Number      int         `json:"number"`
+	PullRequest *giteaIssue `json:"pull_request"`
+	Issue       *giteaIssue `json:"issue"`
+}
+
+// Load retrieves the predefined Gitea or Forgejo Actions variables from the
+// environment. Both set the GITHUB_* variables for compatibility with GitHub
+// Actions, so the GitHub context can be used to read the event payload.
+func (c *giteaConfigDefaults) Load(ctx context.Context, githubContext *githubactions.GitHubContext) {
+	c.Owner, c.Repo = githubContext.Repo()
+	// ignore err because we have no way of returning an error via the flags.Register function.
+	// this is ok beause this is just for defaulting values from the environment.
+	data, _ := json.Marshal(githubContext.Event) //nolint:errchkjson // Shouldnt affect defaults
+	switch githubContext.EventName {
+	case "pull_request", "pull_request_target", "pull_request_sync",
+		"pull_request_review_approved", "pull_request_review_rejected", "pull_request_review_comment":
+		var event giteaEvent
+		if err := json.Unmarshal(data, &event); err == nil && event.PullRequest != nil {
+			c.PullRequestNumber = event.PullRequest.Number
+			if c.PullRequestNumber == 0 {
+				c.PullRequestNumber = event.Number
+			}
+			c.PullRequestBody = event.PullRequest.Body
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing gitea pull request event context failed",
+				"context_event_name", githubContext.EventName,
+				"error", err)
+		}
+	case "issues", "issue_comment":
+		var event giteaEvent
+		if err := json.Unmarshal(data, &event); err == nil && event.Issue != nil {
+			if event.Issue.PullRequest != nil {
+				// Comments on pull requests are delivered as issue comments.
+				c.PullRequestNumber = event.Issue.Number
+				c.PullRequestBody = event.Issue.Body
+			} else {
+				c.IssueNumber = event.Issue.Number
+				c.IssueBody = event.Issue.Body
+			}
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing gitea issue event context failed",
+				"context_event_name", githubContext.EventName,
+				"error", err)
+		}
+	case "":
+		logging.FromContext(ctx).InfoContext(ctx, "found no gitea context event, if you meant to run this in gitea or forgejo, something has gone wrong.")
+	default:
+		logging.FromContext(ctx).WarnContext(ctx, "unhandled tagrep gitea event context type", "context_event_name", githubContext.EventName)
+	}
+}
+
+func (c *GiteaConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	c.configDefaults = &giteaConfigDefaults{}
+	if isGiteaActions(set.GetEnv) {
+		gitHubContext, _ := githubactions.New(githubactions.WithGetenv(set.GetEnv)).Context()
+		c.configDefaults.Load(ctx, gitHubContext)
+	}
+
+	f := set.NewSection("GITEA OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitea-token",
+		EnvVar:  "GITEA_TOKEN",
+		Target:  &c.GiteaToken,
+		Default: set.GetEnv("FORGEJO_TOKEN"),
+		Usage:   "The Gitea or Forgejo access token to make API calls.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitea-server-url",
+		EnvVar:  "GITEA_SERVER_URL",
+		Target:  &c.GiteaServerURL,
+		Default: set.GetEnv("GITHUB_SERVER_URL"),
+		Example: "https://codeberg.org",
+		Usage:   "The URL of the Gitea or Forgejo instance.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitea-owner",
+		Target:  &c.GiteaOwner,
+		Default: c.configDefaults.Owner,
+		Example: "organization-name",
+		Usage:   "The Gitea repository owner.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitea-repo",
+		Target:  &c.GiteaRepo,
+		Default: c.configDefaults.Repo,
+		Example: "repository-name",
+		Usage:   "The Gitea repository name.",
+		Hidden:  true,
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "gitea-pull-request-number",
+		EnvVar:  "GITEA_PULL_REQUEST_NUMBER",
+		Target:  &c.GiteaPullRequestNumber,
+		Default: c.configDefaults.PullRequestNumber,
+		Usage:   "The Gitea pull request number.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitea-pull-request-body",
+		EnvVar:  "GITEA_PULL_REQUEST_BODY",
+		Target:  &c.GiteaPullRequestBody,
+		Default: c.configDefaults.PullRequestBody,
+		Usage:   "The Gitea pull request body.",
+		Hidden:  true,
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "gitea-issue-number",
+		EnvVar:  "GITEA_ISSUE_NUMBER",
+		Target:  &c.GiteaIssueNumber,
+		Default: c.configDefaults.IssueNumber,
+		Usage:   "The Gitea issue number.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitea-issue-body",
+		EnvVar:  "GITEA_ISSUE_BODY",
+		Target:  &c.GiteaIssueBody,
+		Default: c.configDefaults.IssueBody,
+		Usage:   "The Gitea issue body.",
+		Hidden:  true,
+	})
+
+	set.AfterParse(func(merr error) error {
+		// The number and body must derive from the same pull request or issue,
+		// reset the body from the event if the user provided a custom number.
+		userProvidedPRNumberOverride := c.configDefaults.PullRequestNumber > 0 && c.configDefaults.PullRequestNumber != c.GiteaPullRequestNumber
+		if userProvidedPRNumberOverride && c.configDefaults.PullRequestBody == c.GiteaPullRequestBody {
+			c.GiteaPullRequestBody = ""
+		}
+
+		userProvidedIssueNumberOverride := c.configDefaults.IssueNumber > 0 && c.configDefaults.IssueNumber != c.GiteaIssueNumber
+		if userProvidedIssueNumberOverride && c.configDefaults.IssueBody == c.GiteaIssueBody {
+			c.GiteaIssueBody = ""
+		}
+
+		return nil
+	})
+}
+
+// isGiteaActions reports whether the environment is a Gitea or Forgejo
+// Actions run.
+func isGiteaActions(getenv func(string) string) bool {
+	gitea, _ := strconv.ParseBool(getenv("GITEA_ACTIONS"))
+	forgejo, _ := strconv.ParseBool(getenv("FORGEJO_ACTIONS"))
+	return gitea || forgejo
+}
+
+// NewGitea creates a new Gitea client.
+func NewGitea(ctx context.Context, cfg *GiteaConfig) (*Gitea, error) {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.InitialRetryDelay <= 0 {
+		cfg.InitialRetryDelay = 1 * time.Second
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = 20 * time.Second
+	}
+
+	if cfg.GiteaServerURL == "" {
+		return nil, fmt.Errorf("gitea server url is required")
+	}
+
+	return &Gitea{
+		cfg: cfg,
+		client: &restClient{
+			httpClient: cfg.httpClient,
+			baseURL:    strings.TrimSuffix(cfg.GiteaServerURL, "/") + "/api/v1",
+			auth: func(r *http.Request) {
+				if cfg.GiteaToken != "" {
+					r.Header.Set("Authorization", "token "+cfg.GiteaToken)
+				}
+			},
+		},
+	}, nil
+}
+
+// GetRequestBody gets the Pull Request body.
+func (g *Gitea) GetRequestBody(ctx context.Context) (string, error) {
+	if g.cfg.GiteaPullRequestBody != "" {
+		return g.cfg.GiteaPullRequestBody, nil
+	}
+	if err := validateGiteaInputs(g.cfg); err != nil {
+		return "", fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var pr giteaIssue
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.client.do(ctx, http.MethodGet, g.repoPath("pulls", strconv.Itoa(g.cfg.GiteaPullRequestNumber)), nil, nil, &pr)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get pull request body: %w", err)
+	}
+
+	return pr.Body, nil
+}
+
+// GetIssueBody gets the Issue body.
+func (g *Gitea) GetIssueBody(ctx context.Context) (string, error) {
+	if g.cfg.GiteaIssueBody != "" {
+		return g.cfg.GiteaIssueBody, nil
+	}
+	if err := validateGiteaInputs(g.cfg); err != nil {
+		return "", fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var issue giteaIssue
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.client.do(ctx, http.MethodGet, g.repoPath("issues", strconv.Itoa(g.cfg.GiteaIssueNumber)), nil, nil, &issue)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get issue body: %w", err)
+	}
+
+	return issue.Body, nil
+}
+
+// ListRequestsInRange lists the merged Pull Requests associated with the
+// commits between base and head, ordered by number.
+func (g *Gitea) ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error) {
+	if err := validateGiteaRepoInputs(g.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var comparison struct {
+		Commits []struct {
+			SHA string `json:"sha"`
+		} `json:"commits"`
+	}
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.client.do(ctx, http.MethodGet, g.repoPath("compare", base+"..."+head), nil, nil, &comparison)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to compare commits: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	seen := make(map[int]struct{})
+	items := make([]*Item, 0, len(comparison.Commits))
+	for _, c := range comparison.Commits {
+		var pr giteaIssue
+		var found bool
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := g.client.do(ctx, http.MethodGet, g.repoPath("commits", c.SHA, "pull"), nil, nil, &pr)
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				// The commit was not merged by a pull request.
+				return nil
+			}
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to get pull request for commit %s: %w", c.SHA, err))
+			}
+			found = true
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+
+		if !found || !pr.Merged {
+			continue
+		}
+		if _, ok := seen[pr.Number]; ok {
+			continue
+		}
+		seen[pr.Number] = struct{}{}
+
+		items = append(items, &Item{
+			Number: pr.Number,
+			URL:    pr.HTMLURL,
+			Title:  pr.Title,
+			Body:   pr.Body,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Number < items[j].Number
+	})
+
+	return items, nil
+}
+
+// SearchItems calls fn for every Issue and Pull Request in the repository that
+// matches the query. The query is a URL encoded filter using the parameters of
+// the list repository issues API, e.g. "state=open&type=issues&q=JUSTIFICATION".
+func (g *Gitea) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	if err := validateGiteaRepoInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	filter, err := url.ParseQuery(query)
+	if err != nil {
+		return fmt.Errorf("failed to parse issue filter: %w", err)
+	}
+
+	fn = serialize(fn)
+
+	if err := fetchPages(ctx, concurrency, func(ctx context.Context, page int) (int, error) {
+		q := url.Values{}
+		for k, v := range filter {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(page))
+		q.Set("limit", strconv.Itoa(giteaPageSize))
+
+		var issues []*giteaIssue
+		var lastPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := g.client.do(ctx, http.MethodGet, g.repoPath("issues"), q, nil, &issues)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to list issues: %w", err))
+			}
+			if total, err := strconv.Atoi(resp.Header.Get("X-Total-Count")); err == nil {
+				lastPage = (total + giteaPageSize - 1) / giteaPageSize
+			}
+			return nil
+		}); err != nil {
+			return 0, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if err := fn(&Item{
+				Number: issue.Number,
+				URL:    issue.HTMLURL,
+				Title:  issue.Title,
+				Body:   issue.Body,
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		return lastPage, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRequestBody replaces the Pull Request body if it was not changed since
+// it was fetched.
+func (g *Gitea) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	if err := g.updateBody(ctx, "pulls", g.cfg.GiteaPullRequestNumber, oldBody, newBody); err != nil {
+		return fmt.Errorf("failed to update pull request body: %w", err)
+	}
+	if g.cfg.GiteaPullRequestBody != "" {
+		g.cfg.GiteaPullRequestBody = newBody
+	}
+	return nil
+}
+
+// UpdateIssueBody replaces the Issue body if it was not changed since it was
+// fetched.
+func (g *Gitea) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	if err := g.updateBody(ctx, "issues", g.cfg.GiteaIssueNumber, oldBody, newBody); err != nil {
+		return fmt.Errorf("failed to update issue body: %w", err)
+	}
+	if g.cfg.GiteaIssueBody != "" {
+		g.cfg.GiteaIssueBody = newBody
+	}
+	return nil
+}
+
+// updateBody replaces the body of the pull request or issue of the given kind
+// if it is still oldBody. The body from the event payload may be outdated, so
+// it is compared with the current body.
+func (g *Gitea) updateBody(ctx context.Context, kind string, number int, oldBody, newBody string) error {
+	if err := validateGiteaInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	path := g.repoPath(kind, strconv.Itoa(number))
+
+	var current giteaIssue
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.client.do(withCacheRevalidation(ctx), http.MethodGet, path, nil, nil, &current)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get %s %d: %w", kind, number, err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get current body: %w", err)
+	}
+
+	return compareAndSwapBody(current.Body, oldBody, newBody, func() error {
+		return g.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := g.client.do(ctx, http.MethodPatch, path, nil, map[string]string{"body": newBody}, nil)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to edit %s %d: %w", kind, number, err))
+			}
+			return nil
+		})
+	})
+}
+
+// repoPath returns the API path of the configured repository joined with the
+// escaped path segments.
+func (g *Gitea) repoPath(segments ...string) string {
+	parts := []string{"repos", url.PathEscape(g.cfg.GiteaOwner), url.PathEscape(g.cfg.GiteaRepo)}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (g *Gitea) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	backoff := retry.NewFibonacci(g.cfg.InitialRetryDelay)
+	backoff = retry.WithMaxRetries(retryLimit(g.cfg.MaxRetries), backoff)
+	backoff = retry.WithCappedDuration(g.cfg.MaxRetryDelay, backoff)
+
+	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
+		return fmt.Errorf("failed to execute retriable function: %w", err)
+	}
+	return nil
+}
+
+// validateGiteaInputs validates the required inputs.
+func validateGiteaInputs(cfg *GiteaConfig) error {
+	merr := validateGiteaRepoInputs(cfg)
+
+	if cfg.GiteaPullRequestNumber <= 0 && cfg.GiteaIssueNumber <= 0 {
+		merr = errors.Join(merr, fmt.Errorf("one of gitea pull request number or gitea issue number is required"))
+	}
+
+	return merr
+}
+
+// validateGiteaRepoInputs validates the inputs required for repository level
+// requests.
+func validateGiteaRepoInputs(cfg *GiteaConfig) error {
+	var merr error
+	if cfg.GiteaOwner == "" {
+		merr = errors.Join(merr, fmt.Errorf("gitea owner is required"))
+	}
+
+	if cfg.GiteaRepo == "" {
+		merr = errors.Join(merr, fmt.Errorf("gitea repo is required"))
+	}
+
+	return merr
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sethvargo/go-githubactions"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestGiteaConfigDefaults_Load(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name          string
+		githubContext *githubactions.GitHubContext
+		exp           *giteaConfigDefaults
+	}{
+		{
+			name: "pull_request",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "pull_request",
+				Event: map[string]any{
+					"number": 123,
+					"pull_request": map[string]any{
+						"number": 123,
+						"body":   "this-is-a-pull-request-body",
+					},
+				},
+			},
+			exp: &giteaConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				PullRequestBody:   "this-is-a-pull-request-body",
+			},
+		},
+		{
+			name: "pull_request_review_approved",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "pull_request_review_approved",
+				Event: map[string]any{
+					"pull_request": map[string]any{
+						"number": 123,
+						"body":   "this-is-a-pull-request-body",
+					},
+				},
+			},
+			exp: &giteaConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				PullRequestBody:   "this-is-a-pull-request-body",
+			},
+		},
+		{
+			name: "issues",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "issues",
+				Event: map[string]any{
+					"issue": map[string]any{
+						"number": 7,
+						"body":   "this-is-an-issue-body",
+					},
+				},
+			},
+			exp: &giteaConfigDefaults{
+				Owner:       "owner",
+				Repo:        "repo",
+				IssueNumber: 7,
+				IssueBody:   "this-is-an-issue-body",
+			},
+		},
+		{
+			name: "issue_comment_on_pull_request",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "issue_comment",
+				Event: map[string]any{
+					"issue": map[string]any{
+						"number":       9,
+						"body":         "this-is-a-pull-request-body",
+						"pull_request": map[string]any{"merged": false},
+					},
+				},
+			},
+			exp: &giteaConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 9,
+				PullRequestBody:   "this-is-a-pull-request-body",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &giteaConfigDefaults{}
+			c.Load(ctx, tc.githubContext)
+
+			if diff := cmp.Diff(c, tc.exp); diff != "" {
+				t.Errorf("giteaConfigDefaults not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitea_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "token my-token"; got != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/repo/pulls/1":
+			fmt.Fprint(w, `{"number":1,"body":"TAG_1=value"}`)
+		case "/api/v1/repos/owner/repo/issues/2":
+			fmt.Fprint(w, `{"number":2,"body":"TAG_2=value"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name   string
+		cfg    *GiteaConfig
+		issue  bool
+		expErr string
+		exp    string
+	}{
+		{
+			name: "pull_request",
+			cfg: &GiteaConfig{
+				GiteaOwner:             "owner",
+				GiteaRepo:              "repo",
+				GiteaPullRequestNumber: 1,
+			},
+			exp: "TAG_1=value",
+		},
+		{
+			name: "pull_request_body_from_event",
+			cfg: &GiteaConfig{
+				GiteaOwner:             "owner",
+				GiteaRepo:              "repo",
+				GiteaPullRequestNumber: 1,
+				GiteaPullRequestBody:   "TAG_1=from-event",
+			},
+			exp: "TAG_1=from-event",
+		},
+		{
+			name: "issue",
+			cfg: &GiteaConfig{
+				GiteaOwner:       "owner",
+				GiteaRepo:        "repo",
+				GiteaIssueNumber: 2,
+			},
+			issue: true,
+			exp:   "TAG_2=value",
+		},
+		{
+			name: "not_found",
+			cfg: &GiteaConfig{
+				GiteaOwner:             "owner",
+				GiteaRepo:              "repo",
+				GiteaPullRequestNumber: 3,
+			},
+			expErr: "unexpected status code 404",
+		},
+		{
+			name: "missing_inputs",
+			cfg:  &GiteaConfig{},
+			expErr: "gitea owner is required\n" +
+				"gitea repo is required\n" +
+				"one of gitea pull request number or gitea issue number is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.GiteaServerURL = srv.URL
+			tc.cfg.GiteaToken = "my-token"
+
+			g, err := NewGitea(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			if tc.issue {
+				got, err = g.GetIssueBody(ctx)
+			} else {
+				got, err = g.GetRequestBody(ctx)
+			}
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -19,11 +19,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v53/github"
+	"github.com/posener/complete/v2"
 	"github.com/sethvargo/go-githubactions"
 	"github.com/sethvargo/go-retry"
 	"golang.org/x/oauth2"
@@ -34,31 +39,58 @@ import (
 )
 
 var (
-	_ Platform = (*GitHub)(nil)
-
-	// ignoredStatusCodes are status codes that should not be retried. This list
-	// is taken from the GitHub REST API documentation.
-	ignoredStatusCodes = map[int]struct{}{
-		403: {},
-		422: {},
-	}
+	_ Platform          = (*GitHub)(nil)
+	_ RangeLister       = (*GitHub)(nil)
+	_ Searcher          = (*GitHub)(nil)
+	_ LinkedIssueLister = (*GitHub)(nil)
+	_ BodyUpdater       = (*GitHub)(nil)
+	_ Commenter         = (*GitHub)(nil)
+	_ StatusPublisher   = (*GitHub)(nil)
+	_ Labeler           = (*GitHub)(nil)
 )
 
 // GitHub implements the Platform interface.
 type GitHub struct {
-	cfg    *gitHubConfig
+	cfg    *GitHubConfig
 	client *github.Client
+
+	// graphQLUnsupported is set when the GraphQL schema of the GitHub instance
+	// lacks the fields used, after which the REST API is used.
+	graphQLUnsupported bool
+	// closingIssues is the first page of closing issues fetched together with
+	// the pull request body by the GraphQL API.
+	closingIssues *gitHubGraphQLConnection
+	// labels are the labels of the pull request and issue fetched together with
+	// their bodies by the GraphQL API, until the labels are changed.
+	labels map[ItemKind][]string
+	// comments are the pull request comments fetched together with the pull
+	// request body by the GraphQL API, until a comment is written.
+	comments []*gitHubGraphQLComment
+	// viewerLogin is the login of the authenticated user, once looked up.
+	viewerLogin string
 }
 
 // mergeGroupPullRequestNumberPattern is a Regex pattern used to parse the pull request number from the merge_group ref.
 var mergeGroupPullRequestNumberPattern = regexp.MustCompile(`refs\/heads\/gh-readonly-queue\/(?:.+)\/pr-(\d*)`)
 
-// gitHubConfig is the config values for the GitHub client.
-type gitHubConfig struct {
+const (
+	// gitHubCheckRunAnnotationPath is the path of check run annotations. GitHub
+	// requires a path, but the body is not a file of the repository, so the
+	// annotations are listed on the check run instead of inline in the diff.
+	gitHubCheckRunAnnotationPath = "PULL_REQUEST_BODY"
+
+	// gitHubCheckRunAnnotationsPerRequest is the maximum number of annotations
+	// per check run request.
+	gitHubCheckRunAnnotationsPerRequest = 50
+)
+
+// GitHubConfig is the config values for the GitHub client.
+type GitHubConfig struct {
 	// Retry
 	MaxRetries        uint64
 	InitialRetryDelay time.Duration
 	MaxRetryDelay     time.Duration
+	MaxRateLimitWait  time.Duration
 
 	// Auth
 	GitHubToken             string
@@ -73,6 +105,10 @@ type gitHubConfig struct {
 	GitHubServerURL string
 	GitHubAPIURL    string
 
+	// GitHubAPI is the API used to fetch items, one of gitHubAPIREST or
+	// gitHubAPIGraphQL.
+	GitHubAPI string
+
 	GitHubEventName         string
 	GitHubRunID             int64
 	GitHubRunAttempt        int64
@@ -86,6 +122,9 @@ type gitHubConfig struct {
 	GitHubActor             string
 
 	configDefaults *gitHubConfigDefaults
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
 }
 
 type gitHubConfigDefaults struct {
@@ -95,6 +134,9 @@ type gitHubConfigDefaults struct {
 	PullRequestBody   string
 	IssueNumber       int
 	IssueBody         string
+	// SHA is the head commit of the event when it differs from GITHUB_SHA, which
+	// is used to find the pull request when the event does not reference one.
+	SHA string
 }
 
 // Load retrieves the predefined GitHub CI/CD variables from environment.
@@ -156,6 +198,58 @@ func (c *gitHubConfigDefaults) Load(ctx context.Context, githubContext *githubac
 		} else {
 			logging.FromContext(ctx).WarnContext(ctx, "parsing issues event context failed", "error", err)
 		}
+	case "pull_request_review_comment":
+		var event github.PullRequestReviewCommentEvent
+		if err := json.Unmarshal(data, &event); err == nil {
+			c.PullRequestNumber = event.GetPullRequest().GetNumber()
+			c.PullRequestBody = event.GetPullRequest().GetBody()
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing pull_request_review_comment event context failed", "error", err)
+		}
+	case "issue_comment":
+		var event github.IssueCommentEvent
+		if err := json.Unmarshal(data, &event); err == nil {
+			// Comments on pull requests are delivered as issue comments, and the
+			// issue body is the pull request body.
+			if event.GetIssue().IsPullRequest() {
+				c.PullRequestNumber = event.GetIssue().GetNumber()
+				c.PullRequestBody = event.GetIssue().GetBody()
+			} else {
+				c.IssueNumber = event.GetIssue().GetNumber()
+				c.IssueBody = event.GetIssue().GetBody()
+			}
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing issue_comment event context failed", "error", err)
+		}
+	case "workflow_run":
+		var event github.WorkflowRunEvent
+		if err := json.Unmarshal(data, &event); err == nil {
+			// Pull requests from forks are not included, in which case the pull
+			// request is found from the head commit.
+			c.PullRequestNumber = firstPullRequestNumber(event.GetWorkflowRun().PullRequests)
+			c.SHA = event.GetWorkflowRun().GetHeadSHA()
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing workflow_run event context failed", "error", err)
+		}
+	case "check_run":
+		var event github.CheckRunEvent
+		if err := json.Unmarshal(data, &event); err == nil {
+			c.PullRequestNumber = firstPullRequestNumber(event.GetCheckRun().PullRequests)
+			c.SHA = event.GetCheckRun().GetHeadSHA()
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing check_run event context failed", "error", err)
+		}
+	case "check_suite":
+		var event github.CheckSuiteEvent
+		if err := json.Unmarshal(data, &event); err == nil {
+			c.PullRequestNumber = firstPullRequestNumber(event.GetCheckSuite().PullRequests)
+			c.SHA = event.GetCheckSuite().GetHeadSHA()
+		} else {
+			logging.FromContext(ctx).WarnContext(ctx, "parsing check_suite event context failed", "error", err)
+		}
+	case "push":
+		// Push events do not reference a pull request, it is found from
+		// GITHUB_SHA when needed.
 	case "":
 		logging.FromContext(ctx).InfoContext(ctx, "found no github context event, if you meant to run this in github, something has gone wrong.")
 	default:
@@ -163,7 +257,16 @@ func (c *gitHubConfigDefaults) Load(ctx context.Context, githubContext *githubac
 	}
 }
 
-func (c *gitHubConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+// firstPullRequestNumber returns the number of the first pull request, or 0 if
+// there are none.
+func firstPullRequestNumber(prs []*github.PullRequest) int {
+	if len(prs) == 0 {
+		return 0
+	}
+	return prs[0].GetNumber()
+}
+
+func (c *GitHubConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
 	gitHubContext, _ := githubactions.New().Context()
 	c.configDefaults = &gitHubConfigDefaults{}
 	c.configDefaults.Load(ctx, gitHubContext)
@@ -238,6 +341,20 @@ func (c *gitHubConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSe
 		Hidden:  true,
 	})
 
+	f.StringVar(&cli.StringVar{
+		Name:    "github-api",
+		EnvVar:  "TAGREP_GITHUB_API",
+		Target:  &c.GitHubAPI,
+		Default: gitHubAPIREST,
+		Usage: fmt.Sprintf("The GitHub API used to fetch items. Allowed values are %q. The graphql API "+
+			"fetches a pull request with its labels, comments and linked issues in a single query and falls back to "+
+			"the rest API when the GitHub instance does not support the query.", gitHubAPIs),
+		Predict: complete.PredictFunc(func(prefix string) []string {
+			return gitHubAPIs
+		}),
+		Hidden: true,
+	})
+
 	f.Int64Var(&cli.Int64Var{
 		Name:   "github-run-id",
 		EnvVar: "GITHUB_RUN_ID",
@@ -316,7 +433,7 @@ func (c *gitHubConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSe
 		Name:   "github-commit-sha",
 		EnvVar: "GITHUB_SHA",
 		Target: &c.GitHubSHA,
-		Usage:  "The GitHub SHA.",
+		Usage:  "The GitHub SHA, used to find the pull request when no pull request number is available.",
 		Hidden: true,
 	})
 
@@ -345,12 +462,24 @@ func (c *gitHubConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSe
 			c.GitHubIssueBody = ""
 		}
 
-		return nil
+		// GITHUB_SHA is the default branch commit for workflow_run and check
+		// events, so the head commit of the event is used unless the user
+		// provided a custom SHA.
+		if c.configDefaults.SHA != "" && c.GitHubSHA == set.GetEnv("GITHUB_SHA") {
+			c.GitHubSHA = c.configDefaults.SHA
+		}
+
+		c.GitHubAPI = strings.ToLower(strings.TrimSpace(c.GitHubAPI))
+		if !slices.Contains(gitHubAPIs, c.GitHubAPI) {
+			merr = errors.Join(merr, fmt.Errorf("unsupported value for github-api flag: %s", c.GitHubAPI))
+		}
+
+		return merr
 	})
 }
 
 // NewGitHub creates a new GitHub client.
-func NewGitHub(ctx context.Context, cfg *gitHubConfig) (*GitHub, error) {
+func NewGitHub(ctx context.Context, cfg *GitHubConfig) (*GitHub, error) {
 	if cfg.MaxRetries <= 0 {
 		cfg.MaxRetries = 3
 	}
@@ -360,6 +489,15 @@ func NewGitHub(ctx context.Context, cfg *gitHubConfig) (*GitHub, error) {
 	if cfg.MaxRetryDelay <= 0 {
 		cfg.MaxRetryDelay = 20 * time.Second
 	}
+	if cfg.MaxRateLimitWait <= 0 {
+		cfg.MaxRateLimitWait = 5 * time.Minute
+	}
+	if cfg.GitHubServerURL == "" {
+		cfg.GitHubServerURL = "https://github.com"
+	}
+	if cfg.GitHubAPIURL == "" {
+		cfg.GitHubAPIURL = "https://api.github.com/"
+	}
 
 	var ts oauth2.TokenSource
 	if cfg.GitHubToken != "" {
@@ -385,7 +523,11 @@ func NewGitHub(ctx context.Context, cfg *gitHubConfig) (*GitHub, error) {
 		ts = installation.SelectedReposOAuth2TokenSource(ctx, cfg.Permissions, cfg.GitHubRepo)
 	}
 
-	tc := oauth2.NewClient(ctx, ts)
+	clientCtx := ctx
+	if cfg.httpClient != nil {
+		clientCtx = context.WithValue(ctx, oauth2.HTTPClient, cfg.httpClient)
+	}
+	tc := oauth2.NewClient(clientCtx, ts)
 
 	client, err := github.NewEnterpriseClient(cfg.GitHubAPIURL, cfg.GitHubServerURL, tc)
 	if err != nil {
@@ -400,14 +542,31 @@ func NewGitHub(ctx context.Context, cfg *gitHubConfig) (*GitHub, error) {
 	return g, nil
 }
 
-// GetRequestBody gets the Pull Request body.
+// GetRequestBody gets the Pull Request body. When no pull request number is
+// configured, the pull request is found by the commit SHA.
 func (g *GitHub) GetRequestBody(ctx context.Context) (string, error) {
 	if g.cfg.GitHubPullRequestBody != "" {
 		return g.cfg.GitHubPullRequestBody, nil
 	}
+	if g.cfg.GitHubPullRequestNumber <= 0 && g.cfg.GitHubSHA != "" {
+		if err := validateGitHubRepoInputs(g.cfg); err != nil {
+			return "", fmt.Errorf("failed to validate inputs: %w", err)
+		}
+		pr, err := g.findPullRequestForCommit(ctx, g.cfg.GitHubSHA)
+		if err != nil {
+			return "", err
+		}
+		return pr.GetBody(), nil
+	}
 	if err := validateGitHubInputs(g.cfg); err != nil {
 		return "", fmt.Errorf("failed to validate inputs: %w", err)
 	}
+	if g.useGraphQL() {
+		body, err := g.graphQLRequestBody(ctx)
+		if !g.fallbackToREST(ctx, err) {
+			return body, err
+		}
+	}
 	var body string
 
 	if err := g.withRetries(ctx, func(ctx context.Context) error {
@@ -436,6 +595,12 @@ func (g *GitHub) GetIssueBody(ctx context.Context) (string, error) {
 	if err := validateGitHubInputs(g.cfg); err != nil {
 		return "", fmt.Errorf("failed to validate inputs: %w", err)
 	}
+	if g.useGraphQL() {
+		body, err := g.graphQLIssueBody(ctx)
+		if !g.fallbackToREST(ctx, err) {
+			return body, err
+		}
+	}
 	var body string
 
 	if err := g.withRetries(ctx, func(ctx context.Context) error {
@@ -454,19 +619,692 @@ func (g *GitHub) GetIssueBody(ctx context.Context) (string, error) {
 	return body, nil
 }
 
-func (g *GitHub) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
-	backoff := retry.NewFibonacci(g.cfg.InitialRetryDelay)
-	backoff = retry.WithMaxRetries(g.cfg.MaxRetries, backoff)
-	backoff = retry.WithCappedDuration(g.cfg.MaxRetryDelay, backoff)
+// ListRequestsInRange lists the merged Pull Requests associated with the
+// commits between base and head, ordered by number.
+func (g *GitHub) ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error) {
+	if err := validateGitHubRepoInputs(g.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	shas, err := g.compareCommits(ctx, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.useGraphQL() {
+		items, err := g.graphQLRequestsForCommits(ctx, shas)
+		if !g.fallbackToREST(ctx, err) {
+			return items, err
+		}
+	}
+
+	seen := make(map[int]struct{})
+	items := make([]*Item, 0, len(shas))
+	for _, sha := range shas {
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			prs, resp, err := g.client.PullRequests.ListPullRequestsWithCommit(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, sha, &github.PullRequestListOptions{
+				ListOptions: github.ListOptions{PerPage: 100},
+			})
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to list pull requests for commit %s: %w", sha, err))
+			}
+
+			for _, pr := range prs {
+				if pr.MergedAt == nil {
+					continue
+				}
+				if _, ok := seen[pr.GetNumber()]; ok {
+					continue
+				}
+				seen[pr.GetNumber()] = struct{}{}
+
+				items = append(items, &Item{
+					Number: pr.GetNumber(),
+					URL:    pr.GetHTMLURL(),
+					Title:  pr.GetTitle(),
+					Body:   pr.GetBody(),
+				})
+			}
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Number < items[j].Number
+	})
 
-	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
-		return fmt.Errorf("failed to execute retriable function: %w", err)
+	return items, nil
+}
+
+// compareCommits lists the SHAs of the commits between base and head.
+func (g *GitHub) compareCommits(ctx context.Context, base, head string) ([]string, error) {
+	var shas []string
+	compareOpts := &github.ListOptions{PerPage: 100}
+	for {
+		var nextPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			comparison, resp, err := g.client.Repositories.CompareCommits(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, base, head, compareOpts)
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to compare commits: %w", err))
+			}
+
+			for _, c := range comparison.Commits {
+				shas = append(shas, c.GetSHA())
+			}
+			nextPage = resp.NextPage
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list commits: %w", err)
+		}
+
+		if nextPage == 0 {
+			break
+		}
+		compareOpts.Page = nextPage
 	}
+	return shas, nil
+}
+
+// SearchItems calls fn for every Issue and Pull Request that matches the
+// GitHub search query. See
+// https://docs.github.com/en/search-github/searching-on-github/searching-issues-and-pull-requests.
+func (g *GitHub) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	if g.useGraphQL() {
+		// The GraphQL search is paginated with cursors and cannot fetch pages
+		// concurrently.
+		if err := g.graphQLSearchItems(ctx, query, fn); !g.fallbackToREST(ctx, err) {
+			return err
+		}
+	}
+
+	fn = serialize(fn)
+
+	// The search API allows 30 requests per minute, so the pages are fetched
+	// one after another regardless of concurrency to avoid secondary rate
+	// limits.
+	if err := fetchPages(ctx, 1, func(ctx context.Context, page int) (int, error) {
+		var issues []*github.Issue
+		var lastPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			result, resp, err := g.client.Search.Issues(ctx, query, &github.SearchOptions{
+				ListOptions: github.ListOptions{
+					Page:    page,
+					PerPage: 100,
+				},
+			})
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to search issues: %w", err))
+			}
+			issues = result.Issues
+			lastPage = resp.LastPage
+
+			return nil
+		}); err != nil {
+			return 0, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if err := fn(&Item{
+				Number: issue.GetNumber(),
+				URL:    issue.GetHTMLURL(),
+				Title:  issue.GetTitle(),
+				Body:   issue.GetBody(),
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		return lastPage, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
 	return nil
 }
 
+// findPullRequestForCommit finds the pull request associated with the commit,
+// preferring open pull requests over merged ones. Closed pull requests that
+// were not merged are ignored. See
+// https://docs.github.com/en/rest/commits/commits#list-pull-requests-associated-with-a-commit.
+func (g *GitHub) findPullRequestForCommit(ctx context.Context, sha string) (*github.PullRequest, error) {
+	var found *github.PullRequest
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		prs, resp, err := g.client.PullRequests.ListPullRequestsWithCommit(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, sha, &github.PullRequestListOptions{
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			return githubMaybeRetryable(resp, fmt.Errorf("failed to list pull requests for commit %s: %w", sha, err))
+		}
+
+		found = nil
+		for _, pr := range prs {
+			if pr.GetState() == "open" {
+				found = pr
+				return nil
+			}
+			if pr.MergedAt != nil && found == nil {
+				found = pr
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to find pull request: %w", err)
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no open or merged pull request found for commit %s", sha)
+	}
+
+	// Subsequent calls refer to the same pull request.
+	g.cfg.GitHubPullRequestNumber = found.GetNumber()
+
+	return found, nil
+}
+
+// ListLinkedIssues lists the issues the Pull Request closes when merged,
+// including issues linked manually in the development sidebar.
+func (g *GitHub) ListLinkedIssues(ctx context.Context) ([]*Item, error) {
+	if err := validateGitHubRepoInputs(g.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if g.cfg.GitHubPullRequestNumber <= 0 && g.cfg.GitHubSHA != "" {
+		if _, err := g.findPullRequestForCommit(ctx, g.cfg.GitHubSHA); err != nil {
+			return nil, err
+		}
+	}
+	if g.cfg.GitHubPullRequestNumber <= 0 {
+		return nil, fmt.Errorf("failed to validate inputs: github pull request number is required")
+	}
+
+	items := make([]*Item, 0)
+	var after *string
+	if refs := g.closingIssues; refs != nil {
+		items = append(items, refs.items()...)
+		if !refs.PageInfo.HasNextPage {
+			return items, nil
+		}
+		after = &refs.PageInfo.EndCursor
+	}
+
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ClosingIssuesReferences gitHubGraphQLConnection `json:"closingIssuesReferences"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := g.graphQLWithRetries(ctx, gitHubClosingIssuesQuery, map[string]any{
+			"owner":  g.cfg.GitHubOwner,
+			"repo":   g.cfg.GitHubRepo,
+			"number": g.cfg.GitHubPullRequestNumber,
+			"after":  after,
+		}, &data); err != nil {
+			return nil, fmt.Errorf("failed to list linked issues: %w", err)
+		}
+
+		refs := data.Repository.PullRequest.ClosingIssuesReferences
+		items = append(items, refs.items()...)
+		if !refs.PageInfo.HasNextPage {
+			return items, nil
+		}
+		after = &refs.PageInfo.EndCursor
+	}
+}
+
+// UpdateRequestBody replaces the Pull Request body if it was not changed since
+// it was fetched. When no pull request number is configured, the pull request
+// is found by the commit SHA.
+func (g *GitHub) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	if g.cfg.GitHubPullRequestNumber <= 0 && g.cfg.GitHubSHA != "" {
+		if err := validateGitHubRepoInputs(g.cfg); err != nil {
+			return fmt.Errorf("failed to validate inputs: %w", err)
+		}
+		if _, err := g.findPullRequestForCommit(ctx, g.cfg.GitHubSHA); err != nil {
+			return err
+		}
+	}
+	if err := validateGitHubInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	// The body from the event payload may be outdated, compare with the
+	// current body instead.
+	var current string
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		pr, resp, err := g.client.PullRequests.Get(withCacheRevalidation(ctx), g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubPullRequestNumber)
+		if err != nil {
+			return githubMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+		}
+		current = pr.GetBody()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get pull request body: %w", err)
+	}
+
+	if err := compareAndSwapBody(current, oldBody, newBody, func() error {
+		return g.withRetries(ctx, func(ctx context.Context) error {
+			_, resp, err := g.client.PullRequests.Edit(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubPullRequestNumber, &github.PullRequest{
+				Body: &newBody,
+			})
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to edit pull request: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to update pull request body: %w", err)
+	}
+
+	if g.cfg.GitHubPullRequestBody != "" {
+		g.cfg.GitHubPullRequestBody = newBody
+	}
+	return nil
+}
+
+// UpdateIssueBody replaces the Issue body if it was not changed since it was
+// fetched.
+func (g *GitHub) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	if err := validateGitHubInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var current string
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		issue, resp, err := g.client.Issues.Get(withCacheRevalidation(ctx), g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubIssueNumber)
+		if err != nil {
+			return githubMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
+		}
+		current = issue.GetBody()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get issue body: %w", err)
+	}
+
+	if err := compareAndSwapBody(current, oldBody, newBody, func() error {
+		return g.withRetries(ctx, func(ctx context.Context) error {
+			_, resp, err := g.client.Issues.Edit(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubIssueNumber, &github.IssueRequest{
+				Body: &newBody,
+			})
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to edit issue: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to update issue body: %w", err)
+	}
+
+	if g.cfg.GitHubIssueBody != "" {
+		g.cfg.GitHubIssueBody = newBody
+	}
+	return nil
+}
+
+// UpsertRequestComment updates the Pull Request comment containing marker, or
+// creates one if there is none. Only comments of the authenticated user or app
+// are updated, so comments quoting the marker are left alone. When no pull
+// request number is configured, the pull request is found by the commit SHA.
+func (g *GitHub) UpsertRequestComment(ctx context.Context, marker, body string) error {
+	if err := validateGitHubRepoInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if g.cfg.GitHubPullRequestNumber <= 0 && g.cfg.GitHubSHA != "" {
+		if _, err := g.findPullRequestForCommit(ctx, g.cfg.GitHubSHA); err != nil {
+			return err
+		}
+	}
+	if g.cfg.GitHubPullRequestNumber <= 0 {
+		return fmt.Errorf("failed to validate inputs: github pull request number is required")
+	}
+
+	login, err := g.authenticatedLogin(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := g.findRequestComment(ctx, marker, login)
+	if err != nil {
+		return fmt.Errorf("failed to find pull request comment: %w", err)
+	}
+
+	if existing != nil && existing.GetBody() == body {
+		return nil
+	}
+
+	g.comments = nil
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		var resp *github.Response
+		var err error
+		if existing != nil {
+			_, resp, err = g.client.Issues.EditComment(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, existing.GetID(), &github.IssueComment{
+				Body: &body,
+			})
+		} else {
+			_, resp, err = g.client.Issues.CreateComment(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubPullRequestNumber, &github.IssueComment{
+				Body: &body,
+			})
+		}
+		if err != nil {
+			return githubMaybeRetryable(resp, fmt.Errorf("failed to write comment: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to upsert pull request comment: %w", err)
+	}
+	return nil
+}
+
+// findRequestComment returns the first Pull Request comment of login that
+// contains marker, or nil if there is none. The comments fetched with the body
+// by the GraphQL API are used when there are any.
+func (g *GitHub) findRequestComment(ctx context.Context, marker, login string) (*github.IssueComment, error) {
+	if g.comments != nil {
+		for _, c := range g.comments {
+			if strings.Contains(c.Body, marker) && sameGitHubLogin(c.Author.Login, login) {
+				return &github.IssueComment{ID: github.Int64(c.DatabaseID), Body: github.String(c.Body)}, nil
+			}
+		}
+		return nil, nil
+	}
+
+	var existing *github.IssueComment
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for existing == nil {
+		var nextPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			comments, resp, err := g.client.Issues.ListComments(withCacheRevalidation(ctx), g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubPullRequestNumber, opts)
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to list comments: %w", err))
+			}
+			for _, c := range comments {
+				if strings.Contains(c.GetBody(), marker) && sameGitHubLogin(c.GetUser().GetLogin(), login) {
+					existing = c
+					break
+				}
+			}
+			nextPage = resp.NextPage
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		if nextPage == 0 {
+			break
+		}
+		opts.Page = nextPage
+	}
+	return existing, nil
+}
+
+// authenticatedLogin returns the login of the authenticated user or app. The
+// GraphQL API is used because the REST API cannot look up the bot user of an
+// installation token.
+func (g *GitHub) authenticatedLogin(ctx context.Context) (string, error) {
+	if g.viewerLogin != "" {
+		return g.viewerLogin, nil
+	}
+
+	var data struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+	if err := g.graphQLWithRetries(ctx, gitHubViewerQuery, map[string]any{}, &data); err != nil {
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+	g.viewerLogin = data.Viewer.Login
+	return g.viewerLogin, nil
+}
+
+// sameGitHubLogin reports whether the logins are the same user. The REST API
+// adds a [bot] suffix to the logins of apps that the GraphQL API omits.
+func sameGitHubLogin(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "[bot]"), strings.TrimSuffix(b, "[bot]"))
+}
+
+// PublishRequestStatus creates a check run on the head commit of the Pull
+// Request. When no pull request number is configured, the check run is created
+// on the commit SHA.
+func (g *GitHub) PublishRequestStatus(ctx context.Context, status *Status) error {
+	if err := validateGitHubRepoInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	sha := g.cfg.GitHubSHA
+	if g.cfg.GitHubPullRequestNumber > 0 {
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			pr, resp, err := g.client.PullRequests.Get(withCacheRevalidation(ctx), g.cfg.GitHubOwner, g.cfg.GitHubRepo, g.cfg.GitHubPullRequestNumber)
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to get pull request: %w", err))
+			}
+			sha = pr.GetHead().GetSHA()
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to get pull request head: %w", err)
+		}
+	}
+	if sha == "" {
+		return fmt.Errorf("failed to validate inputs: one of github pull request number or github sha is required")
+	}
+
+	conclusion := "success"
+	if !status.Success {
+		conclusion = "failure"
+	}
+
+	annotations := make([]*github.CheckRunAnnotation, 0, len(status.Annotations))
+	for _, a := range status.Annotations {
+		// Check run annotations need a line, the others are only in the summary.
+		if a.Line == 0 {
+			continue
+		}
+		level := "failure"
+		if a.Warning {
+			level = "warning"
+		}
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(gitHubCheckRunAnnotationPath),
+			StartLine:       github.Int(a.Line),
+			EndLine:         github.Int(a.Line),
+			AnnotationLevel: github.String(level),
+			Message:         github.String(a.Message),
+		})
+	}
+
+	// The annotations of a check run are limited per request, later batches are
+	// added by updating the check run.
+	first := annotations[:min(len(annotations), gitHubCheckRunAnnotationsPerRequest)]
+	output := func(annotations []*github.CheckRunAnnotation) *github.CheckRunOutput {
+		return &github.CheckRunOutput{
+			Title:       github.String(status.Title),
+			Summary:     github.String(status.Summary),
+			Annotations: annotations,
+		}
+	}
+
+	var checkRunID int64
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		run, resp, err := g.client.Checks.CreateCheckRun(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, github.CreateCheckRunOptions{
+			Name:       status.Name,
+			HeadSHA:    sha,
+			Status:     github.String("completed"),
+			Conclusion: github.String(conclusion),
+			Output:     output(first),
+		})
+		if err != nil {
+			return githubMaybeRetryable(resp, fmt.Errorf("failed to create check run: %w", err))
+		}
+		checkRunID = run.GetID()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to publish check run: %w", err)
+	}
+
+	for i := len(first); i < len(annotations); i += gitHubCheckRunAnnotationsPerRequest {
+		batch := annotations[i:min(len(annotations), i+gitHubCheckRunAnnotationsPerRequest)]
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			_, resp, err := g.client.Checks.UpdateCheckRun(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, checkRunID, github.UpdateCheckRunOptions{
+				Name:   status.Name,
+				Output: output(batch),
+			})
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to update check run: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to publish check run annotations: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListLabels lists the labels of the Pull Request or Issue. The labels fetched
+// with the body by the GraphQL API are used when there are any.
+func (g *GitHub) ListLabels(ctx context.Context, kind ItemKind) ([]string, error) {
+	number, err := g.labelsNumber(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	if labels, ok := g.labels[kind]; ok {
+		return slices.Clone(labels), nil
+	}
+
+	labels := make([]string, 0)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var nextPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			ghLabels, resp, err := g.client.Issues.ListLabelsByIssue(withCacheRevalidation(ctx), g.cfg.GitHubOwner, g.cfg.GitHubRepo, number, opts)
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to list labels: %w", err))
+			}
+			for _, l := range ghLabels {
+				labels = append(labels, l.GetName())
+			}
+			nextPage = resp.NextPage
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list %s labels: %w", kind, err)
+		}
+
+		if nextPage == 0 {
+			return labels, nil
+		}
+		opts.Page = nextPage
+	}
+}
+
+// AddLabels adds the labels to the Pull Request or Issue.
+func (g *GitHub) AddLabels(ctx context.Context, kind ItemKind, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	number, err := g.labelsNumber(ctx, kind)
+	if err != nil {
+		return err
+	}
+
+	delete(g.labels, kind)
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		_, resp, err := g.client.Issues.AddLabelsToIssue(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, number, labels)
+		if err != nil {
+			return githubMaybeRetryable(resp, fmt.Errorf("failed to add labels: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to add %s labels: %w", kind, err)
+	}
+	return nil
+}
+
+// RemoveLabels removes the labels from the Pull Request or Issue. GitHub
+// removes one label per request.
+func (g *GitHub) RemoveLabels(ctx context.Context, kind ItemKind, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	number, err := g.labelsNumber(ctx, kind)
+	if err != nil {
+		return err
+	}
+
+	delete(g.labels, kind)
+	for _, label := range labels {
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := g.client.Issues.RemoveLabelForIssue(ctx, g.cfg.GitHubOwner, g.cfg.GitHubRepo, number, label)
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				// The label was already removed.
+				return nil
+			}
+			if err != nil {
+				return githubMaybeRetryable(resp, fmt.Errorf("failed to remove label %q: %w", label, err))
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to remove %s labels: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// labelsNumber returns the number of the Pull Request or Issue whose labels to
+// change. Pull requests share the labels API of issues.
+func (g *GitHub) labelsNumber(ctx context.Context, kind ItemKind) (int, error) {
+	if err := validateGitHubRepoInputs(g.cfg); err != nil {
+		return 0, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	switch kind {
+	case ItemKindRequest:
+		if g.cfg.GitHubPullRequestNumber <= 0 && g.cfg.GitHubSHA != "" {
+			if _, err := g.findPullRequestForCommit(ctx, g.cfg.GitHubSHA); err != nil {
+				return 0, err
+			}
+		}
+		if g.cfg.GitHubPullRequestNumber <= 0 {
+			return 0, fmt.Errorf("failed to validate inputs: github pull request number is required")
+		}
+		return g.cfg.GitHubPullRequestNumber, nil
+	case ItemKindIssue:
+		if g.cfg.GitHubIssueNumber <= 0 {
+			return 0, fmt.Errorf("failed to validate inputs: github issue number is required")
+		}
+		return g.cfg.GitHubIssueNumber, nil
+	default:
+		return 0, fmt.Errorf("unsupported item kind %q", kind)
+	}
+}
+
+func (g *GitHub) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	return withRateLimitRetries(ctx, &retryOptions{
+		MaxRetries:        g.cfg.MaxRetries,
+		InitialRetryDelay: g.cfg.InitialRetryDelay,
+		MaxRetryDelay:     g.cfg.MaxRetryDelay,
+		MaxRateLimitWait:  g.cfg.MaxRateLimitWait,
+	}, retryFunc)
+}
+
 // validateGitHubInputs validates the required inputs.
-func validateGitHubInputs(cfg *gitHubConfig) error {
+func validateGitHubInputs(cfg *GitHubConfig) error {
+	merr := validateGitHubRepoInputs(cfg)
+
+	if cfg.GitHubPullRequestNumber <= 0 && cfg.GitHubIssueNumber <= 0 {
+		merr = errors.Join(merr, fmt.Errorf("one of github pull request number or github issue number is required"))
+	}
+
+	return merr
+}
+
+// validateGitHubRepoInputs validates the inputs required for repository level
+// requests.
+func validateGitHubRepoInputs(cfg *GitHubConfig) error {
 	var merr error
 	if cfg.GitHubOwner == "" {
 		merr = errors.Join(merr, fmt.Errorf("github owner is required"))
@@ -476,16 +1314,32 @@ func validateGitHubInputs(cfg *gitHubConfig) error {
 		merr = errors.Join(merr, fmt.Errorf("github repo is required"))
 	}
 
-	if cfg.GitHubPullRequestNumber <= 0 && cfg.GitHubIssueNumber <= 0 {
-		merr = errors.Join(merr, fmt.Errorf("one of github pull request number or github issue number is required"))
-	}
-
 	return merr
 }
 
+// githubMaybeRetryable marks the error as retryable if the request was rate
+// limited or failed with a server error, see httpMaybeRetryable. The client
+// also returns rate limit errors without sending the request once it knows the
+// rate limit is exhausted.
 func githubMaybeRetryable(resp *github.Response, err error) error {
-	if _, ok := ignoredStatusCodes[resp.StatusCode]; !ok {
-		return retry.RetryableError(err)
+	var rlErr *github.RateLimitError
+	if errors.As(err, &rlErr) {
+		return retry.RetryableError(&rateLimitError{
+			err:   err,
+			delay: max(time.Until(rlErr.Rate.Reset.Time), 0),
+		})
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return retry.RetryableError(&rateLimitError{
+			err:   err,
+			delay: *abuseErr.RetryAfter,
+		})
+	}
+
+	if resp == nil {
+		return httpMaybeRetryable(nil, err)
 	}
-	return err
+	return httpMaybeRetryable(resp.Response, err)
 }
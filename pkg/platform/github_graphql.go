@@ -0,0 +1,494 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+const (
+	// gitHubAPIREST fetches items with the GitHub REST API.
+	gitHubAPIREST = "rest"
+	// gitHubAPIGraphQL fetches items with the GitHub GraphQL API.
+	gitHubAPIGraphQL = "graphql"
+
+	// gitHubGraphQLCommitBatchSize is the number of commits whose pull requests
+	// are fetched in a single query.
+	gitHubGraphQLCommitBatchSize = 50
+)
+
+// gitHubAPIs are the allowed values of the github-api flag.
+var gitHubAPIs = []string{gitHubAPIGraphQL, gitHubAPIREST}
+
+// gitHubSchemaErrorCodes are the GraphQL error codes returned when a query uses
+// fields, arguments or types that are not in the schema of the GitHub
+// instance, e.g. older GitHub Enterprise Server versions.
+var gitHubSchemaErrorCodes = map[string]struct{}{
+	"undefinedField":      {},
+	"undefinedType":       {},
+	"argumentNotAccepted": {},
+}
+
+// gitHubPullRequestQuery gets the body of a pull request together with the
+// first page of its labels, its comments and the issues it closes, and the
+// login of the authenticated user that comments are written as.
+const gitHubPullRequestQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  viewer { login }
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      body
+      labels(first: 100) {
+        nodes { name }
+        pageInfo { hasNextPage endCursor }
+      }
+      comments(first: 100) {
+        nodes { databaseId body author { login } }
+        pageInfo { hasNextPage endCursor }
+      }
+      closingIssuesReferences(first: 100) {
+        nodes { number url title body }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// gitHubIssueQuery gets the body of an issue together with the first page of
+// its labels.
+const gitHubIssueQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      body
+      labels(first: 100) {
+        nodes { name }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// gitHubClosingIssuesQuery lists the issues a pull request closes when merged.
+const gitHubClosingIssuesQuery = `query($owner: String!, $repo: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      closingIssuesReferences(first: 100, after: $after) {
+        nodes { number url title body }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// gitHubViewerQuery gets the login of the authenticated user, which also
+// works for the installation tokens of GitHub Apps and GitHub Actions.
+const gitHubViewerQuery = `query {
+  viewer { login }
+}`
+
+// gitHubSearchQuery searches issues and pull requests.
+const gitHubSearchQuery = `query($query: String!, $after: String) {
+  search(query: $query, type: ISSUE, first: 100, after: $after) {
+    nodes {
+      ... on Issue { number url title body }
+      ... on PullRequest { number url title body }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// gitHubAssociatedPullRequestsFragment selects the pull requests of a commit.
+const gitHubAssociatedPullRequestsFragment = `fragment associatedPullRequests on Commit {
+  associatedPullRequests(first: 10) {
+    nodes { number url title body merged }
+  }
+}`
+
+// gitHubGraphQLItem is an issue or pull request returned by a GraphQL query.
+type gitHubGraphQLItem struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Merged bool   `json:"merged"`
+}
+
+func (i *gitHubGraphQLItem) item() *Item {
+	return &Item{
+		Number: i.Number,
+		URL:    i.URL,
+		Title:  i.Title,
+		Body:   i.Body,
+	}
+}
+
+// gitHubGraphQLPageInfo is the position of a page returned by a GraphQL
+// query.
+type gitHubGraphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// gitHubGraphQLConnection is a page of issues or pull requests returned by a
+// GraphQL query.
+type gitHubGraphQLConnection struct {
+	Nodes    []*gitHubGraphQLItem  `json:"nodes"`
+	PageInfo gitHubGraphQLPageInfo `json:"pageInfo"`
+}
+
+func (c *gitHubGraphQLConnection) items() []*Item {
+	items := make([]*Item, 0, len(c.Nodes))
+	for _, n := range c.Nodes {
+		// Search results of other types are decoded as empty nodes.
+		if n == nil || n.Number == 0 {
+			continue
+		}
+		items = append(items, n.item())
+	}
+	return items
+}
+
+// gitHubGraphQLLabels is a page of the labels of an issue or pull request
+// returned by a GraphQL query.
+type gitHubGraphQLLabels struct {
+	Nodes []struct {
+		Name string `json:"name"`
+	} `json:"nodes"`
+	PageInfo gitHubGraphQLPageInfo `json:"pageInfo"`
+}
+
+// gitHubGraphQLComments is a page of the comments of a pull request returned
+// by a GraphQL query.
+type gitHubGraphQLComments struct {
+	Nodes    []*gitHubGraphQLComment `json:"nodes"`
+	PageInfo gitHubGraphQLPageInfo   `json:"pageInfo"`
+}
+
+// gitHubGraphQLComment is a comment returned by a GraphQL query. The database
+// ID is the ID of the comment in the REST API.
+type gitHubGraphQLComment struct {
+	DatabaseID int64  `json:"databaseId"`
+	Body       string `json:"body"`
+	Author     struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// gitHubGraphQLError is the list of errors returned by a GraphQL query.
+type gitHubGraphQLError struct {
+	Errors []*gitHubGraphQLErrorEntry
+}
+
+type gitHubGraphQLErrorEntry struct {
+	Message    string `json:"message"`
+	Type       string `json:"type"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+func (e *gitHubGraphQLError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, entry := range e.Errors {
+		msgs = append(msgs, entry.Message)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// rateLimited reports whether the query failed because the rate limit was
+// exceeded.
+func (e *gitHubGraphQLError) rateLimited() bool {
+	for _, entry := range e.Errors {
+		if entry.Type == "RATE_LIMITED" {
+			return true
+		}
+	}
+	return false
+}
+
+// isGitHubSchemaError reports whether the error is caused by a query that is
+// not supported by the schema of the GitHub instance.
+func isGitHubSchemaError(err error) bool {
+	var gqlErr *gitHubGraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+	for _, entry := range gqlErr.Errors {
+		if _, ok := gitHubSchemaErrorCodes[entry.Extensions.Code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// useGraphQL reports whether items should be fetched with the GraphQL API.
+func (g *GitHub) useGraphQL() bool {
+	return g.cfg.GitHubAPI == gitHubAPIGraphQL && !g.graphQLUnsupported
+}
+
+// fallbackToREST reports whether the REST API should be used because the
+// GraphQL query failed with err. The REST API is used for all subsequent calls
+// once the schema is found not to support a query.
+func (g *GitHub) fallbackToREST(ctx context.Context, err error) bool {
+	if !isGitHubSchemaError(err) {
+		return false
+	}
+
+	logging.FromContext(ctx).WarnContext(ctx, "github graphql api does not support the query, falling back to the rest api",
+		"error", err)
+	g.graphQLUnsupported = true
+	return true
+}
+
+// graphQLRequestBody gets the Pull Request body and caches the first page of
+// the issues it closes for ListLinkedIssues.
+func (g *GitHub) graphQLRequestBody(ctx context.Context) (string, error) {
+	var data struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+		Repository struct {
+			PullRequest struct {
+				Body                    string                  `json:"body"`
+				Labels                  gitHubGraphQLLabels     `json:"labels"`
+				Comments                gitHubGraphQLComments   `json:"comments"`
+				ClosingIssuesReferences gitHubGraphQLConnection `json:"closingIssuesReferences"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	if err := g.graphQLWithRetries(ctx, gitHubPullRequestQuery, map[string]any{
+		"owner":  g.cfg.GitHubOwner,
+		"repo":   g.cfg.GitHubRepo,
+		"number": g.cfg.GitHubPullRequestNumber,
+	}, &data); err != nil {
+		return "", fmt.Errorf("failed to get pull request body: %w", err)
+	}
+
+	pr := &data.Repository.PullRequest
+	g.closingIssues = &pr.ClosingIssuesReferences
+	g.cacheLabels(ItemKindRequest, &pr.Labels)
+	if !pr.Comments.PageInfo.HasNextPage {
+		g.comments = pr.Comments.Nodes
+		if g.comments == nil {
+			g.comments = []*gitHubGraphQLComment{}
+		}
+	}
+	if data.Viewer.Login != "" {
+		g.viewerLogin = data.Viewer.Login
+	}
+	return pr.Body, nil
+}
+
+// graphQLIssueBody gets the Issue body.
+func (g *GitHub) graphQLIssueBody(ctx context.Context) (string, error) {
+	var data struct {
+		Repository struct {
+			Issue struct {
+				Body   string              `json:"body"`
+				Labels gitHubGraphQLLabels `json:"labels"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	if err := g.graphQLWithRetries(ctx, gitHubIssueQuery, map[string]any{
+		"owner":  g.cfg.GitHubOwner,
+		"repo":   g.cfg.GitHubRepo,
+		"number": g.cfg.GitHubIssueNumber,
+	}, &data); err != nil {
+		return "", fmt.Errorf("failed to get issue body: %w", err)
+	}
+
+	g.cacheLabels(ItemKindIssue, &data.Repository.Issue.Labels)
+	return data.Repository.Issue.Body, nil
+}
+
+// cacheLabels keeps the labels of the pull request or issue fetched with its
+// body, when they were all returned in the first page.
+func (g *GitHub) cacheLabels(kind ItemKind, labels *gitHubGraphQLLabels) {
+	if labels.PageInfo.HasNextPage {
+		return
+	}
+	names := make([]string, 0, len(labels.Nodes))
+	for _, n := range labels.Nodes {
+		names = append(names, n.Name)
+	}
+	if g.labels == nil {
+		g.labels = make(map[ItemKind][]string)
+	}
+	g.labels[kind] = names
+}
+
+// graphQLRequestsForCommits lists the merged Pull Requests associated with the
+// commits, ordered by number. The pull requests of a batch of commits are
+// fetched in a single query.
+func (g *GitHub) graphQLRequestsForCommits(ctx context.Context, shas []string) ([]*Item, error) {
+	seen := make(map[int]struct{})
+	items := make([]*Item, 0, len(shas))
+	for batch := range slices.Chunk(shas, gitHubGraphQLCommitBatchSize) {
+		var b strings.Builder
+		b.WriteString("query($owner: String!, $repo: String!")
+		for i := range batch {
+			fmt.Fprintf(&b, ", $c%d: GitObjectID!", i)
+		}
+		b.WriteString(") {\n  repository(owner: $owner, name: $repo) {\n")
+		for i := range batch {
+			fmt.Fprintf(&b, "    c%d: object(oid: $c%d) { ...associatedPullRequests }\n", i, i)
+		}
+		b.WriteString("  }\n}\n")
+		b.WriteString(gitHubAssociatedPullRequestsFragment)
+
+		variables := map[string]any{
+			"owner": g.cfg.GitHubOwner,
+			"repo":  g.cfg.GitHubRepo,
+		}
+		for i, sha := range batch {
+			variables[fmt.Sprintf("c%d", i)] = sha
+		}
+
+		var data struct {
+			Repository map[string]*struct {
+				AssociatedPullRequests gitHubGraphQLConnection `json:"associatedPullRequests"`
+			} `json:"repository"`
+		}
+		if err := g.graphQLWithRetries(ctx, b.String(), variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+
+		for i := range batch {
+			commit := data.Repository[fmt.Sprintf("c%d", i)]
+			if commit == nil {
+				continue
+			}
+			for _, pr := range commit.AssociatedPullRequests.Nodes {
+				if !pr.Merged {
+					continue
+				}
+				if _, ok := seen[pr.Number]; ok {
+					continue
+				}
+				seen[pr.Number] = struct{}{}
+
+				items = append(items, pr.item())
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Number < items[j].Number
+	})
+
+	return items, nil
+}
+
+// graphQLSearchItems calls fn for every Issue and Pull Request that matches the
+// GitHub search query.
+func (g *GitHub) graphQLSearchItems(ctx context.Context, query string, fn SearchFunc) error {
+	var after *string
+	for {
+		var data struct {
+			Search gitHubGraphQLConnection `json:"search"`
+		}
+		if err := g.graphQLWithRetries(ctx, gitHubSearchQuery, map[string]any{
+			"query": query,
+			"after": after,
+		}, &data); err != nil {
+			return fmt.Errorf("failed to search items: %w", err)
+		}
+
+		for _, item := range data.Search.items() {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if !data.Search.PageInfo.HasNextPage {
+			return nil
+		}
+		after = &data.Search.PageInfo.EndCursor
+	}
+}
+
+// graphQLWithRetries sends the query to the GitHub GraphQL API, retrying
+// failed requests. Errors returned by the query are not retried unless the
+// query was rate limited.
+func (g *GitHub) graphQLWithRetries(ctx context.Context, query string, variables map[string]any, out any) error {
+	return g.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := g.graphQL(ctx, query, variables, out)
+		if err == nil {
+			return nil
+		}
+
+		// Requests that could not be created fail the same way when retried.
+		if resp == nil {
+			return err
+		}
+
+		var gqlErr *gitHubGraphQLError
+		if errors.As(err, &gqlErr) {
+			// Rate limited queries succeed with a RATE_LIMITED error rather than
+			// failing with a status code.
+			if delay, ok := rateLimitResetDelay(resp.Header, time.Now()); ok && gqlErr.rateLimited() {
+				return retry.RetryableError(&rateLimitError{err: err, delay: delay})
+			}
+			return err
+		}
+		return githubMaybeRetryable(resp, err)
+	})
+}
+
+// graphQL sends the query to the GitHub GraphQL API and decodes the data of
+// the response into out.
+func (g *GitHub) graphQL(ctx context.Context, query string, variables map[string]any, out any) (*github.Response, error) {
+	// The GraphQL endpoint of GitHub Enterprise Server is /api/graphql rather
+	// than /api/v3/graphql.
+	endpoint := strings.TrimSuffix(strings.TrimSuffix(g.client.BaseURL.String(), "/"), "/v3") + "/graphql"
+
+	req, err := g.client.NewRequest(http.MethodPost, endpoint, map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graphql request: %w", err)
+	}
+
+	var result struct {
+		Data   json.RawMessage            `json:"data"`
+		Errors []*gitHubGraphQLErrorEntry `json:"errors"`
+	}
+	resp, err := g.client.Do(ctx, req, &result)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send graphql request: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return resp, fmt.Errorf("graphql request failed: %w", &gitHubGraphQLError{Errors: result.Errors})
+	}
+
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return resp, fmt.Errorf("failed to decode graphql response: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v53/github"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+// newGitHubGraphQLTestClient returns a GitHub using the GraphQL API of a GitHub
+// Enterprise Server that serves GraphQL requests with graphQL and REST requests
+// with rest. It also returns the number of GraphQL requests made.
+func newGitHubGraphQLTestClient(tb testing.TB, graphQL func(query string, variables map[string]any) string, rest http.HandlerFunc) (*GitHub, *atomic.Int32) {
+	tb.Helper()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			rest(w, r)
+			return
+		}
+		requests.Add(1)
+
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			tb.Errorf("failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, graphQL(req.Query, req.Variables))
+	}))
+	tb.Cleanup(srv.Close)
+
+	client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return &GitHub{
+		cfg: &GitHubConfig{
+			MaxRetries:              1,
+			InitialRetryDelay:       1,
+			GitHubOwner:             "owner",
+			GitHubRepo:              "repo",
+			GitHubPullRequestNumber: 2,
+			GitHubAPI:               gitHubAPIGraphQL,
+		},
+		client: client,
+	}, &requests
+}
+
+func TestGitHub_GraphQLRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name        string
+		graphQL     func(query string, variables map[string]any) string
+		expBody     string
+		expLinked   []*Item
+		expRequests int32
+	}{
+		{
+			name: "single_query",
+			graphQL: func(query string, variables map[string]any) string {
+				return `{"data":{"repository":{"pullRequest":{"body":"TAG=graphql","closingIssuesReferences":{
+					"nodes":[{"number":1,"url":"https://github.com/owner/repo/issues/1","title":"one","body":"TAG=1"}],
+					"pageInfo":{"hasNextPage":false}}}}}}`
+			},
+			expBody: "TAG=graphql",
+			expLinked: []*Item{
+				{Number: 1, URL: "https://github.com/owner/repo/issues/1", Title: "one", Body: "TAG=1"},
+			},
+			expRequests: 1,
+		},
+		{
+			name: "more_linked_issues",
+			graphQL: func(query string, variables map[string]any) string {
+				if variables["after"] == "cursor" {
+					return `{"data":{"repository":{"pullRequest":{"closingIssuesReferences":{
+						"nodes":[{"number":3,"url":"https://github.com/owner/repo/issues/3","title":"three","body":"TAG=3"}],
+						"pageInfo":{"hasNextPage":false}}}}}}`
+				}
+				return `{"data":{"repository":{"pullRequest":{"body":"TAG=graphql","closingIssuesReferences":{
+					"nodes":[{"number":1,"url":"https://github.com/owner/repo/issues/1","title":"one","body":"TAG=1"}],
+					"pageInfo":{"hasNextPage":true,"endCursor":"cursor"}}}}}}`
+			},
+			expBody: "TAG=graphql",
+			expLinked: []*Item{
+				{Number: 1, URL: "https://github.com/owner/repo/issues/1", Title: "one", Body: "TAG=1"},
+				{Number: 3, URL: "https://github.com/owner/repo/issues/3", Title: "three", Body: "TAG=3"},
+			},
+			expRequests: 2,
+		},
+		{
+			name: "fallback_to_rest",
+			graphQL: func(query string, variables map[string]any) string {
+				if !strings.Contains(query, "$after") {
+					return `{"errors":[{"message":"Field 'closingIssuesReferences' doesn't exist on type 'PullRequest'",
+						"extensions":{"code":"undefinedField"}}]}`
+				}
+				return `{"data":{"repository":{"pullRequest":{"closingIssuesReferences":{
+					"nodes":[],"pageInfo":{"hasNextPage":false}}}}}}`
+			},
+			expBody:     "TAG=rest",
+			expLinked:   []*Item{},
+			expRequests: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, requests := newGitHubGraphQLTestClient(t, tc.graphQL, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v3/repos/owner/repo/pulls/2" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, `{"number":2,"body":"TAG=rest"}`)
+			})
+
+			body, err := g.GetRequestBody(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := body, tc.expBody; got != want {
+				t.Errorf("body not as expected; got %q, want %q", got, want)
+			}
+
+			linked, err := g.ListLinkedIssues(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(linked, tc.expLinked); diff != "" {
+				t.Errorf("linked issues not as expected; (-got,+want): %s", diff)
+			}
+
+			if got, want := requests.Load(), tc.expRequests; got != want {
+				t.Errorf("graphql requests not as expected; got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestGitHub_GraphQLLabelsAndComments(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	var restReqs []string
+	g, requests := newGitHubGraphQLTestClient(t, func(query string, variables map[string]any) string {
+		return `{"data":{"viewer":{"login":"tagrep"},"repository":{"pullRequest":{"body":"TAG=graphql",
+			"labels":{"nodes":[{"name":"bug"}],"pageInfo":{"hasNextPage":false}},
+			"comments":{"nodes":[
+				{"databaseId":41,"body":"quoting <!-- marker -->","author":{"login":"octocat"}},
+				{"databaseId":42,"body":"<!-- marker --> old","author":{"login":"tagrep"}}],
+				"pageInfo":{"hasNextPage":false}},
+			"closingIssuesReferences":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}}}`
+	}, func(w http.ResponseWriter, r *http.Request) {
+		restReqs = append(restReqs, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"name":"bug"},{"name":"lgtm:all"}]`)
+		case http.MethodPost:
+			fmt.Fprint(w, `[]`)
+		default:
+			fmt.Fprint(w, `{}`)
+		}
+	})
+
+	if _, err := g.GetRequestBody(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	labels, err := g.ListLabels(ctx, ItemKindRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(labels, []string{"bug"}); diff != "" {
+		t.Errorf("labels not as expected; (-got,+want): %s", diff)
+	}
+
+	if err := g.UpsertRequestComment(ctx, "<!-- marker -->", "<!-- marker --> new"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The labels are listed again once they are changed.
+	if err := g.AddLabels(ctx, ItemKindRequest, []string{"lgtm:all"}); err != nil {
+		t.Fatal(err)
+	}
+	labels, err = g.ListLabels(ctx, ItemKindRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(labels, []string{"bug", "lgtm:all"}); diff != "" {
+		t.Errorf("labels not as expected; (-got,+want): %s", diff)
+	}
+
+	if got, want := requests.Load(), int32(1); got != want {
+		t.Errorf("graphql requests not as expected; got %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(restReqs, []string{
+		"PATCH /api/v3/repos/owner/repo/issues/comments/42",
+		"POST /api/v3/repos/owner/repo/issues/2/labels",
+		"GET /api/v3/repos/owner/repo/issues/2/labels",
+	}); diff != "" {
+		t.Errorf("rest requests not as expected; (-got,+want): %s", diff)
+	}
+}
+
+func TestGitHub_GraphQLListRequestsInRange(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	g, requests := newGitHubGraphQLTestClient(t, func(query string, variables map[string]any) string {
+		if got, want := variables["c0"], "a"; got != want {
+			t.Errorf("c0 not as expected; got %v, want %v", got, want)
+		}
+		if got, want := variables["c1"], "b"; got != want {
+			t.Errorf("c1 not as expected; got %v, want %v", got, want)
+		}
+		return `{"data":{"repository":{
+			"c0":{"associatedPullRequests":{"nodes":[
+				{"number":2,"url":"https://github.com/owner/repo/pull/2","title":"two","body":"TAG=2","merged":true},
+				{"number":4,"url":"https://github.com/owner/repo/pull/4","title":"four","body":"TAG=4","merged":false}
+			]}},
+			"c1":{"associatedPullRequests":{"nodes":[
+				{"number":1,"url":"https://github.com/owner/repo/pull/1","title":"one","body":"TAG=1","merged":true},
+				{"number":2,"url":"https://github.com/owner/repo/pull/2","title":"two","body":"TAG=2","merged":true}
+			]}}
+		}}}`
+	}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/owner/repo/compare/base...head" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"commits":[{"sha":"a"},{"sha":"b"}]}`)
+	})
+
+	got, err := g.ListRequestsInRange(ctx, "base", "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Item{
+		{Number: 1, URL: "https://github.com/owner/repo/pull/1", Title: "one", Body: "TAG=1"},
+		{Number: 2, URL: "https://github.com/owner/repo/pull/2", Title: "two", Body: "TAG=2"},
+	}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Errorf("items not as expected; (-got,+want): %s", diff)
+	}
+	if got, want := requests.Load(), int32(1); got != want {
+		t.Errorf("graphql requests not as expected; got %d, want %d", got, want)
+	}
+}
+
+func TestGitHub_GraphQLSearchItems(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	g, _ := newGitHubGraphQLTestClient(t, func(query string, variables map[string]any) string {
+		if got, want := variables["query"], "repo:owner/repo is:open"; got != want {
+			t.Errorf("query not as expected; got %v, want %v", got, want)
+		}
+		if variables["after"] == "cursor" {
+			return `{"data":{"search":{"nodes":[
+				{"number":2,"url":"https://github.com/owner/repo/pull/2","title":"two","body":"TAG=2"}
+			],"pageInfo":{"hasNextPage":false}}}}`
+		}
+		return `{"data":{"search":{"nodes":[
+			{"number":1,"url":"https://github.com/owner/repo/issues/1","title":"one","body":"TAG=1"}
+		],"pageInfo":{"hasNextPage":true,"endCursor":"cursor"}}}}`
+	}, http.NotFound)
+
+	var got []*Item
+	if err := g.SearchItems(ctx, "repo:owner/repo is:open", 4, func(item *Item) error {
+		got = append(got, item)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Item{
+		{Number: 1, URL: "https://github.com/owner/repo/issues/1", Title: "one", Body: "TAG=1"},
+		{Number: 2, URL: "https://github.com/owner/repo/pull/2", Title: "two", Body: "TAG=2"},
+	}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Errorf("items not as expected; (-got,+want): %s", diff)
+	}
+}
@@ -15,12 +15,24 @@
 package platform
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v53/github"
 	"github.com/sethvargo/go-githubactions"
 
 	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
 )
 
 func TestGitHubConfigDefaults_Load(t *testing.T) {
@@ -162,6 +174,161 @@ func TestGitHubConfigDefaults_Load(t *testing.T) {
 				PullRequestBody:   "",
 			},
 		},
+		{
+			name: "pull_request_review_comment",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "pull_request_review_comment",
+				Event: map[string]any{
+					"pull_request": map[string]any{
+						"body":   "this-is-a-pull-request-body",
+						"number": 123,
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				PullRequestBody:   "this-is-a-pull-request-body",
+			},
+		},
+		{
+			name: "issue_comment_on_issue",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "issue_comment",
+				Event: map[string]any{
+					"issue": map[string]any{
+						"body":   "this-is-an-issue-body",
+						"number": 123,
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner:       "owner",
+				Repo:        "repo",
+				IssueNumber: 123,
+				IssueBody:   "this-is-an-issue-body",
+			},
+		},
+		{
+			name: "issue_comment_on_pull_request",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "issue_comment",
+				Event: map[string]any{
+					"issue": map[string]any{
+						"body":   "this-is-a-pull-request-body",
+						"number": 123,
+						"pull_request": map[string]any{
+							"url": "https://api.github.com/repos/owner/repo/pulls/123",
+						},
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				PullRequestBody:   "this-is-a-pull-request-body",
+			},
+		},
+		{
+			name: "workflow_run",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "workflow_run",
+				Event: map[string]any{
+					"workflow_run": map[string]any{
+						"head_sha": "abc",
+						"pull_requests": []any{
+							map[string]any{"number": 123},
+						},
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				SHA:               "abc",
+			},
+		},
+		{
+			name: "workflow_run_from_fork",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "workflow_run",
+				Event: map[string]any{
+					"workflow_run": map[string]any{
+						"head_sha":      "abc",
+						"pull_requests": []any{},
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner: "owner",
+				Repo:  "repo",
+				SHA:   "abc",
+			},
+		},
+		{
+			name: "check_run",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "check_run",
+				Event: map[string]any{
+					"check_run": map[string]any{
+						"head_sha": "abc",
+						"pull_requests": []any{
+							map[string]any{"number": 123},
+						},
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				SHA:               "abc",
+			},
+		},
+		{
+			name: "check_suite",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "check_suite",
+				Event: map[string]any{
+					"check_suite": map[string]any{
+						"head_sha": "abc",
+						"pull_requests": []any{
+							map[string]any{"number": 123},
+						},
+					},
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner:             "owner",
+				Repo:              "repo",
+				PullRequestNumber: 123,
+				SHA:               "abc",
+			},
+		},
+		{
+			name: "push",
+			githubContext: &githubactions.GitHubContext{
+				Repository: "owner/repo",
+				EventName:  "push",
+				Event: map[string]any{
+					"after": "abc",
+				},
+			},
+			exp: &gitHubConfigDefaults{
+				Owner: "owner",
+				Repo:  "repo",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -177,3 +344,832 @@ func TestGitHubConfigDefaults_Load(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHub_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/owner/repo/pulls/2":
+			fmt.Fprint(w, `{"number":2,"body":"TAG=from-api"}`)
+		case "/api/v3/repos/owner/repo/commits/abc/pulls":
+			fmt.Fprint(w, `[
+				{"number":5,"state":"closed","body":"TAG=closed"},
+				{"number":6,"state":"closed","merged_at":"2025-01-01T00:00:00Z","body":"TAG=merged"},
+				{"number":7,"state":"open","body":"TAG=open"}
+			]`)
+		case "/api/v3/repos/owner/repo/commits/def/pulls":
+			fmt.Fprint(w, `[
+				{"number":5,"state":"closed","body":"TAG=closed"},
+				{"number":6,"state":"closed","merged_at":"2025-01-01T00:00:00Z","body":"TAG=merged"}
+			]`)
+		case "/api/v3/repos/owner/repo/commits/ghi/pulls":
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name      string
+		cfg       *GitHubConfig
+		expErr    string
+		exp       string
+		expNumber int
+	}{
+		{
+			name: "pull_request_body",
+			cfg: &GitHubConfig{
+				GitHubPullRequestNumber: 2,
+				GitHubPullRequestBody:   "TAG=from-event",
+			},
+			exp:       "TAG=from-event",
+			expNumber: 2,
+		},
+		{
+			name: "pull_request_number",
+			cfg: &GitHubConfig{
+				GitHubPullRequestNumber: 2,
+			},
+			exp:       "TAG=from-api",
+			expNumber: 2,
+		},
+		{
+			name: "sha_open_pull_request",
+			cfg: &GitHubConfig{
+				GitHubSHA: "abc",
+			},
+			exp:       "TAG=open",
+			expNumber: 7,
+		},
+		{
+			name: "sha_merged_pull_request",
+			cfg: &GitHubConfig{
+				GitHubSHA: "def",
+			},
+			exp:       "TAG=merged",
+			expNumber: 6,
+		},
+		{
+			name: "sha_without_pull_request",
+			cfg: &GitHubConfig{
+				GitHubSHA: "ghi",
+			},
+			expErr: "no open or merged pull request found for commit ghi",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+			tc.cfg.GitHubOwner = "owner"
+			tc.cfg.GitHubRepo = "repo"
+			g := &GitHub{
+				cfg:    tc.cfg,
+				client: client,
+			}
+
+			got, err := g.GetRequestBody(ctx)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+			if got, want := g.cfg.GitHubPullRequestNumber, tc.expNumber; got != want {
+				t.Errorf("pull request number not as expected; got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestGitHub_UpdateRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name    string
+		cfg     *GitHubConfig
+		current string
+		oldBody string
+		newBody string
+		expErr  string
+		expBody string
+		expCfg  string
+	}{
+		{
+			name:    "updated",
+			cfg:     &GitHubConfig{GitHubPullRequestNumber: 2},
+			current: "TAG=a",
+			oldBody: "TAG=a",
+			newBody: "TAG=b",
+			expBody: "TAG=b",
+		},
+		{
+			name: "event_body_updated",
+			cfg: &GitHubConfig{
+				GitHubPullRequestNumber: 2,
+				GitHubPullRequestBody:   "TAG=a",
+			},
+			current: "TAG=a",
+			oldBody: "TAG=a",
+			newBody: "TAG=b",
+			expBody: "TAG=b",
+			expCfg:  "TAG=b",
+		},
+		{
+			name: "changed",
+			cfg: &GitHubConfig{
+				GitHubPullRequestNumber: 2,
+				GitHubPullRequestBody:   "TAG=a",
+			},
+			current: "TAG=edited",
+			oldBody: "TAG=a",
+			newBody: "TAG=b",
+			expErr:  "body was changed since it was fetched",
+			expBody: "TAG=edited",
+			expCfg:  "TAG=a",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := tc.current
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v3/repos/owner/repo/pulls/2" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if r.Method == http.MethodPatch {
+					var pr github.PullRequest
+					if err := json.NewDecoder(r.Body).Decode(&pr); err != nil {
+						t.Error(err)
+					}
+					body = pr.GetBody()
+				}
+				if err := json.NewEncoder(w).Encode(&github.PullRequest{Number: github.Int(2), Body: &body}); err != nil {
+					t.Error(err)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+			tc.cfg.GitHubOwner = "owner"
+			tc.cfg.GitHubRepo = "repo"
+			g := &GitHub{
+				cfg:    tc.cfg,
+				client: client,
+			}
+
+			err = g.UpdateRequestBody(ctx, tc.oldBody, tc.newBody)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+			var changed *BodyChangedError
+			if errors.As(err, &changed) && changed.Current != tc.current {
+				t.Errorf("current body not as expected; got %q, want %q", changed.Current, tc.current)
+			}
+
+			if got, want := body, tc.expBody; got != want {
+				t.Errorf("body not as expected; got %q, want %q", got, want)
+			}
+			if got, want := g.cfg.GitHubPullRequestBody, tc.expCfg; got != want {
+				t.Errorf("event body not as expected; got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitHub_UpsertRequestComment(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	type comment struct {
+		Login string
+		Body  string
+	}
+
+	cases := []struct {
+		name        string
+		comments    []*comment
+		body        string
+		expRequests []string
+		expComments []*comment
+	}{
+		{
+			name:     "created",
+			comments: []*comment{{Login: "alice", Body: "LGTM"}},
+			body:     "<!-- marker -->\nsummary",
+			expRequests: []string{
+				"POST /api/graphql",
+				"GET /api/v3/repos/owner/repo/issues/2/comments?page=1",
+				"POST /api/v3/repos/owner/repo/issues/2/comments",
+			},
+			expComments: []*comment{
+				{Login: "alice", Body: "LGTM"},
+				{Login: "tagrep-bot[bot]", Body: "<!-- marker -->\nsummary"},
+			},
+		},
+		{
+			name: "updated_on_later_page",
+			comments: []*comment{
+				{Login: "alice", Body: "LGTM"},
+				{Login: "tagrep-bot[bot]", Body: "<!-- marker -->\nold"},
+				{Login: "bob", Body: "Thanks"},
+			},
+			body: "<!-- marker -->\nnew",
+			expRequests: []string{
+				"POST /api/graphql",
+				"GET /api/v3/repos/owner/repo/issues/2/comments?page=1",
+				"GET /api/v3/repos/owner/repo/issues/2/comments?page=2",
+				"PATCH /api/v3/repos/owner/repo/issues/comments/1",
+			},
+			expComments: []*comment{
+				{Login: "alice", Body: "LGTM"},
+				{Login: "tagrep-bot[bot]", Body: "<!-- marker -->\nnew"},
+				{Login: "bob", Body: "Thanks"},
+			},
+		},
+		{
+			name:     "unchanged",
+			comments: []*comment{{Login: "tagrep-bot[bot]", Body: "<!-- marker -->\nsummary"}},
+			body:     "<!-- marker -->\nsummary",
+			expRequests: []string{
+				"POST /api/graphql",
+				"GET /api/v3/repos/owner/repo/issues/2/comments?page=1",
+			},
+			expComments: []*comment{{Login: "tagrep-bot[bot]", Body: "<!-- marker -->\nsummary"}},
+		},
+		{
+			name:     "quoted_by_other_user",
+			comments: []*comment{{Login: "alice", Body: "> <!-- marker -->\n> old\n\nWhy?"}},
+			body:     "<!-- marker -->\nnew",
+			expRequests: []string{
+				"POST /api/graphql",
+				"GET /api/v3/repos/owner/repo/issues/2/comments?page=1",
+				"POST /api/v3/repos/owner/repo/issues/2/comments",
+			},
+			expComments: []*comment{
+				{Login: "alice", Body: "> <!-- marker -->\n> old\n\nWhy?"},
+				{Login: "tagrep-bot[bot]", Body: "<!-- marker -->\nnew"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			comments := make([]*comment, 0, len(tc.comments))
+			for _, c := range tc.comments {
+				comments = append(comments, &comment{Login: c.Login, Body: c.Body})
+			}
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var in github.IssueComment
+				if r.Method != http.MethodGet && r.URL.Path != "/api/graphql" {
+					if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+						t.Error(err)
+					}
+				}
+
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/api/graphql":
+					// The GraphQL API omits the [bot] suffix of apps.
+					requests = append(requests, "POST "+r.URL.Path)
+					fmt.Fprint(w, `{"data":{"viewer":{"login":"tagrep-bot"}}}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/issues/2/comments":
+					// Serve one comment per page.
+					page, err := strconv.Atoi(r.URL.Query().Get("page"))
+					if err != nil {
+						page = 1
+					}
+					requests = append(requests, fmt.Sprintf("GET %s?page=%d", r.URL.Path, page))
+					if page < len(comments) {
+						w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, page+1))
+					}
+					out := []*github.IssueComment{}
+					if page <= len(comments) {
+						c := comments[page-1]
+						out = append(out, &github.IssueComment{
+							ID:   github.Int64(int64(page - 1)),
+							Body: github.String(c.Body),
+							User: &github.User{Login: github.String(c.Login)},
+						})
+					}
+					if err := json.NewEncoder(w).Encode(out); err != nil {
+						t.Error(err)
+					}
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/owner/repo/issues/2/comments":
+					requests = append(requests, "POST "+r.URL.Path)
+					comments = append(comments, &comment{Login: "tagrep-bot[bot]", Body: in.GetBody()})
+					fmt.Fprint(w, "{}")
+				case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/api/v3/repos/owner/repo/issues/comments/"):
+					requests = append(requests, "PATCH "+r.URL.Path)
+					id, err := strconv.Atoi(path.Base(r.URL.Path))
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					comments[id].Body = in.GetBody()
+					fmt.Fprint(w, "{}")
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g := &GitHub{
+				cfg: &GitHubConfig{
+					MaxRetries:              1,
+					InitialRetryDelay:       1,
+					GitHubOwner:             "owner",
+					GitHubRepo:              "repo",
+					GitHubPullRequestNumber: 2,
+				},
+				client: client,
+			}
+
+			if err := g.UpsertRequestComment(ctx, "<!-- marker -->", tc.body); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(requests, tc.expRequests); diff != "" {
+				t.Errorf("requests not as expected; (-got,+want): %s", diff)
+			}
+			if diff := cmp.Diff(comments, tc.expComments); diff != "" {
+				t.Errorf("comments not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitHub_PublishRequestStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	manyAnnotations := make([]*Annotation, 0, 60)
+	for i := range 60 {
+		manyAnnotations = append(manyAnnotations, &Annotation{Line: i + 1, Message: "invalid"})
+	}
+
+	type checkRun struct {
+		Method      string
+		Path        string
+		HeadSHA     string
+		Conclusion  string
+		Title       string
+		Annotations int
+	}
+
+	cases := []struct {
+		name   string
+		cfg    *GitHubConfig
+		status *Status
+		expErr string
+		exp    []*checkRun
+	}{
+		{
+			name: "success_on_pull_request_head",
+			cfg:  &GitHubConfig{GitHubPullRequestNumber: 2, GitHubSHA: "merge-sha"},
+			status: &Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "All 1 tags are valid",
+			},
+			exp: []*checkRun{
+				{Method: "POST", Path: "/api/v3/repos/owner/repo/check-runs", HeadSHA: "head-sha", Conclusion: "success", Title: "All 1 tags are valid"},
+			},
+		},
+		{
+			name: "failure_on_sha",
+			cfg:  &GitHubConfig{GitHubSHA: "abc"},
+			status: &Status{
+				Name:        "tagrep",
+				Title:       "1 of 1 tags are invalid",
+				Annotations: []*Annotation{{Line: 3, Message: "DRAFT: invalid"}},
+			},
+			exp: []*checkRun{
+				{Method: "POST", Path: "/api/v3/repos/owner/repo/check-runs", HeadSHA: "abc", Conclusion: "failure", Title: "1 of 1 tags are invalid", Annotations: 1},
+			},
+		},
+		{
+			name: "annotation_without_line",
+			cfg:  &GitHubConfig{GitHubSHA: "abc"},
+			status: &Status{
+				Name:  "tagrep",
+				Title: "2 of 2 tags are invalid",
+				Annotations: []*Annotation{
+					{Line: 3, Message: "DRAFT: invalid"},
+					{Message: "OWNER: missing required tag"},
+				},
+			},
+			exp: []*checkRun{
+				{Method: "POST", Path: "/api/v3/repos/owner/repo/check-runs", HeadSHA: "abc", Conclusion: "failure", Title: "2 of 2 tags are invalid", Annotations: 1},
+			},
+		},
+		{
+			name: "annotations_in_batches",
+			cfg:  &GitHubConfig{GitHubSHA: "abc"},
+			status: &Status{
+				Name:        "tagrep",
+				Title:       "60 of 60 tags are invalid",
+				Annotations: manyAnnotations,
+			},
+			exp: []*checkRun{
+				{Method: "POST", Path: "/api/v3/repos/owner/repo/check-runs", HeadSHA: "abc", Conclusion: "failure", Title: "60 of 60 tags are invalid", Annotations: 50},
+				{Method: "PATCH", Path: "/api/v3/repos/owner/repo/check-runs/7", Title: "60 of 60 tags are invalid", Annotations: 10},
+			},
+		},
+		{
+			name:   "missing_sha",
+			cfg:    &GitHubConfig{},
+			status: &Status{Name: "tagrep"},
+			expErr: "one of github pull request number or github sha is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []*checkRun
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/pulls/2":
+					fmt.Fprint(w, `{"number":2,"head":{"sha":"head-sha"}}`)
+				case strings.HasPrefix(r.URL.Path, "/api/v3/repos/owner/repo/check-runs"):
+					var in struct {
+						HeadSHA    string                `json:"head_sha"`
+						Conclusion string                `json:"conclusion"`
+						Output     github.CheckRunOutput `json:"output"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+						t.Error(err)
+					}
+					got = append(got, &checkRun{
+						Method:      r.Method,
+						Path:        r.URL.Path,
+						HeadSHA:     in.HeadSHA,
+						Conclusion:  in.Conclusion,
+						Title:       in.Output.GetTitle(),
+						Annotations: len(in.Output.Annotations),
+					})
+					fmt.Fprint(w, `{"id":7}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+			tc.cfg.GitHubOwner = "owner"
+			tc.cfg.GitHubRepo = "repo"
+			g := &GitHub{
+				cfg:    tc.cfg,
+				client: client,
+			}
+
+			err = g.PublishRequestStatus(ctx, tc.status)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("check runs not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitHub_Labels(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name        string
+		kind        ItemKind
+		labels      []string
+		add         []string
+		remove      []string
+		expPath     string
+		expRequests []string
+		expLabels   []string
+	}{
+		{
+			name:    "request",
+			kind:    ItemKindRequest,
+			labels:  []string{"bug", "lgtm:some"},
+			add:     []string{"lgtm:all"},
+			remove:  []string{"lgtm:some"},
+			expPath: "/api/v3/repos/owner/repo/issues/2/labels",
+			expRequests: []string{
+				"GET /api/v3/repos/owner/repo/issues/2/labels",
+				"POST /api/v3/repos/owner/repo/issues/2/labels",
+				"DELETE /api/v3/repos/owner/repo/issues/2/labels/lgtm:some",
+			},
+			expLabels: []string{"bug", "lgtm:all"},
+		},
+		{
+			name:    "issue_remove_missing",
+			kind:    ItemKindIssue,
+			labels:  []string{"bug"},
+			remove:  []string{"lgtm:some"},
+			expPath: "/api/v3/repos/owner/repo/issues/3/labels",
+			expRequests: []string{
+				"GET /api/v3/repos/owner/repo/issues/3/labels",
+				"DELETE /api/v3/repos/owner/repo/issues/3/labels/lgtm:some",
+			},
+			expLabels: []string{"bug"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			labels := append([]string{}, tc.labels...)
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == tc.expPath:
+				case r.Method == http.MethodPost && r.URL.Path == tc.expPath:
+					var in []string
+					if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+						t.Error(err)
+					}
+					labels = append(labels, in...)
+				case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, tc.expPath+"/"):
+					i := slices.Index(labels, path.Base(r.URL.Path))
+					if i < 0 {
+						w.WriteHeader(http.StatusNotFound)
+						fmt.Fprint(w, `{"message": "Label does not exist"}`)
+						return
+					}
+					labels = slices.Delete(labels, i, i+1)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				out := make([]*github.Label, 0, len(labels))
+				for _, l := range labels {
+					out = append(out, &github.Label{Name: github.String(l)})
+				}
+				if err := json.NewEncoder(w).Encode(out); err != nil {
+					t.Error(err)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g := &GitHub{
+				cfg: &GitHubConfig{
+					MaxRetries:              1,
+					InitialRetryDelay:       1,
+					GitHubOwner:             "owner",
+					GitHubRepo:              "repo",
+					GitHubPullRequestNumber: 2,
+					GitHubIssueNumber:       3,
+				},
+				client: client,
+			}
+
+			got, err := g.ListLabels(ctx, tc.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tc.labels); diff != "" {
+				t.Errorf("labels not as expected; (-got,+want): %s", diff)
+			}
+			if err := g.AddLabels(ctx, tc.kind, tc.add); err != nil {
+				t.Fatal(err)
+			}
+			if err := g.RemoveLabels(ctx, tc.kind, tc.remove); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(requests, tc.expRequests); diff != "" {
+				t.Errorf("requests not as expected; (-got,+want): %s", diff)
+			}
+			if diff := cmp.Diff(labels, tc.expLabels); diff != "" {
+				t.Errorf("labels not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitHub_ListLinkedIssues(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		switch req.Variables["after"] {
+		case nil:
+			fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"closingIssuesReferences":{
+				"nodes":[{"number":1,"url":"https://github.com/owner/repo/issues/1","title":"one","body":"TAG=1"}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"cursor"}}}}}}`)
+		case "cursor":
+			fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"closingIssuesReferences":{
+				"nodes":[{"number":2,"url":"https://github.com/other/repo/issues/2","title":"two","body":"TAG=2"}],
+				"pageInfo":{"hasNextPage":false}}}}}}`)
+		default:
+			fmt.Fprint(w, `{"errors":[{"message":"bad cursor"}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	// GitHub Enterprise Server serves GraphQL from /api/graphql.
+	client, err := github.NewEnterpriseClient(srv.URL+"/api/v3/", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &GitHub{
+		cfg: &GitHubConfig{
+			MaxRetries:              1,
+			InitialRetryDelay:       1,
+			GitHubOwner:             "owner",
+			GitHubRepo:              "repo",
+			GitHubPullRequestNumber: 3,
+		},
+		client: client,
+	}
+
+	got, err := g.ListLinkedIssues(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Item{
+		{Number: 1, URL: "https://github.com/owner/repo/issues/1", Title: "one", Body: "TAG=1"},
+		{Number: 2, URL: "https://github.com/other/repo/issues/2", Title: "two", Body: "TAG=2"},
+	}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Errorf("items not as expected; (-got,+want): %s", diff)
+	}
+}
+
+// TestGitHub_Replay runs the GitHub platform end to end against the API
+// responses recorded in testdata/github.json.
+func TestGitHub_Replay(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name   string
+		cfg    *GitHubConfig
+		call   func(ctx context.Context, g *GitHub) (any, error)
+		exp    any
+		expErr string
+	}{
+		{
+			name: "request_body",
+			cfg:  &GitHubConfig{GitHubPullRequestNumber: 12},
+			call: func(ctx context.Context, g *GitHub) (any, error) {
+				return g.GetRequestBody(ctx)
+			},
+			exp: "Adds the feature.\n\nJUSTIFICATION=needed for the release\nBUG=12",
+		},
+		{
+			name: "request_body_from_sha",
+			cfg:  &GitHubConfig{GitHubSHA: "3333333333333333333333333333333333333333"},
+			call: func(ctx context.Context, g *GitHub) (any, error) {
+				return g.GetRequestBody(ctx)
+			},
+			exp: "JUSTIFICATION=from the push",
+		},
+		{
+			name: "request_not_found",
+			cfg:  &GitHubConfig{GitHubPullRequestNumber: 404},
+			call: func(ctx context.Context, g *GitHub) (any, error) {
+				return g.GetRequestBody(ctx)
+			},
+			exp:    "",
+			expErr: "404 Not Found",
+		},
+		{
+			name: "issue_body",
+			cfg:  &GitHubConfig{GitHubIssueNumber: 7},
+			call: func(ctx context.Context, g *GitHub) (any, error) {
+				return g.GetIssueBody(ctx)
+			},
+			exp: "Access request.\n\nJUSTIFICATION=incident 7",
+		},
+		{
+			name: "requests_in_range",
+			cfg:  &GitHubConfig{},
+			call: func(ctx context.Context, g *GitHub) (any, error) {
+				return g.ListRequestsInRange(ctx, "v0.1.0", "v0.2.0")
+			},
+			exp: []*Item{
+				{Number: 12, URL: "https://github.com/abcxyz/tagrep/pull/12", Title: "Pull request 12", Body: "JUSTIFICATION=needed for the release"},
+			},
+		},
+		{
+			name: "search_items",
+			cfg:  &GitHubConfig{},
+			call: func(ctx context.Context, g *GitHub) (any, error) {
+				var items []*Item
+				err := g.SearchItems(ctx, "is:open repo:abcxyz/tagrep JUSTIFICATION", 2, func(item *Item) error {
+					items = append(items, item)
+					return nil
+				})
+				return items, err
+			},
+			exp: []*Item{
+				{Number: 7, URL: "https://github.com/abcxyz/tagrep/issues/7", Title: "Issue 7", Body: "JUSTIFICATION=incident 7"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpClient, err := cassetteHTTPClient("testdata/github.json", CassetteModeReplay, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+			tc.cfg.GitHubToken = "test-token"
+			tc.cfg.GitHubOwner = "abcxyz"
+			tc.cfg.GitHubRepo = "tagrep"
+			tc.cfg.GitHubServerURL = "https://github.com"
+			tc.cfg.GitHubAPIURL = "https://api.github.com/"
+			tc.cfg.httpClient = httpClient
+
+			g, err := NewGitHub(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tc.call(ctx, g)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("result not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
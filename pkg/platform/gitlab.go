@@ -16,93 +16,220 @@ package platform
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sethvargo/go-retry"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
 	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
 )
 
 var (
-	_ Platform = (*GitLab)(nil)
-
-	// gitLabIgnoredStatusCodes are status codes that should not be retried. This
-	// list is taken from the GitLab REST API documentation but may not contain
-	// the full set of status codes to ignore.
-	// See https://docs.gitlab.com/ee/api/rest/troubleshooting.html#status-codes.
-	gitLabIgnoredStatusCodes = map[int]struct{}{
-		403: {},
-		405: {},
-		422: {},
-	}
+	_ Platform          = (*GitLab)(nil)
+	_ RangeLister       = (*GitLab)(nil)
+	_ Searcher          = (*GitLab)(nil)
+	_ LinkedIssueLister = (*GitLab)(nil)
+	_ BodyUpdater       = (*GitLab)(nil)
+	_ Commenter         = (*GitLab)(nil)
+	_ StatusPublisher   = (*GitLab)(nil)
+	_ Labeler           = (*GitLab)(nil)
 )
 
+// gitLabCommitStatusDescriptionLength is the maximum length of a commit status
+// description.
+const gitLabCommitStatusDescriptionLength = 255
+
 // GitLab implements the Platform interface.
 type GitLab struct {
-	cfg    *gitLabConfig
+	cfg    *GitLabConfig
 	client *gitlab.Client
+
+	// userID is the ID of the authenticated user, once looked up.
+	userID int
 }
 
-type gitLabConfig struct {
+// GitLabConfig is the config values for the GitLab client.
+type GitLabConfig struct {
 	// Retry
 	MaxRetries        uint64
 	InitialRetryDelay time.Duration
 	MaxRetryDelay     time.Duration
+	MaxRateLimitWait  time.Duration
 
 	TagrepGitLabToken string
 	GitLabBaseURL     string
 
-	GitLabProjectID       int
-	GitLabMergeRequestIID int
-	GitLabIssueIID        int
+	GitLabProjectID               int
+	GitLabMergeRequestIID         int
+	GitLabMergeRequestDescription string
+	GitLabIssueIID                int
+	GitLabIssueDescription        string
+	GitLabCommitSHA               string
+
+	configDefaults *gitLabPredefinedConfig
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
 }
 
+// gitLabMergeRequestRefPattern is a Regex pattern used to parse the merge
+// request IID from the refs GitLab creates for merge requests and merge trains.
+var gitLabMergeRequestRefPattern = regexp.MustCompile(`^refs\/merge-requests\/(\d+)\/(?:head|merge|train)$`)
+
+// gitLabMaxDescriptionLength is the number of characters GitLab truncates the
+// CI_MERGE_REQUEST_DESCRIPTION variable to.
+const gitLabMaxDescriptionLength = 2700
+
 type gitLabPredefinedConfig struct {
 	CIJobToken   string
 	CIServerHost string
 	CIProjectID  int
+	CICommitSHA  string
 	// The merge request IID is the number used in the GitLab API, and not ID.
 	// See https://docs.gitlab.com/ee/ci/variables/predefined_variables.html.
-	CIMergeRequestIID int
+	CIMergeRequestIID         int
+	CIMergeRequestDescription string
 	// The issue IID is the number used in the GitLab API, and not ID.
 	// See https://docs.gitlab.com/ee/ci/variables/predefined_variables.html.
-	CIIssueIID int
+	CIIssueIID         int
+	CIIssueDescription string
+}
+
+// gitLabWebhookPayload is the subset of a GitLab webhook payload used to
+// default values for pipelines triggered by a webhook. See
+// https://docs.gitlab.com/ee/user/project/integrations/webhook_events.html.
+type gitLabWebhookPayload struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		ID int `json:"id"`
+	} `json:"project"`
+	ObjectAttributes gitLabWebhookObject  `json:"object_attributes"`
+	Issue            *gitLabWebhookObject `json:"issue"`
+	MergeRequest     *gitLabWebhookObject `json:"merge_request"`
+}
+
+type gitLabWebhookObject struct {
+	IID         int    `json:"iid"`
+	Description string `json:"description"`
 }
 
 // Load retrieves the predefined GitLab CI/CD variables from environment. See
 // https://docs.gitlab.com/ee/ci/variables/predefined_variables.html#predefined-variables.
-func (c *gitLabPredefinedConfig) Load() {
-	if v := os.Getenv("CI_JOB_TOKEN"); v != "" {
+func (c *gitLabPredefinedConfig) Load(ctx context.Context, getenv func(string) string) {
+	if v := getenv("CI_JOB_TOKEN"); v != "" {
 		c.CIJobToken = v
 	}
 
-	if v := os.Getenv("CI_API_V4_URL"); v != "" {
+	if v := getenv("CI_API_V4_URL"); v != "" {
 		c.CIServerHost = v
 	}
 
-	if v, err := strconv.Atoi(os.Getenv("CI_PROJECT_ID")); err == nil {
+	if v, err := strconv.Atoi(getenv("CI_PROJECT_ID")); err == nil {
 		c.CIProjectID = v
 	}
 
-	if v, err := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID")); err == nil {
+	if v := getenv("CI_COMMIT_SHA"); v != "" {
+		c.CICommitSHA = v
+	}
+
+	if v, err := strconv.Atoi(getenv("CI_MERGE_REQUEST_IID")); err == nil {
 		c.CIMergeRequestIID = v
+	} else if matches := gitLabMergeRequestRefPattern.FindStringSubmatch(getenv("CI_COMMIT_REF_NAME")); len(matches) == 2 {
+		// Merge train and detached merge request pipelines run on a ref of the
+		// merge request even when the merge request variables are not exposed.
+		if v, err := strconv.Atoi(matches[1]); err == nil {
+			c.CIMergeRequestIID = v
+		}
+	}
+
+	// GitLab truncates the description, in which case it must be fetched from
+	// the API instead. Older versions do not set the truncation variable, so
+	// a description of the maximum length is assumed to be truncated.
+	if v := getenv("CI_MERGE_REQUEST_DESCRIPTION"); v != "" {
+		truncated, err := strconv.ParseBool(getenv("CI_MERGE_REQUEST_DESCRIPTION_IS_TRUNCATED"))
+		if err != nil {
+			truncated = utf8.RuneCountInString(v) >= gitLabMaxDescriptionLength
+		}
+		if !truncated {
+			c.CIMergeRequestDescription = v
+		}
 	}
 
-	if v, err := strconv.Atoi(os.Getenv("CI_ISSUE_IID")); err == nil {
+	if v, err := strconv.Atoi(getenv("CI_ISSUE_IID")); err == nil {
 		c.CIIssueIID = v
 	}
+
+	// Pipelines triggered by a webhook expose the payload as a file variable.
+	// See https://docs.gitlab.com/ee/ci/triggers/#access-webhook-payload.
+	if getenv("CI_PIPELINE_SOURCE") == "trigger" {
+		if path := getenv("TRIGGER_PAYLOAD"); path != "" {
+			c.loadWebhookPayload(ctx, path)
+		}
+	}
 }
 
-func (c *gitLabConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+// loadWebhookPayload sets the merge request or issue from the webhook payload
+// at path.
+func (c *gitLabPredefinedConfig) loadWebhookPayload(ctx context.Context, path string) {
+	logger := logging.FromContext(ctx)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.WarnContext(ctx, "reading gitlab trigger payload failed", "error", err)
+		return
+	}
+
+	var payload gitLabWebhookPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		logger.WarnContext(ctx, "parsing gitlab trigger payload failed", "error", err)
+		return
+	}
+
+	var issue, mergeRequest *gitLabWebhookObject
+	switch payload.ObjectKind {
+	case "issue":
+		issue = &payload.ObjectAttributes
+	case "merge_request":
+		mergeRequest = &payload.ObjectAttributes
+	case "note":
+		// Comments reference the issue or merge request they were made on.
+		issue, mergeRequest = payload.Issue, payload.MergeRequest
+	default:
+		logger.WarnContext(ctx, "unhandled gitlab trigger payload kind", "object_kind", payload.ObjectKind)
+		return
+	}
+
+	if payload.Project.ID > 0 {
+		c.CIProjectID = payload.Project.ID
+	}
+	if issue != nil {
+		c.CIIssueIID = issue.IID
+		c.CIIssueDescription = issue.Description
+	}
+	if mergeRequest != nil {
+		c.CIMergeRequestIID = mergeRequest.IID
+		c.CIMergeRequestDescription = mergeRequest.Description
+	}
+}
+
+func (c *GitLabConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
 	f := set.NewSection("GITLAB OPTIONS")
 
-	cfgDefaults := &gitLabPredefinedConfig{}
-	cfgDefaults.Load()
+	c.configDefaults = &gitLabPredefinedConfig{}
+	c.configDefaults.Load(ctx, os.Getenv)
+	cfgDefaults := c.configDefaults
 
 	f.StringVar(&cli.StringVar{
 		Name:    "tagrep-gitlab-token",
@@ -141,18 +268,64 @@ func (c *gitLabConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSe
 		Hidden:  true,
 	})
 
+	f.StringVar(&cli.StringVar{
+		Name:    "gitlab-merge-request-description",
+		EnvVar:  "GITLAB_MERGE_REQUEST_DESCRIPTION",
+		Target:  &c.GitLabMergeRequestDescription,
+		Default: cfgDefaults.CIMergeRequestDescription,
+		Usage:   "The GitLab merge request description.",
+		Hidden:  true,
+	})
+
 	f.IntVar(&cli.IntVar{
 		Name:    "gitlab-issue-iid",
 		EnvVar:  "GITLAB_ISSUE_IID",
 		Target:  &c.GitLabIssueIID,
-		Default: cfgDefaults.CIMergeRequestIID,
+		Default: cfgDefaults.CIIssueIID,
 		Usage:   "The GitLab project-level issue internal ID.",
 		Hidden:  true,
 	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitlab-issue-description",
+		EnvVar:  "GITLAB_ISSUE_DESCRIPTION",
+		Target:  &c.GitLabIssueDescription,
+		Default: cfgDefaults.CIIssueDescription,
+		Usage:   "The GitLab issue description.",
+		Hidden:  true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "gitlab-commit-sha",
+		EnvVar:  "GITLAB_COMMIT_SHA",
+		Target:  &c.GitLabCommitSHA,
+		Default: cfgDefaults.CICommitSHA,
+		Usage: "The commit SHA used to find the merge request when no merge " +
+			"request internal ID is available, e.g. in push pipelines.",
+		Hidden: true,
+	})
+
+	set.AfterParse(func(merr error) error {
+		// The MergeRequestIID and MergeRequestDescription must derive from the
+		// same merge request - we reset the description from the predefined
+		// variables if the user provided a custom merge request IID.
+		if c.configDefaults.CIMergeRequestIID != c.GitLabMergeRequestIID &&
+			c.configDefaults.CIMergeRequestDescription == c.GitLabMergeRequestDescription {
+			c.GitLabMergeRequestDescription = ""
+		}
+
+		// The IssueIID and IssueDescription must derive from the same issue.
+		if c.configDefaults.CIIssueIID != c.GitLabIssueIID &&
+			c.configDefaults.CIIssueDescription == c.GitLabIssueDescription {
+			c.GitLabIssueDescription = ""
+		}
+
+		return nil
+	})
 }
 
 // NewGitLab creates a new GitLab client.
-func NewGitLab(ctx context.Context, cfg *gitLabConfig) (*GitLab, error) {
+func NewGitLab(ctx context.Context, cfg *GitLabConfig) (*GitLab, error) {
 	if cfg.MaxRetries <= 0 {
 		cfg.MaxRetries = 3
 	}
@@ -162,12 +335,24 @@ func NewGitLab(ctx context.Context, cfg *gitLabConfig) (*GitLab, error) {
 	if cfg.MaxRetryDelay <= 0 {
 		cfg.MaxRetryDelay = 20 * time.Second
 	}
+	if cfg.MaxRateLimitWait <= 0 {
+		cfg.MaxRateLimitWait = 5 * time.Minute
+	}
 
 	if cfg.GitLabBaseURL == "" {
 		return nil, fmt.Errorf("gitlab base url is required")
 	}
 
-	c, err := gitlab.NewClient(cfg.TagrepGitLabToken, gitlab.WithBaseURL(cfg.GitLabBaseURL))
+	// Requests are retried by withRetries, so the retries of the client are
+	// disabled to honor the configured retries.
+	opts := []gitlab.ClientOptionFunc{
+		gitlab.WithBaseURL(cfg.GitLabBaseURL),
+		gitlab.WithoutRetries(),
+	}
+	if cfg.httpClient != nil {
+		opts = append(opts, gitlab.WithHTTPClient(cfg.httpClient))
+	}
+	c, err := gitlab.NewClient(cfg.TagrepGitLabToken, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gitlab client: %w", err)
 	}
@@ -178,15 +363,29 @@ func NewGitLab(ctx context.Context, cfg *gitLabConfig) (*GitLab, error) {
 	}, nil
 }
 
-// GetRequestBody gets the Merge Request description.
+// GetRequestBody gets the Merge Request description. When no merge request
+// IID is configured, the merge request is found by the commit SHA.
 func (g *GitLab) GetRequestBody(ctx context.Context) (string, error) {
+	if g.cfg.GitLabMergeRequestDescription != "" {
+		return g.cfg.GitLabMergeRequestDescription, nil
+	}
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return "", fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if g.cfg.GitLabMergeRequestIID <= 0 && g.cfg.GitLabCommitSHA != "" {
+		mr, err := g.findMergeRequestForCommit(ctx, g.cfg.GitLabCommitSHA)
+		if err != nil {
+			return "", err
+		}
+		return mr.Description, nil
+	}
 	if err := validateGitLabInputs(g.cfg); err != nil {
 		return "", fmt.Errorf("failed to validate inputs: %w", err)
 	}
 	var body string
 
 	if err := g.withRetries(ctx, func(ctx context.Context) error {
-		mr, resp, err := g.client.MergeRequests.GetMergeRequest(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, nil)
+		mr, resp, err := g.client.MergeRequests.GetMergeRequest(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, nil, gitlab.WithContext(ctx))
 		if err != nil {
 			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get merge request: %w", err))
 		}
@@ -201,13 +400,16 @@ func (g *GitLab) GetRequestBody(ctx context.Context) (string, error) {
 
 // GetIssueBody gets the body of the issue.
 func (g *GitLab) GetIssueBody(ctx context.Context) (string, error) {
-	if err := validateGitLabInputs(g.cfg); err != nil {
+	if g.cfg.GitLabIssueDescription != "" {
+		return g.cfg.GitLabIssueDescription, nil
+	}
+	if err := validateGitLabIssueInputs(g.cfg); err != nil {
 		return "", fmt.Errorf("failed to validate inputs: %w", err)
 	}
 	var body string
 
 	if err := g.withRetries(ctx, func(ctx context.Context) error {
-		mr, resp, err := g.client.Issues.GetIssue(g.cfg.GitLabProjectID, g.cfg.GitLabIssueIID, nil)
+		mr, resp, err := g.client.Issues.GetIssue(g.cfg.GitLabProjectID, g.cfg.GitLabIssueIID, gitlab.WithContext(ctx))
 		if err != nil {
 			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
 		}
@@ -220,14 +422,301 @@ func (g *GitLab) GetIssueBody(ctx context.Context) (string, error) {
 	return body, nil
 }
 
-func validateGitLabInputs(cfg *gitLabConfig) error {
+// ListRequestsInRange lists the merged Merge Requests associated with the
+// commits between base and head, ordered by IID.
+func (g *GitLab) ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error) {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var commits []*gitlab.Commit
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		compare, resp, err := g.client.Repositories.Compare(g.cfg.GitLabProjectID, &gitlab.CompareOptions{
+			From: gitlab.Ptr(base),
+			To:   gitlab.Ptr(head),
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to compare commits: %w", err))
+		}
+		commits = compare.Commits
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	seen := make(map[int]struct{})
+	items := make([]*Item, 0, len(commits))
+	for _, c := range commits {
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			mrs, resp, err := g.client.Commits.ListMergeRequestsByCommit(g.cfg.GitLabProjectID, c.ID, gitlab.WithContext(ctx))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to list merge requests for commit %s: %w", c.ID, err))
+			}
+
+			for _, mr := range mrs {
+				if mr.State != "merged" {
+					continue
+				}
+				if _, ok := seen[mr.IID]; ok {
+					continue
+				}
+				seen[mr.IID] = struct{}{}
+
+				items = append(items, &Item{
+					Number: mr.IID,
+					URL:    mr.WebURL,
+					Title:  mr.Title,
+					Body:   mr.Description,
+				})
+			}
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list merge requests: %w", err)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Number < items[j].Number
+	})
+
+	return items, nil
+}
+
+// SearchItems calls fn for every Issue in the project that matches the query.
+// The query is a URL encoded filter using the parameters of the list project
+// issues API, e.g. "state=opened&labels=aod&search=JUSTIFICATION". See
+// https://docs.gitlab.com/ee/api/issues.html#list-project-issues.
+func (g *GitLab) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	opts, err := gitLabIssueFilter(query)
+	if err != nil {
+		return fmt.Errorf("failed to parse issue filter: %w", err)
+	}
+
+	fn = serialize(fn)
+
+	// listPage calls fn for the issues of the page.
+	listPage := func(ctx context.Context, page int) (*gitlab.Response, error) {
+		pageOpts := *opts
+		pageOpts.ListOptions = gitlab.ListOptions{
+			Page:    page,
+			PerPage: 100,
+		}
+
+		var issues []*gitlab.Issue
+		var pageResp *gitlab.Response
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			result, resp, err := g.client.Issues.ListProjectIssues(g.cfg.GitLabProjectID, &pageOpts, gitlab.WithContext(ctx))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to list issues: %w", err))
+			}
+			issues, pageResp = result, resp
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if err := fn(&Item{
+				Number: issue.IID,
+				URL:    issue.WebURL,
+				Title:  issue.Title,
+				Body:   issue.Description,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return pageResp, nil
+	}
+
+	// GitLab omits the total for more than 10,000 results, in which case the
+	// pages after the first are followed one after another instead.
+	var nextPage int
+	if err := fetchPages(ctx, concurrency, func(ctx context.Context, page int) (int, error) {
+		resp, err := listPage(ctx, page)
+		if err != nil {
+			return 0, err
+		}
+		if page == 1 && resp.TotalPages == 0 {
+			nextPage = resp.NextPage
+		}
+		return resp.TotalPages, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	if err := fetchNextPages(ctx, nextPage, func(ctx context.Context, page int) (int, error) {
+		resp, err := listPage(ctx, page)
+		if err != nil {
+			return 0, err
+		}
+		return resp.NextPage, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	return nil
+}
+
+// ListLinkedIssues lists the issues the Merge Request closes when merged.
+func (g *GitLab) ListLinkedIssues(ctx context.Context) ([]*Item, error) {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return nil, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if g.cfg.GitLabMergeRequestIID <= 0 && g.cfg.GitLabCommitSHA != "" {
+		if _, err := g.findMergeRequestForCommit(ctx, g.cfg.GitLabCommitSHA); err != nil {
+			return nil, err
+		}
+	}
+	if g.cfg.GitLabMergeRequestIID <= 0 {
+		return nil, fmt.Errorf("failed to validate inputs: gitlab merge request iid is required")
+	}
+
+	items := make([]*Item, 0)
+	opts := &gitlab.GetIssuesClosedOnMergeOptions{PerPage: 100}
+	for {
+		var nextPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			issues, resp, err := g.client.MergeRequests.GetIssuesClosedOnMerge(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, opts, gitlab.WithContext(ctx))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to list closed issues: %w", err))
+			}
+
+			for _, issue := range issues {
+				items = append(items, &Item{
+					Number: issue.IID,
+					URL:    issue.WebURL,
+					Title:  issue.Title,
+					Body:   issue.Description,
+				})
+			}
+			nextPage = resp.NextPage
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list linked issues: %w", err)
+		}
+
+		if nextPage == 0 {
+			return items, nil
+		}
+		opts.Page = nextPage
+	}
+}
+
+// findMergeRequestForCommit finds the merge request the commit belongs to,
+// preferring open merge requests over merged ones. Closed merge requests are
+// ignored.
+func (g *GitLab) findMergeRequestForCommit(ctx context.Context, sha string) (*gitlab.BasicMergeRequest, error) {
+	var found *gitlab.BasicMergeRequest
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		mrs, resp, err := g.client.Commits.ListMergeRequestsByCommit(g.cfg.GitLabProjectID, sha, gitlab.WithContext(ctx))
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to list merge requests for commit %s: %w", sha, err))
+		}
+
+		found = nil
+		for _, mr := range mrs {
+			switch mr.State {
+			case "opened":
+				found = mr
+				return nil
+			case "merged":
+				if found == nil {
+					found = mr
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to find merge request: %w", err)
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no open or merged merge request found for commit %s", sha)
+	}
+
+	// Subsequent calls refer to the same merge request.
+	g.cfg.GitLabMergeRequestIID = found.IID
+
+	return found, nil
+}
+
+// gitLabIssueFilter parses a URL encoded issue filter into the options for
+// listing project issues.
+func gitLabIssueFilter(query string) (*gitlab.ListProjectIssuesOptions, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	opts := &gitlab.ListProjectIssuesOptions{}
 	var merr error
-	if cfg.GitLabProjectID <= 0 {
-		merr = errors.Join(merr, fmt.Errorf("gitlab project id is required"))
+	for k := range values {
+		v := values.Get(k)
+		switch k {
+		case "state":
+			opts.State = gitlab.Ptr(v)
+		case "labels":
+			labels := gitlab.LabelOptions(strings.Split(v, ","))
+			opts.Labels = &labels
+		case "milestone":
+			opts.Milestone = gitlab.Ptr(v)
+		case "search":
+			opts.Search = gitlab.Ptr(v)
+		case "in":
+			opts.In = gitlab.Ptr(v)
+		case "author_username":
+			opts.AuthorUsername = gitlab.Ptr(v)
+		case "assignee_username":
+			opts.AssigneeUsername = gitlab.Ptr(v)
+		case "scope":
+			opts.Scope = gitlab.Ptr(v)
+		case "issue_type":
+			opts.IssueType = gitlab.Ptr(v)
+		default:
+			merr = errors.Join(merr, fmt.Errorf("unsupported issue filter: %s", k))
+		}
 	}
 
-	if cfg.GitLabMergeRequestIID <= 0 && cfg.GitLabIssueIID <= 0 {
-		merr = errors.Join(merr, fmt.Errorf("gitlab merge request id or issue id is required"))
+	return opts, merr
+}
+
+func validateGitLabInputs(cfg *GitLabConfig) error {
+	merr := validateGitLabProjectInputs(cfg)
+
+	if cfg.GitLabMergeRequestIID <= 0 && cfg.GitLabIssueIID <= 0 && cfg.GitLabCommitSHA == "" {
+		merr = errors.Join(merr, fmt.Errorf("gitlab merge request id, issue id or commit sha is required"))
+	}
+
+	return merr
+}
+
+// validateGitLabIssueInputs validates the inputs required for issue requests.
+// Unlike merge requests, issues cannot be resolved from the commit SHA that is
+// set in every GitLab CI job.
+func validateGitLabIssueInputs(cfg *GitLabConfig) error {
+	merr := validateGitLabProjectInputs(cfg)
+
+	if cfg.GitLabIssueIID <= 0 {
+		merr = errors.Join(merr, fmt.Errorf("gitlab issue id is required"))
+	}
+
+	return merr
+}
+
+// validateGitLabProjectInputs validates the inputs required for project level
+// requests.
+func validateGitLabProjectInputs(cfg *GitLabConfig) error {
+	var merr error
+	if cfg.GitLabProjectID <= 0 {
+		merr = errors.Join(merr, fmt.Errorf("gitlab project id is required"))
 	}
 
 	if cfg.TagrepGitLabToken == "" {
@@ -237,22 +726,383 @@ func validateGitLabInputs(cfg *gitLabConfig) error {
 	return merr
 }
 
-func (g *GitLab) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
-	backoff := retry.NewFibonacci(g.cfg.InitialRetryDelay)
-	backoff = retry.WithMaxRetries(g.cfg.MaxRetries, backoff)
-	backoff = retry.WithCappedDuration(g.cfg.MaxRetryDelay, backoff)
+// UpdateRequestBody replaces the Merge Request description if it was not
+// changed since it was fetched. When no merge request IID is configured, the
+// merge request is found by the commit SHA.
+func (g *GitLab) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if g.cfg.GitLabMergeRequestIID <= 0 && g.cfg.GitLabCommitSHA != "" {
+		if _, err := g.findMergeRequestForCommit(ctx, g.cfg.GitLabCommitSHA); err != nil {
+			return err
+		}
+	}
+	if err := validateGitLabInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	// The predefined description may be outdated, compare with the current
+	// description instead.
+	var current string
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		mr, resp, err := g.client.MergeRequests.GetMergeRequest(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, nil, gitlab.WithContext(withCacheRevalidation(ctx)))
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get merge request: %w", err))
+		}
+		current = mr.Description
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get merge request description: %w", err)
+	}
+
+	if err := compareAndSwapBody(current, oldBody, newBody, func() error {
+		return g.withRetries(ctx, func(ctx context.Context) error {
+			_, resp, err := g.client.MergeRequests.UpdateMergeRequest(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, &gitlab.UpdateMergeRequestOptions{
+				Description: &newBody,
+			}, gitlab.WithContext(ctx))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to update merge request: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to update merge request description: %w", err)
+	}
 
-	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
-		return fmt.Errorf("failed to execute retriable function: %w", err)
+	if g.cfg.GitLabMergeRequestDescription != "" {
+		g.cfg.GitLabMergeRequestDescription = newBody
 	}
 	return nil
 }
 
-func gitlabMaybeRetryable(resp *gitlab.Response, err error) error {
-	if resp != nil {
-		if _, ok := gitLabIgnoredStatusCodes[resp.StatusCode]; !ok {
-			return retry.RetryableError(err)
+// UpdateIssueBody replaces the issue description if it was not changed since
+// it was fetched.
+func (g *GitLab) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	if err := validateGitLabInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	var current string
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		issue, resp, err := g.client.Issues.GetIssue(g.cfg.GitLabProjectID, g.cfg.GitLabIssueIID, gitlab.WithContext(withCacheRevalidation(ctx)))
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
+		}
+		current = issue.Description
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to get issue description: %w", err)
+	}
+
+	if err := compareAndSwapBody(current, oldBody, newBody, func() error {
+		return g.withRetries(ctx, func(ctx context.Context) error {
+			_, resp, err := g.client.Issues.UpdateIssue(g.cfg.GitLabProjectID, g.cfg.GitLabIssueIID, &gitlab.UpdateIssueOptions{
+				Description: &newBody,
+			}, gitlab.WithContext(ctx))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to update issue: %w", err))
+			}
+			return nil
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to update issue description: %w", err)
+	}
+
+	if g.cfg.GitLabIssueDescription != "" {
+		g.cfg.GitLabIssueDescription = newBody
+	}
+	return nil
+}
+
+// UpsertRequestComment updates the Merge Request note containing marker, or
+// creates one if there is none. Only notes of the authenticated user are
+// updated, so notes quoting the marker are left alone. When no merge request
+// internal ID is configured, the merge request is found by the commit SHA.
+func (g *GitLab) UpsertRequestComment(ctx context.Context, marker, body string) error {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+	if g.cfg.GitLabMergeRequestIID <= 0 && g.cfg.GitLabCommitSHA != "" {
+		if _, err := g.findMergeRequestForCommit(ctx, g.cfg.GitLabCommitSHA); err != nil {
+			return err
+		}
+	}
+	if g.cfg.GitLabMergeRequestIID <= 0 {
+		return fmt.Errorf("failed to validate inputs: gitlab merge request iid is required")
+	}
+
+	userID, err := g.authenticatedUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var existing *gitlab.Note
+	opts := &gitlab.ListMergeRequestNotesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	for existing == nil {
+		var nextPage int
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			notes, resp, err := g.client.Notes.ListMergeRequestNotes(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, opts, gitlab.WithContext(withCacheRevalidation(ctx)))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to list notes: %w", err))
+			}
+			for _, n := range notes {
+				if !n.System && n.Author.ID == userID && strings.Contains(n.Body, marker) {
+					existing = n
+					break
+				}
+			}
+			nextPage = resp.NextPage
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to find merge request note: %w", err)
 		}
+		if nextPage == 0 {
+			break
+		}
+		opts.Page = nextPage
+	}
+
+	if existing != nil && existing.Body == body {
+		return nil
+	}
+
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		var resp *gitlab.Response
+		var err error
+		if existing != nil {
+			_, resp, err = g.client.Notes.UpdateMergeRequestNote(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, existing.ID, &gitlab.UpdateMergeRequestNoteOptions{
+				Body: &body,
+			}, gitlab.WithContext(ctx))
+		} else {
+			_, resp, err = g.client.Notes.CreateMergeRequestNote(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, &gitlab.CreateMergeRequestNoteOptions{
+				Body: &body,
+			}, gitlab.WithContext(ctx))
+		}
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to write note: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to upsert merge request note: %w", err)
+	}
+	return nil
+}
+
+// authenticatedUserID returns the ID of the authenticated user, which is the
+// bot user of project and group access tokens.
+func (g *GitLab) authenticatedUserID(ctx context.Context) (int, error) {
+	if g.userID != 0 {
+		return g.userID, nil
+	}
+
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		user, resp, err := g.client.Users.CurrentUser(gitlab.WithContext(ctx))
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get current user: %w", err))
+		}
+		g.userID = user.ID
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+	return g.userID, nil
+}
+
+// PublishRequestStatus sets a commit status on the head commit of the Merge
+// Request. When no merge request internal ID is configured, the status is set
+// on the commit SHA. Commit statuses have no annotations, the invalid tags are
+// listed in the description.
+func (g *GitLab) PublishRequestStatus(ctx context.Context, status *Status) error {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	sha := g.cfg.GitLabCommitSHA
+	if g.cfg.GitLabMergeRequestIID > 0 {
+		if err := g.withRetries(ctx, func(ctx context.Context) error {
+			mr, resp, err := g.client.MergeRequests.GetMergeRequest(g.cfg.GitLabProjectID, g.cfg.GitLabMergeRequestIID, nil, gitlab.WithContext(withCacheRevalidation(ctx)))
+			if err != nil {
+				return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get merge request: %w", err))
+			}
+			sha = mr.SHA
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to get merge request head: %w", err)
+		}
+	}
+	if sha == "" {
+		return fmt.Errorf("failed to validate inputs: one of gitlab merge request iid or gitlab commit sha is required")
+	}
+
+	state := gitlab.Success
+	if !status.Success {
+		state = gitlab.Failed
+	}
+
+	description := status.Title
+	for _, a := range status.Annotations {
+		message := a.Message
+		if a.Warning {
+			message = "warning: " + message
+		}
+		if a.Line == 0 {
+			description += "; " + message
+			continue
+		}
+		description += fmt.Sprintf("; line %d: %s", a.Line, message)
+	}
+	if r := []rune(description); len(r) > gitLabCommitStatusDescriptionLength {
+		description = string(r[:gitLabCommitStatusDescriptionLength-1]) + "…"
+	}
+
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		_, resp, err := g.client.Commits.SetCommitStatus(g.cfg.GitLabProjectID, sha, &gitlab.SetCommitStatusOptions{
+			State:       state,
+			Name:        &status.Name,
+			Description: &description,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to set commit status: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to publish commit status: %w", err)
+	}
+	return nil
+}
+
+// ListLabels lists the labels of the Merge Request or issue.
+func (g *GitLab) ListLabels(ctx context.Context, kind ItemKind) ([]string, error) {
+	iid, err := g.labelsIID(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels gitlab.Labels
+	if err := g.withRetries(ctx, func(ctx context.Context) error {
+		var resp *gitlab.Response
+		var err error
+		if kind == ItemKindRequest {
+			var mr *gitlab.MergeRequest
+			mr, resp, err = g.client.MergeRequests.GetMergeRequest(g.cfg.GitLabProjectID, iid, nil, gitlab.WithContext(withCacheRevalidation(ctx)))
+			if err == nil {
+				labels = mr.Labels
+			}
+		} else {
+			var issue *gitlab.Issue
+			issue, resp, err = g.client.Issues.GetIssue(g.cfg.GitLabProjectID, iid, gitlab.WithContext(withCacheRevalidation(ctx)))
+			if err == nil {
+				labels = issue.Labels
+			}
+		}
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to get %s: %w", kind, err))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list %s labels: %w", kind, err)
+	}
+	return append([]string{}, labels...), nil
+}
+
+// AddLabels adds the labels to the Merge Request or issue.
+func (g *GitLab) AddLabels(ctx context.Context, kind ItemKind, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	opts := gitlab.LabelOptions(labels)
+	if err := g.updateLabels(ctx, kind, &opts, nil); err != nil {
+		return fmt.Errorf("failed to add %s labels: %w", kind, err)
+	}
+	return nil
+}
+
+// RemoveLabels removes the labels from the Merge Request or issue.
+func (g *GitLab) RemoveLabels(ctx context.Context, kind ItemKind, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	opts := gitlab.LabelOptions(labels)
+	if err := g.updateLabels(ctx, kind, nil, &opts); err != nil {
+		return fmt.Errorf("failed to remove %s labels: %w", kind, err)
+	}
+	return nil
+}
+
+// updateLabels adds and removes labels of the Merge Request or issue in a
+// single update.
+func (g *GitLab) updateLabels(ctx context.Context, kind ItemKind, add, remove *gitlab.LabelOptions) error {
+	iid, err := g.labelsIID(ctx, kind)
+	if err != nil {
+		return err
+	}
+
+	return g.withRetries(ctx, func(ctx context.Context) error {
+		var resp *gitlab.Response
+		var err error
+		if kind == ItemKindRequest {
+			_, resp, err = g.client.MergeRequests.UpdateMergeRequest(g.cfg.GitLabProjectID, iid, &gitlab.UpdateMergeRequestOptions{
+				AddLabels:    add,
+				RemoveLabels: remove,
+			}, gitlab.WithContext(ctx))
+		} else {
+			_, resp, err = g.client.Issues.UpdateIssue(g.cfg.GitLabProjectID, iid, &gitlab.UpdateIssueOptions{
+				AddLabels:    add,
+				RemoveLabels: remove,
+			}, gitlab.WithContext(ctx))
+		}
+		if err != nil {
+			return gitlabMaybeRetryable(resp, fmt.Errorf("failed to update %s: %w", kind, err))
+		}
+		return nil
+	})
+}
+
+// labelsIID returns the internal ID of the Merge Request or issue whose labels
+// to change.
+func (g *GitLab) labelsIID(ctx context.Context, kind ItemKind) (int, error) {
+	if err := validateGitLabProjectInputs(g.cfg); err != nil {
+		return 0, fmt.Errorf("failed to validate inputs: %w", err)
+	}
+
+	switch kind {
+	case ItemKindRequest:
+		if g.cfg.GitLabMergeRequestIID <= 0 && g.cfg.GitLabCommitSHA != "" {
+			if _, err := g.findMergeRequestForCommit(ctx, g.cfg.GitLabCommitSHA); err != nil {
+				return 0, err
+			}
+		}
+		if g.cfg.GitLabMergeRequestIID <= 0 {
+			return 0, fmt.Errorf("failed to validate inputs: gitlab merge request iid is required")
+		}
+		return g.cfg.GitLabMergeRequestIID, nil
+	case ItemKindIssue:
+		if g.cfg.GitLabIssueIID <= 0 {
+			return 0, fmt.Errorf("failed to validate inputs: gitlab issue iid is required")
+		}
+		return g.cfg.GitLabIssueIID, nil
+	default:
+		return 0, fmt.Errorf("unsupported item kind %q", kind)
+	}
+}
+
+func (g *GitLab) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	return withRateLimitRetries(ctx, &retryOptions{
+		MaxRetries:        g.cfg.MaxRetries,
+		InitialRetryDelay: g.cfg.InitialRetryDelay,
+		MaxRetryDelay:     g.cfg.MaxRetryDelay,
+		MaxRateLimitWait:  g.cfg.MaxRateLimitWait,
+	}, retryFunc)
+}
+
+// gitlabMaybeRetryable marks the error as retryable if the request was rate
+// limited or failed with a server error, see httpMaybeRetryable.
+func gitlabMaybeRetryable(resp *gitlab.Response, err error) error {
+	if resp == nil {
+		return httpMaybeRetryable(nil, err)
 	}
-	return err
+	return httpMaybeRetryable(resp.Response, err)
 }
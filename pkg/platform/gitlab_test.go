@@ -0,0 +1,940 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestGitLabPredefinedConfig_Load(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writePayload := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	issuePayload := writePayload("issue.json", `{
+		"object_kind": "issue",
+		"project": {"id": 7},
+		"object_attributes": {"iid": 3, "description": "TAG=issue"}
+	}`)
+	notePayload := writePayload("note.json", `{
+		"object_kind": "note",
+		"project": {"id": 7},
+		"object_attributes": {"note": "TAG=comment"},
+		"merge_request": {"iid": 4, "description": "TAG=request"}
+	}`)
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		exp  *gitLabPredefinedConfig
+	}{
+		{
+			name: "merge_request_pipeline",
+			env: map[string]string{
+				"CI_JOB_TOKEN":                              "token",
+				"CI_API_V4_URL":                             "https://gitlab.com/api/v4",
+				"CI_PROJECT_ID":                             "1",
+				"CI_COMMIT_SHA":                             "abc",
+				"CI_MERGE_REQUEST_IID":                      "2",
+				"CI_MERGE_REQUEST_DESCRIPTION":              "TAG=value",
+				"CI_MERGE_REQUEST_DESCRIPTION_IS_TRUNCATED": "false",
+			},
+			exp: &gitLabPredefinedConfig{
+				CIJobToken:                "token",
+				CIServerHost:              "https://gitlab.com/api/v4",
+				CIProjectID:               1,
+				CICommitSHA:               "abc",
+				CIMergeRequestIID:         2,
+				CIMergeRequestDescription: "TAG=value",
+			},
+		},
+		{
+			name: "truncated_description",
+			env: map[string]string{
+				"CI_MERGE_REQUEST_IID":                      "2",
+				"CI_MERGE_REQUEST_DESCRIPTION":              "TAG=val",
+				"CI_MERGE_REQUEST_DESCRIPTION_IS_TRUNCATED": "true",
+			},
+			exp: &gitLabPredefinedConfig{
+				CIMergeRequestIID: 2,
+			},
+		},
+		{
+			name: "maximum_length_description",
+			env: map[string]string{
+				"CI_MERGE_REQUEST_IID":         "2",
+				"CI_MERGE_REQUEST_DESCRIPTION": strings.Repeat("a", gitLabMaxDescriptionLength),
+			},
+			exp: &gitLabPredefinedConfig{
+				CIMergeRequestIID: 2,
+			},
+		},
+		{
+			name: "merge_train_ref",
+			env: map[string]string{
+				"CI_COMMIT_REF_NAME": "refs/merge-requests/12/train",
+			},
+			exp: &gitLabPredefinedConfig{
+				CIMergeRequestIID: 12,
+			},
+		},
+		{
+			name: "issue_webhook",
+			env: map[string]string{
+				"CI_PROJECT_ID":      "1",
+				"CI_PIPELINE_SOURCE": "trigger",
+				"TRIGGER_PAYLOAD":    issuePayload,
+			},
+			exp: &gitLabPredefinedConfig{
+				CIProjectID:        7,
+				CIIssueIID:         3,
+				CIIssueDescription: "TAG=issue",
+			},
+		},
+		{
+			name: "note_webhook",
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE": "trigger",
+				"TRIGGER_PAYLOAD":    notePayload,
+			},
+			exp: &gitLabPredefinedConfig{
+				CIProjectID:               7,
+				CIMergeRequestIID:         4,
+				CIMergeRequestDescription: "TAG=request",
+			},
+		},
+		{
+			name: "payload_ignored_without_trigger",
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE": "push",
+				"TRIGGER_PAYLOAD":    issuePayload,
+			},
+			exp: &gitLabPredefinedConfig{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+			c := &gitLabPredefinedConfig{}
+			c.Load(ctx, func(k string) string { return tc.env[k] })
+
+			if diff := cmp.Diff(c, tc.exp); diff != "" {
+				t.Errorf("gitLabPredefinedConfig not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitLab_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/projects/1/merge_requests/2":
+			fmt.Fprint(w, `{"iid":2,"description":"TAG=from-api"}`)
+		case "/api/v4/projects/1/repository/commits/abc/merge_requests":
+			fmt.Fprint(w, `[
+				{"iid":5,"state":"closed","description":"TAG=closed"},
+				{"iid":6,"state":"merged","description":"TAG=merged"},
+				{"iid":7,"state":"opened","description":"TAG=opened"}
+			]`)
+		case "/api/v4/projects/1/repository/commits/def/merge_requests":
+			fmt.Fprint(w, `[{"iid":5,"state":"closed","description":"TAG=closed"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 Not Found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name   string
+		cfg    *GitLabConfig
+		expErr string
+		exp    string
+		expIID int
+	}{
+		{
+			name: "predefined_description",
+			cfg: &GitLabConfig{
+				GitLabMergeRequestIID:         2,
+				GitLabMergeRequestDescription: "TAG=predefined",
+			},
+			exp:    "TAG=predefined",
+			expIID: 2,
+		},
+		{
+			name: "merge_request_iid",
+			cfg: &GitLabConfig{
+				GitLabMergeRequestIID: 2,
+			},
+			exp:    "TAG=from-api",
+			expIID: 2,
+		},
+		{
+			name: "commit_sha",
+			cfg: &GitLabConfig{
+				GitLabCommitSHA: "abc",
+			},
+			exp:    "TAG=opened",
+			expIID: 7,
+		},
+		{
+			name: "commit_sha_without_merge_request",
+			cfg: &GitLabConfig{
+				GitLabCommitSHA: "def",
+			},
+			expErr: "no open or merged merge request found for commit def",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.TagrepGitLabToken = "token"
+			tc.cfg.GitLabBaseURL = srv.URL + "/api/v4"
+			tc.cfg.GitLabProjectID = 1
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+
+			g, err := NewGitLab(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := g.GetRequestBody(ctx)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+			if got, want := g.cfg.GitLabMergeRequestIID, tc.expIID; got != want {
+				t.Errorf("merge request iid not as expected; got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestGitLab_UpdateIssueBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name    string
+		cfg     *GitLabConfig
+		current string
+		oldBody string
+		newBody string
+		expErr  string
+		expBody string
+		expCfg  string
+	}{
+		{
+			name:    "updated",
+			cfg:     &GitLabConfig{GitLabIssueIID: 3},
+			current: "TAG=a",
+			oldBody: "TAG=a",
+			newBody: "TAG=b",
+			expBody: "TAG=b",
+		},
+		{
+			name: "predefined_description_updated",
+			cfg: &GitLabConfig{
+				GitLabIssueIID:         3,
+				GitLabIssueDescription: "TAG=a",
+			},
+			current: "TAG=a",
+			oldBody: "TAG=a",
+			newBody: "TAG=b",
+			expBody: "TAG=b",
+			expCfg:  "TAG=b",
+		},
+		{
+			name:    "changed",
+			cfg:     &GitLabConfig{GitLabIssueIID: 3},
+			current: "TAG=edited",
+			oldBody: "TAG=a",
+			newBody: "TAG=b",
+			expErr:  "body was changed since it was fetched",
+			expBody: "TAG=edited",
+		},
+		{
+			name:    "unchanged",
+			cfg:     &GitLabConfig{GitLabIssueIID: 3},
+			current: "TAG=a",
+			oldBody: "TAG=a",
+			newBody: "TAG=a",
+			expBody: "TAG=a",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := tc.current
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v4/projects/1/issues/3" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"404 Not Found"}`)
+					return
+				}
+				if r.Method == http.MethodPut {
+					var opts struct {
+						Description string `json:"description"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+						t.Error(err)
+					}
+					body = opts.Description
+				}
+				if err := json.NewEncoder(w).Encode(map[string]any{"id": 103, "iid": 3, "description": body}); err != nil {
+					t.Error(err)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			tc.cfg.TagrepGitLabToken = "token"
+			tc.cfg.GitLabBaseURL = srv.URL + "/api/v4"
+			tc.cfg.GitLabProjectID = 1
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+
+			g, err := NewGitLab(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = g.UpdateIssueBody(ctx, tc.oldBody, tc.newBody)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if got, want := body, tc.expBody; got != want {
+				t.Errorf("body not as expected; got %q, want %q", got, want)
+			}
+			if got, want := g.cfg.GitLabIssueDescription, tc.expCfg; got != want {
+				t.Errorf("predefined description not as expected; got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitLab_UpsertRequestComment(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	type author struct {
+		ID int `json:"id"`
+	}
+
+	type note struct {
+		ID     int    `json:"id"`
+		Body   string `json:"body"`
+		System bool   `json:"system"`
+		Author author `json:"author"`
+	}
+
+	// The authenticated user.
+	bot := author{ID: 42}
+
+	cases := []struct {
+		name        string
+		notes       []*note
+		body        string
+		expRequests []string
+		expNotes    []*note
+	}{
+		{
+			name:  "created",
+			notes: []*note{{ID: 1, Body: "LGTM"}},
+			body:  "<!-- marker -->\nsummary",
+			expRequests: []string{
+				"GET /api/v4/user",
+				"GET /api/v4/projects/1/merge_requests/4/notes",
+				"POST /api/v4/projects/1/merge_requests/4/notes",
+			},
+			expNotes: []*note{{ID: 1, Body: "LGTM"}, {ID: 2, Body: "<!-- marker -->\nsummary", Author: bot}},
+		},
+		{
+			name: "updated",
+			notes: []*note{
+				{ID: 1, Body: "mentioned <!-- marker --> in a commit", System: true, Author: bot},
+				{ID: 2, Body: "<!-- marker -->\nold", Author: bot},
+			},
+			body: "<!-- marker -->\nnew",
+			expRequests: []string{
+				"GET /api/v4/user",
+				"GET /api/v4/projects/1/merge_requests/4/notes",
+				"PUT /api/v4/projects/1/merge_requests/4/notes/2",
+			},
+			expNotes: []*note{
+				{ID: 1, Body: "mentioned <!-- marker --> in a commit", System: true, Author: bot},
+				{ID: 2, Body: "<!-- marker -->\nnew", Author: bot},
+			},
+		},
+		{
+			name:  "unchanged",
+			notes: []*note{{ID: 1, Body: "<!-- marker -->\nsummary", Author: bot}},
+			body:  "<!-- marker -->\nsummary",
+			expRequests: []string{
+				"GET /api/v4/user",
+				"GET /api/v4/projects/1/merge_requests/4/notes",
+			},
+			expNotes: []*note{{ID: 1, Body: "<!-- marker -->\nsummary", Author: bot}},
+		},
+		{
+			name:  "quoted_by_other_user",
+			notes: []*note{{ID: 1, Body: "> <!-- marker -->\n> old\n\nWhy?", Author: author{ID: 7}}},
+			body:  "<!-- marker -->\nnew",
+			expRequests: []string{
+				"GET /api/v4/user",
+				"GET /api/v4/projects/1/merge_requests/4/notes",
+				"POST /api/v4/projects/1/merge_requests/4/notes",
+			},
+			expNotes: []*note{
+				{ID: 1, Body: "> <!-- marker -->\n> old\n\nWhy?", Author: author{ID: 7}},
+				{ID: 2, Body: "<!-- marker -->\nnew", Author: bot},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			notes := tc.notes
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+
+				var in note
+				if r.Method != http.MethodGet {
+					if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+						t.Error(err)
+					}
+				}
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v4/user":
+					if err := json.NewEncoder(w).Encode(bot); err != nil {
+						t.Error(err)
+					}
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1/merge_requests/4/notes":
+					if err := json.NewEncoder(w).Encode(notes); err != nil {
+						t.Error(err)
+					}
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/1/merge_requests/4/notes":
+					n := &note{ID: len(notes) + 1, Body: in.Body, Author: bot}
+					notes = append(notes, n)
+					if err := json.NewEncoder(w).Encode(n); err != nil {
+						t.Error(err)
+					}
+				case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/v4/projects/1/merge_requests/4/notes/"):
+					for _, n := range notes {
+						if r.URL.Path == fmt.Sprintf("/api/v4/projects/1/merge_requests/4/notes/%d", n.ID) {
+							n.Body = in.Body
+						}
+					}
+					fmt.Fprint(w, "{}")
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"404 Not Found"}`)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			g, err := NewGitLab(ctx, &GitLabConfig{
+				TagrepGitLabToken:     "token",
+				GitLabBaseURL:         srv.URL + "/api/v4",
+				GitLabProjectID:       1,
+				GitLabMergeRequestIID: 4,
+				MaxRetries:            1,
+				InitialRetryDelay:     1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := g.UpsertRequestComment(ctx, "<!-- marker -->", tc.body); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(requests, tc.expRequests); diff != "" {
+				t.Errorf("requests not as expected; (-got,+want): %s", diff)
+			}
+			if diff := cmp.Diff(notes, tc.expNotes); diff != "" {
+				t.Errorf("notes not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitLab_PublishRequestStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	type commitStatus struct {
+		Path        string `json:"-"`
+		State       string `json:"state"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	cases := []struct {
+		name   string
+		cfg    *GitLabConfig
+		status *Status
+		expErr string
+		exp    []*commitStatus
+	}{
+		{
+			name: "success_on_merge_request_head",
+			cfg:  &GitLabConfig{GitLabMergeRequestIID: 4, GitLabCommitSHA: "merged-result-sha"},
+			status: &Status{
+				Name:    "tagrep",
+				Success: true,
+				Title:   "All 1 tags are valid",
+			},
+			exp: []*commitStatus{
+				{Path: "/api/v4/projects/1/statuses/head-sha", State: "success", Name: "tagrep", Description: "All 1 tags are valid"},
+			},
+		},
+		{
+			name: "failure_on_sha",
+			cfg:  &GitLabConfig{GitLabCommitSHA: "abc"},
+			status: &Status{
+				Name:        "tags",
+				Title:       "1 of 1 tags are invalid",
+				Annotations: []*Annotation{{Line: 3, Message: "DRAFT: invalid"}},
+			},
+			exp: []*commitStatus{
+				{Path: "/api/v4/projects/1/statuses/abc", State: "failed", Name: "tags", Description: "1 of 1 tags are invalid; line 3: DRAFT: invalid"},
+			},
+		},
+		{
+			name: "warning",
+			cfg:  &GitLabConfig{GitLabCommitSHA: "abc"},
+			status: &Status{
+				Name:        "tagrep",
+				Success:     true,
+				Title:       "All 1 tags are valid, 1 with warnings",
+				Annotations: []*Annotation{{Line: 4, Message: "OWNER: repeated", Warning: true}},
+			},
+			exp: []*commitStatus{
+				{Path: "/api/v4/projects/1/statuses/abc", State: "success", Name: "tagrep", Description: "All 1 tags are valid, 1 with warnings; line 4: warning: OWNER: repeated"},
+			},
+		},
+		{
+			name: "annotation_without_line",
+			cfg:  &GitLabConfig{GitLabCommitSHA: "abc"},
+			status: &Status{
+				Name:        "tagrep",
+				Title:       "1 of 1 tags are invalid",
+				Annotations: []*Annotation{{Message: "OWNER: missing required tag"}},
+			},
+			exp: []*commitStatus{
+				{Path: "/api/v4/projects/1/statuses/abc", State: "failed", Name: "tagrep", Description: "1 of 1 tags are invalid; OWNER: missing required tag"},
+			},
+		},
+		{
+			name: "long_description",
+			cfg:  &GitLabConfig{GitLabCommitSHA: "abc"},
+			status: &Status{
+				Name:        "tagrep",
+				Title:       "1 of 1 tags are invalid",
+				Annotations: []*Annotation{{Line: 3, Message: strings.Repeat("é", 300)}},
+			},
+			exp: []*commitStatus{
+				{Path: "/api/v4/projects/1/statuses/abc", State: "failed", Name: "tagrep", Description: "1 of 1 tags are invalid; line 3: " + strings.Repeat("é", 221) + "…"},
+			},
+		},
+		{
+			name:   "missing_sha",
+			cfg:    &GitLabConfig{},
+			status: &Status{Name: "tagrep"},
+			expErr: "one of gitlab merge request iid or gitlab commit sha is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []*commitStatus
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1/merge_requests/4":
+					fmt.Fprint(w, `{"id":104,"iid":4,"sha":"head-sha"}`)
+				case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v4/projects/1/statuses/"):
+					in := &commitStatus{Path: r.URL.Path}
+					if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+						t.Error(err)
+					}
+					got = append(got, in)
+					fmt.Fprint(w, "{}")
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"404 Not Found"}`)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			tc.cfg.TagrepGitLabToken = "token"
+			tc.cfg.GitLabBaseURL = srv.URL + "/api/v4"
+			tc.cfg.GitLabProjectID = 1
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+
+			g, err := NewGitLab(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = g.PublishRequestStatus(ctx, tc.status)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("commit statuses not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitLab_Labels(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name        string
+		kind        ItemKind
+		add         []string
+		remove      []string
+		expLabels   []string
+		expRequests []string
+	}{
+		{
+			name:      "request",
+			kind:      ItemKindRequest,
+			add:       []string{"lgtm:all"},
+			remove:    []string{"lgtm:some"},
+			expLabels: []string{"bug", "lgtm:some"},
+			expRequests: []string{
+				"GET /api/v4/projects/1/merge_requests/4",
+				`PUT /api/v4/projects/1/merge_requests/4 {"add_labels":"lgtm:all"}`,
+				`PUT /api/v4/projects/1/merge_requests/4 {"remove_labels":"lgtm:some"}`,
+			},
+		},
+		{
+			name:      "issue",
+			kind:      ItemKindIssue,
+			add:       []string{"p1", "triaged"},
+			expLabels: []string{"bug"},
+			expRequests: []string{
+				"GET /api/v4/projects/1/issues/5",
+				`PUT /api/v4/projects/1/issues/5 {"add_labels":"p1,triaged"}`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1/merge_requests/4":
+					requests = append(requests, r.Method+" "+r.URL.Path)
+					fmt.Fprint(w, `{"id":104,"iid":4,"labels":["bug","lgtm:some"]}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1/issues/5":
+					requests = append(requests, r.Method+" "+r.URL.Path)
+					fmt.Fprint(w, `{"id":105,"iid":5,"labels":["bug"]}`)
+				case r.Method == http.MethodPut:
+					b, err := io.ReadAll(r.Body)
+					if err != nil {
+						t.Error(err)
+					}
+					requests = append(requests, r.Method+" "+r.URL.Path+" "+strings.TrimSpace(string(b)))
+					fmt.Fprint(w, `{"id":100}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"404 Not Found"}`)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			g, err := NewGitLab(ctx, &GitLabConfig{
+				TagrepGitLabToken:     "token",
+				GitLabBaseURL:         srv.URL + "/api/v4",
+				GitLabProjectID:       1,
+				GitLabMergeRequestIID: 4,
+				GitLabIssueIID:        5,
+				MaxRetries:            1,
+				InitialRetryDelay:     1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := g.ListLabels(ctx, tc.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tc.expLabels); diff != "" {
+				t.Errorf("labels not as expected; (-got,+want): %s", diff)
+			}
+			if err := g.AddLabels(ctx, tc.kind, tc.add); err != nil {
+				t.Fatal(err)
+			}
+			if err := g.RemoveLabels(ctx, tc.kind, tc.remove); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(requests, tc.expRequests); diff != "" {
+				t.Errorf("requests not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGitLab_SearchItems(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name       string
+		totalPages bool
+		expNumbers []int
+	}{
+		{
+			name:       "total_pages",
+			totalPages: true,
+			expNumbers: []int{1, 2, 3},
+		},
+		{
+			// GitLab omits the total pages for more than 10,000 results.
+			name:       "no_total_pages",
+			totalPages: false,
+			expNumbers: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v4/projects/1/issues" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"404 Not Found"}`)
+					return
+				}
+				page := r.URL.Query().Get("page")
+				if tc.totalPages {
+					w.Header().Set("X-Total-Pages", "3")
+				}
+				switch page {
+				case "1":
+					w.Header().Set("X-Next-Page", "2")
+				case "2":
+					w.Header().Set("X-Next-Page", "3")
+				}
+				w.Header().Set("X-Page", page)
+				fmt.Fprintf(w, `[{"id":10%s,"iid":%s,"title":"Issue %s","description":"TAG=%s"}]`, page, page, page, page)
+			}))
+			t.Cleanup(srv.Close)
+
+			g, err := NewGitLab(ctx, &GitLabConfig{
+				TagrepGitLabToken: "token",
+				GitLabBaseURL:     srv.URL + "/api/v4",
+				GitLabProjectID:   1,
+				MaxRetries:        1,
+				InitialRetryDelay: 1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			if err := g.SearchItems(ctx, "state=opened", 4, func(item *Item) error {
+				got = append(got, item.Number)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(got)
+
+			if diff := cmp.Diff(got, tc.expNumbers); diff != "" {
+				t.Errorf("items not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+// TestGitLab_Replay runs the GitLab platform end to end against the API
+// responses recorded in testdata/gitlab.json.
+func TestGitLab_Replay(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name   string
+		cfg    *GitLabConfig
+		call   func(ctx context.Context, g *GitLab) (any, error)
+		exp    any
+		expErr string
+	}{
+		{
+			name: "request_body",
+			cfg:  &GitLabConfig{GitLabMergeRequestIID: 5},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				return g.GetRequestBody(ctx)
+			},
+			exp: "Adds the feature.\n\nJUSTIFICATION=needed for the release",
+		},
+		{
+			name: "request_not_found",
+			cfg:  &GitLabConfig{GitLabMergeRequestIID: 404},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				return g.GetRequestBody(ctx)
+			},
+			exp:    "",
+			expErr: "404 Not Found",
+		},
+		{
+			name: "issue_body",
+			cfg:  &GitLabConfig{GitLabIssueIID: 3},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				return g.GetIssueBody(ctx)
+			},
+			exp: "JUSTIFICATION=incident 3",
+		},
+		{
+			name: "issue_body_commit_sha",
+			cfg:  &GitLabConfig{GitLabCommitSHA: "abc"},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				return g.GetIssueBody(ctx)
+			},
+			exp:    "",
+			expErr: "failed to validate inputs: gitlab issue id is required",
+		},
+		{
+			name: "requests_in_range",
+			cfg:  &GitLabConfig{},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				return g.ListRequestsInRange(ctx, "v0.1.0", "v0.2.0")
+			},
+			exp: []*Item{
+				{Number: 5, URL: "https://gitlab.com/abcxyz/tagrep/-/merge_requests/5", Title: "Merge request 5", Body: "JUSTIFICATION=needed for the release"},
+			},
+		},
+		{
+			name: "search_items",
+			cfg:  &GitLabConfig{},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				var items []*Item
+				err := g.SearchItems(ctx, "state=opened&labels=aod", 2, func(item *Item) error {
+					items = append(items, item)
+					return nil
+				})
+				return items, err
+			},
+			exp: []*Item{
+				{Number: 3, URL: "https://gitlab.com/abcxyz/tagrep/-/issues/3", Title: "Issue 3", Body: "JUSTIFICATION=incident 3"},
+			},
+		},
+		{
+			name: "linked_issues",
+			cfg:  &GitLabConfig{GitLabMergeRequestIID: 5},
+			call: func(ctx context.Context, g *GitLab) (any, error) {
+				return g.ListLinkedIssues(ctx)
+			},
+			exp: []*Item{
+				{Number: 3, URL: "https://gitlab.com/abcxyz/tagrep/-/issues/3", Title: "Issue 3", Body: "JUSTIFICATION=incident 3"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpClient, err := cassetteHTTPClient("testdata/gitlab.json", CassetteModeReplay, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.cfg.MaxRetries = 1
+			tc.cfg.InitialRetryDelay = 1
+			tc.cfg.TagrepGitLabToken = "test-token"
+			tc.cfg.GitLabBaseURL = "https://gitlab.com/api/v4"
+			tc.cfg.GitLabProjectID = 1234
+			tc.cfg.httpClient = httpClient
+
+			g, err := NewGitLab(ctx, tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tc.call(ctx, g)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("result not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,494 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/cli"
+)
+
+// jiraPageSize is the number of issues requested per page.
+const jiraPageSize = 50
+
+var (
+	_ Platform     = (*Jira)(nil)
+	_ Searcher     = (*Jira)(nil)
+	_ TicketSource = (*Jira)(nil)
+
+	// jiraKeyRegexp matches Jira issue keys such as PROJ-123, e.g. in branch
+	// names like feature/PROJ-123-add-cache.
+	jiraKeyRegexp = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+	// jiraWikiMacroRegexp matches wiki markup macros such as {code:go} and
+	// {noformat}, which surround text without changing it.
+	jiraWikiMacroRegexp = regexp.MustCompile(`\{(code|noformat|quote|panel|color)(:[^}]*)?\}`)
+
+	// jiraWikiLinePrefixRegexp matches wiki markup headings, lists and quotes at
+	// the start of a line, which would otherwise hide tags on that line.
+	jiraWikiLinePrefixRegexp = regexp.MustCompile(`(?m)^[ \t]*(h[1-6]\.|bq\.|[*#-]+)[ \t]+`)
+
+	// jiraWikiEscapeRegexp matches characters escaped with a backslash.
+	jiraWikiEscapeRegexp = regexp.MustCompile(`\\([_*{}\[\]+^~?|!#-])`)
+
+	// jiraADFBlockNodes are the Atlassian Document Format nodes that end a line
+	// of text.
+	jiraADFBlockNodes = map[string]struct{}{
+		"paragraph": {}, "heading": {}, "codeBlock": {}, "blockquote": {},
+		"listItem": {}, "bulletList": {}, "orderedList": {}, "rule": {},
+		"panel": {}, "tableRow": {}, "taskItem": {}, "decisionItem": {},
+		"expand": {}, "nestedExpand": {}, "mediaSingle": {},
+	}
+)
+
+// TicketSource fetches tickets from an issue tracker that requests reference
+// by key, e.g. TICKET=PROJ-123.
+type TicketSource interface {
+	// GetTicketBody gets the text of the ticket with the given key.
+	GetTicketBody(ctx context.Context, key string) (string, error)
+}
+
+// Jira implements the Platform interface for Jira Cloud and Jira Data Center.
+// Jira has no requests, it is used to parse issues directly or as a
+// TicketSource for the requests of another platform.
+type Jira struct {
+	cfg    *JiraConfig
+	client *restClient
+}
+
+// JiraConfig is the config values for the Jira client.
+type JiraConfig struct {
+	// Retry
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+
+	// Auth
+	JiraUser  string
+	JiraToken string
+
+	JiraURL      string
+	JiraIssueKey string
+
+	// JiraFields are the additional fields to read. A field of the form
+	// FIELD=TAG is read as the value of the tag TAG, any other field is parsed
+	// for tags like the description.
+	JiraFields []string
+
+	// httpClient sends the API requests, http.DefaultClient when nil.
+	httpClient *http.Client
+}
+
+func (c *JiraConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	f := set.NewSection("JIRA OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "jira-url",
+		EnvVar:  "JIRA_URL",
+		Target:  &c.JiraURL,
+		Example: "https://my-org.atlassian.net",
+		Usage: "The URL of the Jira instance. When set, tickets referenced from a " +
+			"request with -ticket-tag are also parsed for tags.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "jira-user",
+		EnvVar: "JIRA_USER",
+		Target: &c.JiraUser,
+		Usage: "The Jira user, the account email for Jira Cloud, to use with " +
+			"-jira-token. When empty, -jira-token is used as a personal access token.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "jira-token",
+		EnvVar: "JIRA_API_TOKEN",
+		Target: &c.JiraToken,
+		Usage:  "The Jira API token or personal access token to make API calls.",
+		Hidden: true,
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "jira-issue-key",
+		EnvVar:  "JIRA_ISSUE_KEY",
+		Target:  &c.JiraIssueKey,
+		Example: "PROJ-123",
+		Usage:   "The key of the Jira issue to parse with -platform=jira.",
+	})
+
+	f.StringSliceVar(&cli.StringSliceVar{
+		Name:    "jira-fields",
+		EnvVar:  "JIRA_FIELDS",
+		Target:  &c.JiraFields,
+		Example: "customfield_10042=JUSTIFICATION",
+		Usage: "Additional Jira fields to read. A field of the form FIELD=TAG is " +
+			"read as the value of the tag TAG, any other field is parsed for tags.",
+	})
+}
+
+// NewJira creates a new Jira client.
+func NewJira(ctx context.Context, cfg *JiraConfig) (*Jira, error) {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.InitialRetryDelay <= 0 {
+		cfg.InitialRetryDelay = 1 * time.Second
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = 20 * time.Second
+	}
+
+	if cfg.JiraURL == "" {
+		return nil, fmt.Errorf("jira url is required")
+	}
+
+	return &Jira{
+		cfg: cfg,
+		client: &restClient{
+			httpClient: cfg.httpClient,
+			baseURL:    strings.TrimSuffix(cfg.JiraURL, "/"),
+			auth: func(r *http.Request) {
+				switch {
+				case cfg.JiraToken == "":
+				case cfg.JiraUser != "":
+					r.SetBasicAuth(cfg.JiraUser, cfg.JiraToken)
+				default:
+					r.Header.Set("Authorization", "Bearer "+cfg.JiraToken)
+				}
+			},
+		},
+	}, nil
+}
+
+// jiraIssue is the subset of a Jira issue used by tagrep. Fields are decoded
+// lazily since their type depends on the field and the API version.
+type jiraIssue struct {
+	ID     string                     `json:"id"`
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+// jiraADFNode is a node of an Atlassian Document Format document.
+type jiraADFNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Attrs   map[string]any `json:"attrs"`
+	Content []*jiraADFNode `json:"content"`
+}
+
+// GetRequestBody is not supported, Jira has no requests.
+func (j *Jira) GetRequestBody(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("jira has no requests: %w", errors.ErrUnsupported)
+}
+
+// GetIssueBody gets the text of the issue set by -jira-issue-key.
+func (j *Jira) GetIssueBody(ctx context.Context) (string, error) {
+	if j.cfg.JiraIssueKey == "" {
+		return "", fmt.Errorf("failed to validate inputs: jira issue key is required")
+	}
+	return j.GetTicketBody(ctx, j.cfg.JiraIssueKey)
+}
+
+// GetTicketBody gets the description of the issue with the given key followed
+// by the configured fields, converted to plain text.
+func (j *Jira) GetTicketBody(ctx context.Context, key string) (string, error) {
+	q := url.Values{}
+	q.Set("fields", strings.Join(j.fieldIDs(), ","))
+
+	var issue jiraIssue
+	if err := j.withRetries(ctx, func(ctx context.Context) error {
+		resp, err := j.client.do(ctx, http.MethodGet, j.apiPath("issue", key), q, nil, &issue)
+		if err != nil {
+			return restMaybeRetryable(resp, fmt.Errorf("failed to get issue: %w", err))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to get jira issue %s: %w", key, err)
+	}
+
+	return j.issueBody(&issue), nil
+}
+
+// SearchItems calls fn for every issue that matches the JQL query, e.g.
+// `project = PROJ AND status = "In Progress"`. Jira Cloud paginates with
+// opaque tokens, so its pages are fetched sequentially and concurrency is
+// ignored.
+func (j *Jira) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	if j.isCloud() {
+		return j.searchCloud(ctx, query, fn)
+	}
+
+	fn = serialize(fn)
+
+	if err := fetchPages(ctx, concurrency, func(ctx context.Context, page int) (int, error) {
+		q := url.Values{}
+		q.Set("jql", query)
+		q.Set("startAt", strconv.Itoa((page-1)*jiraPageSize))
+		q.Set("maxResults", strconv.Itoa(jiraPageSize))
+		q.Set("fields", strings.Join(append(j.fieldIDs(), "summary"), ","))
+
+		var result struct {
+			Total  int          `json:"total"`
+			Issues []*jiraIssue `json:"issues"`
+		}
+		if err := j.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := j.client.do(ctx, http.MethodGet, j.apiPath("search"), q, nil, &result)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to search issues: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return 0, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		if err := j.emit(result.Issues, fn); err != nil {
+			return 0, err
+		}
+
+		return (result.Total + jiraPageSize - 1) / jiraPageSize, nil
+	}); err != nil {
+		return fmt.Errorf("failed to search items: %w", err)
+	}
+
+	return nil
+}
+
+// searchCloud searches Jira Cloud issues with the enhanced JQL search API.
+func (j *Jira) searchCloud(ctx context.Context, query string, fn SearchFunc) error {
+	var token string
+	for {
+		q := url.Values{}
+		q.Set("jql", query)
+		q.Set("maxResults", strconv.Itoa(jiraPageSize))
+		q.Set("fields", strings.Join(append(j.fieldIDs(), "summary"), ","))
+		if token != "" {
+			q.Set("nextPageToken", token)
+		}
+
+		var result struct {
+			NextPageToken string       `json:"nextPageToken"`
+			Issues        []*jiraIssue `json:"issues"`
+		}
+		if err := j.withRetries(ctx, func(ctx context.Context) error {
+			resp, err := j.client.do(ctx, http.MethodGet, j.apiPath("search", "jql"), q, nil, &result)
+			if err != nil {
+				return restMaybeRetryable(resp, fmt.Errorf("failed to search issues: %w", err))
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to search items: %w", err)
+		}
+
+		if err := j.emit(result.Issues, fn); err != nil {
+			return err
+		}
+
+		if result.NextPageToken == "" {
+			return nil
+		}
+		token = result.NextPageToken
+	}
+}
+
+// emit calls fn for every issue.
+func (j *Jira) emit(issues []*jiraIssue, fn SearchFunc) error {
+	for _, issue := range issues {
+		id, _ := strconv.Atoi(issue.ID)
+
+		var summary string
+		_ = json.Unmarshal(issue.Fields["summary"], &summary)
+
+		if err := fn(&Item{
+			Number: id,
+			URL:    strings.TrimSuffix(j.cfg.JiraURL, "/") + "/browse/" + url.PathEscape(issue.Key),
+			Title:  summary,
+			Body:   j.issueBody(issue),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueBody returns the description of the issue followed by the configured
+// fields as plain text.
+func (j *Jira) issueBody(issue *jiraIssue) string {
+	parts := []string{strings.Join(jiraFieldValues(issue.Fields["description"]), "\n")}
+
+	for _, f := range j.cfg.JiraFields {
+		id, tag, ok := strings.Cut(f, "=")
+		values := jiraFieldValues(issue.Fields[strings.TrimSpace(id)])
+		if !ok {
+			parts = append(parts, values...)
+			continue
+		}
+
+		for _, v := range values {
+			// Tag values are a single line.
+			v = strings.Join(strings.Fields(v), " ")
+			parts = append(parts, strings.TrimSpace(tag)+"="+v)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// fieldIDs returns the IDs of the fields to request.
+func (j *Jira) fieldIDs() []string {
+	ids := []string{"description"}
+	for _, f := range j.cfg.JiraFields {
+		id, _, _ := strings.Cut(f, "=")
+		ids = append(ids, strings.TrimSpace(id))
+	}
+	return ids
+}
+
+// isCloud reports whether the client targets Jira Cloud, which serves
+// rich text as Atlassian Document Format from version 3 of the API.
+func (j *Jira) isCloud() bool {
+	u, err := url.Parse(j.cfg.JiraURL)
+	return err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net")
+}
+
+// apiPath returns the API path joined with the escaped path segments.
+func (j *Jira) apiPath(segments ...string) string {
+	parts := []string{"rest", "api", "2"}
+	if j.isCloud() {
+		parts[2] = "3"
+	}
+	for _, s := range segments {
+		parts = append(parts, url.PathEscape(s))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (j *Jira) withRetries(ctx context.Context, retryFunc retry.RetryFunc) error {
+	backoff := retry.NewFibonacci(j.cfg.InitialRetryDelay)
+	backoff = retry.WithMaxRetries(retryLimit(j.cfg.MaxRetries), backoff)
+	backoff = retry.WithCappedDuration(j.cfg.MaxRetryDelay, backoff)
+
+	if err := retry.Do(ctx, backoff, retryFunc); err != nil {
+		return fmt.Errorf("failed to execute retriable function: %w", err)
+	}
+	return nil
+}
+
+// jiraFieldValues converts the value of a Jira field to plain text. Strings
+// are wiki markup, documents are Atlassian Document Format, options and users
+// are reduced to their display value and arrays have one value per element.
+func jiraFieldValues(raw json.RawMessage) []string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []string{jiraWikiToText(s)}
+	}
+
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err == nil {
+		var values []string
+		for _, v := range arr {
+			values = append(values, jiraFieldValues(v)...)
+		}
+		return values
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		if string(obj["type"]) == `"doc"` {
+			var doc jiraADFNode
+			if err := json.Unmarshal(raw, &doc); err == nil {
+				var b strings.Builder
+				jiraADFToText(&doc, &b)
+				return []string{strings.TrimSpace(b.String())}
+			}
+		}
+		for _, k := range []string{"value", "name", "displayName", "key"} {
+			if v, ok := obj[k]; ok {
+				return jiraFieldValues(v)
+			}
+		}
+		return nil
+	}
+
+	// Numbers and booleans.
+	return []string{string(raw)}
+}
+
+// jiraWikiToText removes the wiki markup that would prevent tags from being
+// parsed.
+func jiraWikiToText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, `\\`, "\n")
+	s = jiraWikiMacroRegexp.ReplaceAllString(s, "")
+	s = jiraWikiLinePrefixRegexp.ReplaceAllString(s, "")
+	s = jiraWikiEscapeRegexp.ReplaceAllString(s, "$1")
+	return strings.TrimSpace(s)
+}
+
+// jiraADFToText writes the text of the Atlassian Document Format node to b,
+// with one line per block node.
+func jiraADFToText(n *jiraADFNode, b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+	case "hardBreak":
+		b.WriteByte('\n')
+	case "mention", "emoji", "status":
+		if v, ok := n.Attrs["text"].(string); ok {
+			b.WriteString(v)
+		}
+	case "inlineCard":
+		if v, ok := n.Attrs["url"].(string); ok {
+			b.WriteString(v)
+		}
+	}
+
+	for _, c := range n.Content {
+		jiraADFToText(c, b)
+	}
+
+	if _, ok := jiraADFBlockNodes[n.Type]; ok && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteByte('\n')
+	}
+}
+
+// findJiraKeys returns the Jira issue keys in texts, without duplicates, in
+// the order they appear.
+func findJiraKeys(texts ...string) []string {
+	var keys []string
+	for _, text := range texts {
+		for _, key := range jiraKeyRegexp.FindAllString(text, -1) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestJiraFieldValues(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		raw  string
+		exp  []string
+	}{
+		{
+			name: "null",
+			raw:  `null`,
+		},
+		{
+			name: "wiki_markup",
+			raw:  `"h2. Details\n* TAG_1=one\n# TAG_2=two\n{noformat}\nTAG_3=three\n{noformat}\nTAG\\_4=four\\\\TAG_5=five"`,
+			exp:  []string{"Details\nTAG_1=one\nTAG_2=two\n\nTAG_3=three\n\nTAG_4=four\nTAG_5=five"},
+		},
+		{
+			name: "adf",
+			raw: `{"type":"doc","version":1,"content":[
+				{"type":"paragraph","content":[{"type":"text","text":"Some description"}]},
+				{"type":"paragraph","content":[
+					{"type":"text","text":"TAG_1=one"},
+					{"type":"hardBreak"},
+					{"type":"text","text":"TAG_2="},
+					{"type":"mention","attrs":{"text":"@someone"}}
+				]},
+				{"type":"bulletList","content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"TAG_3=three"}]}]}
+				]}
+			]}`,
+			exp: []string{"Some description\nTAG_1=one\nTAG_2=@someone\nTAG_3=three"},
+		},
+		{
+			name: "options",
+			raw:  `[{"value":"a"},{"value":"b"}]`,
+			exp:  []string{"a", "b"},
+		},
+		{
+			name: "user",
+			raw:  `{"accountId":"123","displayName":"Some One"}`,
+			exp:  []string{"Some One"},
+		},
+		{
+			name: "number",
+			raw:  `42`,
+			exp:  []string{"42"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := jiraFieldValues(json.RawMessage(tc.raw))
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("values not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestJira_GetTicketBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer my-token"; got != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PROJ-1":
+			if got, want := r.URL.Query().Get("fields"), "description,customfield_1,customfield_2"; got != want {
+				t.Errorf("fields not as expected; got %q, want %q", got, want)
+			}
+			fmt.Fprint(w, `{"id":"10001","key":"PROJ-1","fields":{
+				"description":"Access request.\n\nTAG_1=value",
+				"customfield_1":"Needed for the\nincident",
+				"customfield_2":"TAG_2=from-field"
+			}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name   string
+		key    string
+		expErr string
+		exp    string
+	}{
+		{
+			name: "description_and_fields",
+			key:  "PROJ-1",
+			exp:  "Access request.\n\nTAG_1=value\nJUSTIFICATION=Needed for the incident\nTAG_2=from-field",
+		},
+		{
+			name:   "not_found",
+			key:    "PROJ-2",
+			expErr: "failed to get jira issue PROJ-2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			j, err := NewJira(ctx, &JiraConfig{
+				JiraURL:           srv.URL,
+				JiraToken:         "my-token",
+				JiraFields:        []string{"customfield_1=JUSTIFICATION", "customfield_2"},
+				MaxRetries:        1,
+				InitialRetryDelay: 1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := j.GetTicketBody(ctx, tc.key)
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
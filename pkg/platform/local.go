@@ -0,0 +1,149 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/abcxyz/pkg/cli"
+)
+
+// localStdinInput is the input value used to read from stdin.
+const localStdinInput = "-"
+
+var (
+	_ Platform    = (*Local)(nil)
+	_ BodyUpdater = (*Local)(nil)
+)
+
+// Local implements the Platform interface by reading from a file or stdin.
+type Local struct {
+	cfg *LocalConfig
+}
+
+// LocalConfig is the config values for the local source.
+type LocalConfig struct {
+	LocalInput string
+
+	// Stdin is the reader used when LocalInput is "-". Defaults to os.Stdin.
+	Stdin io.Reader
+}
+
+func (c *LocalConfig) RegisterFlagsContext(ctx context.Context, set *cli.FlagSet) {
+	f := set.NewSection("LOCAL OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:    "local-input",
+		EnvVar:  "TAGREP_LOCAL_INPUT",
+		Target:  &c.LocalInput,
+		Default: localStdinInput,
+		Example: ".git/COMMIT_EDITMSG",
+		Usage:   `The path to a file to parse tags from, or "-" to read from stdin.`,
+	})
+}
+
+// NewLocal creates a new local source.
+func NewLocal(ctx context.Context, cfg *LocalConfig) (*Local, error) {
+	if cfg.LocalInput == "" {
+		cfg.LocalInput = localStdinInput
+	}
+	if cfg.Stdin == nil {
+		cfg.Stdin = os.Stdin
+	}
+
+	return &Local{
+		cfg: cfg,
+	}, nil
+}
+
+// GetRequestBody gets the contents of the local input.
+func (l *Local) GetRequestBody(ctx context.Context) (string, error) {
+	body, err := l.read()
+	if err != nil {
+		return "", fmt.Errorf("failed to get request body: %w", err)
+	}
+	return body, nil
+}
+
+// GetIssueBody gets the contents of the local input.
+func (l *Local) GetIssueBody(ctx context.Context) (string, error) {
+	body, err := l.read()
+	if err != nil {
+		return "", fmt.Errorf("failed to get issue body: %w", err)
+	}
+	return body, nil
+}
+
+// UpdateRequestBody replaces the contents of the local input file if they were
+// not changed since they were read.
+func (l *Local) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	if err := l.write(oldBody, newBody); err != nil {
+		return fmt.Errorf("failed to update request body: %w", err)
+	}
+	return nil
+}
+
+// UpdateIssueBody replaces the contents of the local input file if they were
+// not changed since they were read.
+func (l *Local) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	if err := l.write(oldBody, newBody); err != nil {
+		return fmt.Errorf("failed to update issue body: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) read() (string, error) {
+	if l.cfg.LocalInput == localStdinInput {
+		b, err := io.ReadAll(l.cfg.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return string(b), nil
+	}
+
+	b, err := os.ReadFile(l.cfg.LocalInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return string(b), nil
+}
+
+// write replaces the contents of the local input file, keeping its
+// permissions. Stdin can not be written to.
+func (l *Local) write(oldBody, newBody string) error {
+	if l.cfg.LocalInput == localStdinInput {
+		return fmt.Errorf("stdin can not be updated, use a file as -local-input: %w", errors.ErrUnsupported)
+	}
+
+	current, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	return compareAndSwapBody(current, oldBody, newBody, func() error {
+		info, err := os.Stat(l.cfg.LocalInput)
+		if err != nil {
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		if err := os.WriteFile(l.cfg.LocalInput, []byte(newBody), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		return nil
+	})
+}
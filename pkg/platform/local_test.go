@@ -0,0 +1,172 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestLocal_GetRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("from a file\n\nTAG_1=file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		input string
+		stdin string
+		exp   string
+		err   string
+	}{
+		{
+			name:  "stdin",
+			input: "-",
+			stdin: "from stdin\n\nTAG_1=stdin\n",
+			exp:   "from stdin\n\nTAG_1=stdin\n",
+		},
+		{
+			name:  "default_stdin",
+			input: "",
+			stdin: "TAG_1=stdin\n",
+			exp:   "TAG_1=stdin\n",
+		},
+		{
+			name:  "file",
+			input: path,
+			exp:   "from a file\n\nTAG_1=file\n",
+		},
+		{
+			name:  "missing_file",
+			input: filepath.Join(dir, "does-not-exist"),
+			err:   "failed to read file",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := NewLocal(ctx, &LocalConfig{
+				LocalInput: tc.input,
+				Stdin:      strings.NewReader(tc.stdin),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := l.GetRequestBody(ctx)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("body not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestLocal_UpdateRequestBody(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name    string
+		input   string
+		current string
+		oldBody string
+		newBody string
+		exp     string
+		err     string
+	}{
+		{
+			name:    "updated",
+			input:   "COMMIT_EDITMSG",
+			current: "TAG=a\n",
+			oldBody: "TAG=a\n",
+			newBody: "TAG=b\n",
+			exp:     "TAG=b\n",
+		},
+		{
+			name:    "changed",
+			input:   "COMMIT_EDITMSG",
+			current: "TAG=edited\n",
+			oldBody: "TAG=a\n",
+			newBody: "TAG=b\n",
+			exp:     "TAG=edited\n",
+			err:     "body was changed since it was fetched",
+		},
+		{
+			name:    "stdin",
+			input:   "-",
+			oldBody: "TAG=a\n",
+			newBody: "TAG=b\n",
+			err:     "stdin can not be updated",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := tc.input
+			if input != "-" {
+				input = filepath.Join(t.TempDir(), tc.input)
+				if err := os.WriteFile(input, []byte(tc.current), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			l, err := NewLocal(ctx, &LocalConfig{
+				LocalInput: input,
+				Stdin:      strings.NewReader(tc.current),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = l.UpdateRequestBody(ctx, tc.oldBody, tc.newBody)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if input == "-" {
+				return
+			}
+			b, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(b), tc.exp); diff != "" {
+				t.Errorf("file not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SearchFunc is called for every item found by a search. It is never called
+// concurrently, returning an error stops the search.
+type SearchFunc func(item *Item) error
+
+// pageFunc fetches a single page of results and returns the number of the
+// last page.
+type pageFunc func(ctx context.Context, page int) (int, error)
+
+// fetchPages fetches the first page on its own to discover the number of the
+// last page and then fetches the remaining pages with at most concurrency
+// requests in flight. The first error cancels all remaining requests.
+func fetchPages(ctx context.Context, concurrency int, fetch pageFunc) error {
+	lastPage, err := fetch(ctx, 1)
+	if err != nil {
+		return fmt.Errorf("failed to fetch page 1: %w", err)
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		merr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for page := 2; page <= lastPage; page++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, err := fetch(ctx, page); err != nil {
+				mu.Lock()
+				merr = errors.Join(merr, fmt.Errorf("failed to fetch page %d: %w", page, err))
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if merr != nil {
+		return merr
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to fetch pages: %w", err)
+	}
+	return nil
+}
+
+// nextPageFunc fetches a single page of results and returns the number of
+// the next page, or 0 after the last page.
+type nextPageFunc func(ctx context.Context, page int) (int, error)
+
+// fetchNextPages fetches the pages one after another starting at page, for
+// results without the number of the last page. Nothing is fetched if page is
+// 0.
+func fetchNextPages(ctx context.Context, page int, fetch nextPageFunc) error {
+	for page != 0 {
+		next, err := fetch(ctx, page)
+		if err != nil {
+			return fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
+		if next != 0 && next <= page {
+			return fmt.Errorf("next page %d does not follow page %d", next, page)
+		}
+		page = next
+	}
+	return nil
+}
+
+// serialize wraps fn so that it is never called concurrently.
+func serialize(fn SearchFunc) SearchFunc {
+	var mu sync.Mutex
+	return func(item *Item) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(item)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestFetchPages(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		concurrency int
+		lastPage    int
+		failPage    int
+		expPages    []int
+		err         string
+	}{
+		{
+			name:        "single_page",
+			concurrency: 4,
+			lastPage:    0,
+			expPages:    []int{1},
+		},
+		{
+			name:        "all_pages",
+			concurrency: 3,
+			lastPage:    7,
+			expPages:    []int{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name:        "zero_concurrency",
+			concurrency: 0,
+			lastPage:    3,
+			expPages:    []int{1, 2, 3},
+		},
+		{
+			name:        "first_page_error",
+			concurrency: 2,
+			lastPage:    3,
+			failPage:    1,
+			err:         "failed to fetch page 1: boom",
+		},
+		{
+			name:        "later_page_error",
+			concurrency: 1,
+			lastPage:    3,
+			failPage:    2,
+			err:         "failed to fetch page 2: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu       sync.Mutex
+				pages    []int
+				inFlight int64
+				maxSeen  int64
+			)
+
+			err := fetchPages(t.Context(), tc.concurrency, func(ctx context.Context, page int) (int, error) {
+				n := atomic.AddInt64(&inFlight, 1)
+				defer atomic.AddInt64(&inFlight, -1)
+
+				mu.Lock()
+				if n > maxSeen {
+					maxSeen = n
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				if page == tc.failPage {
+					return 0, fmt.Errorf("boom")
+				}
+
+				mu.Lock()
+				pages = append(pages, page)
+				mu.Unlock()
+
+				return tc.lastPage, nil
+			})
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			if tc.err != "" {
+				return
+			}
+
+			sort.Ints(pages)
+			if diff := cmp.Diff(pages, tc.expPages); diff != "" {
+				t.Errorf("pages not as expected; (-got,+want): %s", diff)
+			}
+
+			if limit := int64(max(tc.concurrency, 1)); maxSeen > limit {
+				t.Errorf("expected at most %d concurrent requests, got %d", limit, maxSeen)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package platform
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 )
@@ -27,24 +28,38 @@ const (
 	TypeUnspecified = ""
 	TypeGitHub      = "github"
 	TypeGitLab      = "gitlab"
+	TypeGit         = "git"
+	TypeLocal       = "local"
+	TypeGitea       = "gitea"
+	TypeBitbucket   = "bitbucket"
+	TypeAzureDevOps = "azuredevops"
+	TypeGerrit      = "gerrit"
+	TypeJira        = "jira"
 )
 
 var (
 	allowedTypes = map[string]struct{}{
-		TypeGitHub: {},
-		TypeGitLab: {},
+		TypeGitHub:      {},
+		TypeGitLab:      {},
+		TypeGit:         {},
+		TypeLocal:       {},
+		TypeGitea:       {},
+		TypeBitbucket:   {},
+		TypeAzureDevOps: {},
+		TypeGerrit:      {},
+		TypeJira:        {},
 	}
 	// SortedTypes are the sorted Platform types for printing messages and prediction.
 	SortedTypes = func() []string {
-		allowed := append([]string{}, TypeGitHub, TypeGitLab)
+		allowed := append([]string{}, TypeGitHub, TypeGitLab, TypeGit, TypeLocal, TypeGitea, TypeBitbucket, TypeAzureDevOps, TypeGerrit, TypeJira)
 		sort.Strings(allowed)
 		return allowed
 	}()
-
-	_ Platform = (*GitHub)(nil)
 )
 
-// Platform defines the minimum interface for a code review platform.
+// Platform defines the minimum interface for a code review platform. The
+// other features of a platform are separate interfaces, such as Labeler, that
+// callers detect with a type assertion.
 type Platform interface {
 	// GetRequestBody gets the Pull Request or Merge Request body.
 	GetRequestBody(ctx context.Context) (string, error)
@@ -53,8 +68,205 @@ type Platform interface {
 	GetIssueBody(ctx context.Context) (string, error)
 }
 
-// NewPlatform creates a new platform based on the provided type.
-func NewPlatform(ctx context.Context, cfg *Config) (Platform, error) {
+// RangeLister is implemented by platforms that can list the requests merged
+// in a range of commits.
+type RangeLister interface {
+	// ListRequestsInRange lists the merged Pull Requests or Merge Requests
+	// associated with the commits between the base and head revisions.
+	ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error)
+}
+
+// Searcher is implemented by platforms that can search for issues and
+// requests.
+type Searcher interface {
+	// SearchItems calls fn for every Issue, Pull Request or Merge Request that
+	// matches the platform specific query, fetching at most concurrency pages
+	// at the same time. Platforms with stricter search rate limits may fetch
+	// fewer.
+	SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error
+}
+
+// LinkedIssueLister is implemented by platforms that know the issues linked
+// from a request.
+type LinkedIssueLister interface {
+	// ListLinkedIssues lists the issues linked from the Pull Request or Merge
+	// Request, such as the issues it closes when merged.
+	ListLinkedIssues(ctx context.Context) ([]*Item, error)
+}
+
+// TicketReferencer is implemented by platforms whose requests reference
+// tickets of an issue tracker outside of their body, such as Jira keys in the
+// title or branch of a Bitbucket pull request.
+type TicketReferencer interface {
+	// ListTicketKeys lists the keys of the tickets the Pull Request or Merge
+	// Request references outside of its body.
+	ListTicketKeys(ctx context.Context) ([]string, error)
+}
+
+// BodyUpdater is implemented by platforms that can edit the body of requests
+// and issues.
+type BodyUpdater interface {
+	// UpdateRequestBody replaces the body of the Pull Request or Merge Request
+	// with newBody if it is still oldBody, and returns a *BodyChangedError with
+	// the current body otherwise.
+	UpdateRequestBody(ctx context.Context, oldBody, newBody string) error
+
+	// UpdateIssueBody replaces the body of the issue with newBody if it is
+	// still oldBody, and returns a *BodyChangedError with the current body
+	// otherwise.
+	UpdateIssueBody(ctx context.Context, oldBody, newBody string) error
+}
+
+// Commenter is implemented by platforms that can comment on requests.
+type Commenter interface {
+	// UpsertRequestComment updates the comment on the Pull Request or Merge
+	// Request that contains marker, or creates one if there is none, so
+	// repeated runs keep a single comment. The body must contain the marker.
+	UpsertRequestComment(ctx context.Context, marker, body string) error
+}
+
+// StatusPublisher is implemented by platforms that can publish a status on
+// the head commit of requests.
+type StatusPublisher interface {
+	// PublishRequestStatus publishes the status on the head commit of the Pull
+	// Request or Merge Request, so branch protection can require it to pass.
+	PublishRequestStatus(ctx context.Context, status *Status) error
+}
+
+// Labeler is implemented by platforms with labels on requests and issues.
+type Labeler interface {
+	// ListLabels lists the labels of the Pull Request, Merge Request or issue.
+	ListLabels(ctx context.Context, kind ItemKind) ([]string, error)
+
+	// AddLabels adds the labels to the Pull Request, Merge Request or issue.
+	// Labels it already has are kept.
+	AddLabels(ctx context.Context, kind ItemKind, labels []string) error
+
+	// RemoveLabels removes the labels from the Pull Request, Merge Request or
+	// issue. Labels it does not have are ignored.
+	RemoveLabels(ctx context.Context, kind ItemKind, labels []string) error
+}
+
+// ItemKind selects whether a method operates on the configured Pull Request or
+// Merge Request, or on the configured issue.
+type ItemKind string
+
+const (
+	ItemKindRequest ItemKind = "request"
+	ItemKindIssue   ItemKind = "issue"
+)
+
+// BodyChangedError is returned when updating a body that was changed, e.g. by
+// a person editing it, since it was fetched. Callers should apply their change
+// to the Current body and try again.
+type BodyChangedError struct {
+	Current string
+}
+
+func (e *BodyChangedError) Error() string {
+	return "body was changed since it was fetched"
+}
+
+// compareAndSwapBody calls update if the current body is still oldBody and
+// differs from newBody.
+func compareAndSwapBody(current, oldBody, newBody string, update func() error) error {
+	if current != oldBody {
+		return &BodyChangedError{Current: current}
+	}
+	if current == newBody {
+		return nil
+	}
+	return update()
+}
+
+// Status is the result of validating the tags of a Pull Request or Merge
+// Request.
+type Status struct {
+	// Name identifies the status, e.g. in branch protection rules.
+	Name string
+	// Success reports whether all tags are valid.
+	Success bool
+	// Title is a one line description of the result.
+	Title string
+	// Summary describes the result in Markdown.
+	Summary string
+	// Annotations point at the lines of the body with invalid tags or
+	// warnings.
+	Annotations []*Annotation
+}
+
+// Annotation is a message about a line of the Pull Request or Merge Request
+// body.
+type Annotation struct {
+	// Line is the 1-based line of the body, or 0 for messages about the whole
+	// body, e.g. missing tags.
+	Line    int
+	Message string
+	// Warning reports whether the message is about a problem that does not fail
+	// the status.
+	Warning bool
+}
+
+// Item is a Pull Request, Merge Request or Issue on a code review platform.
+type Item struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Body   string `json:"-"`
+}
+
+// Option configures a platform created by NewPlatform.
+type Option func(cfg *Config)
+
+// WithHTTPClient sets the HTTP client that sends the API requests of the
+// platform, instead of http.DefaultClient or the client of the CacheDir and
+// HTTPCassette options.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *Config) {
+		cfg.setHTTPClient(client)
+	}
+}
+
+// NewPlatform creates a new platform based on the provided type. The config is
+// usually populated from flags, but can also be built directly, e.g.
+//
+//	platform.NewPlatform(ctx, &platform.Config{
+//		Type:       platform.TypeGitHub,
+//		MaxRetries: 5,
+//		CacheDir:   "/tmp/tagrep-cache",
+//		GitHub: platform.GitHubConfig{
+//			GitHubToken:             token,
+//			GitHubOwner:             "abcxyz",
+//			GitHubRepo:              "tagrep",
+//			GitHubPullRequestNumber: 1,
+//		},
+//	})
+//
+// The shared retry, caching and recording options of cfg apply to the
+// platform as they do for flags. cfg is not modified.
+//
+// Features beyond reading bodies are separate interfaces that the returned
+// platform implements when it supports them, e.g.
+//
+//	if labeler, ok := p.(platform.Labeler); ok {
+//		labels, err := labeler.ListLabels(ctx, platform.ItemKindRequest)
+//	}
+func NewPlatform(ctx context.Context, cfg *Config, opts ...Option) (Platform, error) {
+	c := *cfg
+	cfg = &c
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	cfg.applyRetryOptions()
+	if cfg.httpClient == nil {
+		if err := cfg.applyHTTPClient(); err != nil {
+			return nil, err
+		}
+	} else {
+		cfg.setHTTPClient(cfg.httpClient)
+	}
+
 	if strings.EqualFold(cfg.Type, TypeGitHub) {
 		gc, err := NewGitHub(ctx, &cfg.GitHub)
 		if err != nil {
@@ -70,5 +282,61 @@ func NewPlatform(ctx context.Context, cfg *Config) (Platform, error) {
 		}
 		return gl, nil
 	}
+
+	if strings.EqualFold(cfg.Type, TypeGit) {
+		g, err := NewGit(ctx, &cfg.Git)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create git: %w", err)
+		}
+		return g, nil
+	}
+
+	if strings.EqualFold(cfg.Type, TypeLocal) {
+		l, err := NewLocal(ctx, &cfg.Local)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local: %w", err)
+		}
+		return l, nil
+	}
+
+	if strings.EqualFold(cfg.Type, TypeGitea) {
+		g, err := NewGitea(ctx, &cfg.Gitea)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gitea: %w", err)
+		}
+		return g, nil
+	}
+
+	if strings.EqualFold(cfg.Type, TypeBitbucket) {
+		b, err := NewBitbucket(ctx, &cfg.Bitbucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bitbucket: %w", err)
+		}
+		return b, nil
+	}
+
+	if strings.EqualFold(cfg.Type, TypeAzureDevOps) {
+		a, err := NewAzureDevOps(ctx, &cfg.AzureDevOps)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure devops: %w", err)
+		}
+		return a, nil
+	}
+
+	if strings.EqualFold(cfg.Type, TypeGerrit) {
+		g, err := NewGerrit(ctx, &cfg.Gerrit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gerrit: %w", err)
+		}
+		return g, nil
+	}
+
+	if strings.EqualFold(cfg.Type, TypeJira) {
+		j, err := NewJira(ctx, &cfg.Jira)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create jira: %w", err)
+		}
+		return j, nil
+	}
 	return nil, fmt.Errorf("unknown platform type: %s", cfg.Type)
 }
@@ -37,6 +37,28 @@ type MockPlatform struct {
 	GetRequestBodyResponse string
 	GetIssueBodyErr        error
 	GetIssueBodyResponse   string
+
+	ListRequestsInRangeErr      error
+	ListRequestsInRangeResponse []*Item
+	SearchItemsErr              error
+	SearchItemsResponse         []*Item
+	ListLinkedIssuesErr         error
+	ListLinkedIssuesResponse    []*Item
+	ListTicketKeysErr           error
+	ListTicketKeysResponse      []string
+
+	// UpdateRequestBodyErrs and UpdateIssueBodyErrs are returned by
+	// consecutive calls, later calls succeed.
+	UpdateRequestBodyErrs []error
+	UpdateIssueBodyErrs   []error
+
+	UpsertRequestCommentErr error
+	PublishRequestStatusErr error
+
+	ListLabelsErr      error
+	ListLabelsResponse []string
+	AddLabelsErr       error
+	RemoveLabelsErr    error
 }
 
 func (m *MockPlatform) GetRequestBody(ctx context.Context) (string, error) {
@@ -68,3 +90,186 @@ func (m *MockPlatform) GetIssueBody(ctx context.Context) (string, error) {
 
 	return m.GetIssueBodyResponse, nil
 }
+
+func (m *MockPlatform) ListRequestsInRange(ctx context.Context, base, head string) ([]*Item, error) {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "ListRequestsInRange",
+		Params: []any{base, head},
+	})
+
+	if m.ListRequestsInRangeErr != nil {
+		return nil, m.ListRequestsInRangeErr
+	}
+
+	return m.ListRequestsInRangeResponse, nil
+}
+
+func (m *MockPlatform) SearchItems(ctx context.Context, query string, concurrency int, fn SearchFunc) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "SearchItems",
+		Params: []any{query, concurrency},
+	})
+
+	if m.SearchItemsErr != nil {
+		return m.SearchItemsErr
+	}
+
+	for _, item := range m.SearchItemsResponse {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockPlatform) ListLinkedIssues(ctx context.Context) ([]*Item, error) {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "ListLinkedIssues",
+		Params: []any{},
+	})
+
+	if m.ListLinkedIssuesErr != nil {
+		return nil, m.ListLinkedIssuesErr
+	}
+
+	return m.ListLinkedIssuesResponse, nil
+}
+
+func (m *MockPlatform) ListTicketKeys(ctx context.Context) ([]string, error) {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "ListTicketKeys",
+		Params: []any{},
+	})
+
+	if m.ListTicketKeysErr != nil {
+		return nil, m.ListTicketKeysErr
+	}
+
+	return m.ListTicketKeysResponse, nil
+}
+
+func (m *MockPlatform) UpdateRequestBody(ctx context.Context, oldBody, newBody string) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "UpdateRequestBody",
+		Params: []any{oldBody, newBody},
+	})
+
+	if len(m.UpdateRequestBodyErrs) > 0 {
+		err := m.UpdateRequestBodyErrs[0]
+		m.UpdateRequestBodyErrs = m.UpdateRequestBodyErrs[1:]
+		return err
+	}
+
+	return nil
+}
+
+func (m *MockPlatform) UpdateIssueBody(ctx context.Context, oldBody, newBody string) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "UpdateIssueBody",
+		Params: []any{oldBody, newBody},
+	})
+
+	if len(m.UpdateIssueBodyErrs) > 0 {
+		err := m.UpdateIssueBodyErrs[0]
+		m.UpdateIssueBodyErrs = m.UpdateIssueBodyErrs[1:]
+		return err
+	}
+
+	return nil
+}
+
+func (m *MockPlatform) UpsertRequestComment(ctx context.Context, marker, body string) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "UpsertRequestComment",
+		Params: []any{marker, body},
+	})
+
+	return m.UpsertRequestCommentErr
+}
+
+func (m *MockPlatform) PublishRequestStatus(ctx context.Context, status *Status) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "PublishRequestStatus",
+		Params: []any{status},
+	})
+
+	return m.PublishRequestStatusErr
+}
+
+func (m *MockPlatform) ListLabels(ctx context.Context, kind ItemKind) ([]string, error) {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "ListLabels",
+		Params: []any{kind},
+	})
+
+	if m.ListLabelsErr != nil {
+		return nil, m.ListLabelsErr
+	}
+
+	return m.ListLabelsResponse, nil
+}
+
+func (m *MockPlatform) AddLabels(ctx context.Context, kind ItemKind, labels []string) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "AddLabels",
+		Params: []any{kind, labels},
+	})
+
+	return m.AddLabelsErr
+}
+
+func (m *MockPlatform) RemoveLabels(ctx context.Context, kind ItemKind, labels []string) error {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "RemoveLabels",
+		Params: []any{kind, labels},
+	})
+
+	return m.RemoveLabelsErr
+}
+
+var _ TicketSource = (*MockTicketSource)(nil)
+
+type MockTicketSource struct {
+	reqMu sync.Mutex
+	Reqs  []*Request
+
+	GetTicketBodyErr       error
+	GetTicketBodyResponses map[string]string
+}
+
+func (m *MockTicketSource) GetTicketBody(ctx context.Context, key string) (string, error) {
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+	m.Reqs = append(m.Reqs, &Request{
+		Name:   "GetTicketBody",
+		Params: []any{key},
+	})
+
+	if m.GetTicketBodyErr != nil {
+		return "", m.GetTicketBodyErr
+	}
+
+	return m.GetTicketBodyResponses[key], nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+// countingTransport counts the requests it sends.
+type countingTransport struct {
+	count atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count.Add(1)
+	return http.DefaultTransport.RoundTrip(req) //nolint:wrapcheck // Want passthrough
+}
+
+func TestNewPlatform_Config(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	client := &countingTransport{}
+
+	cases := []struct {
+		name   string
+		status int
+		cfg    func(dir string) *Config
+		opts   []Option
+		calls  int
+		err    string
+		// expRequests are the requests the server receives.
+		expRequests int
+		// expClientRequests are the requests sent by the WithHTTPClient client.
+		expClientRequests int
+		// expFile is a file expected in dir after the calls.
+		expFile string
+	}{
+		{
+			name:   "retry_options",
+			status: http.StatusInternalServerError,
+			cfg: func(dir string) *Config {
+				return &Config{MaxRetries: 2, InitialRetryDelay: time.Millisecond, MaxRetryDelay: time.Millisecond}
+			},
+			calls:       1,
+			err:         "failed to get pull request body",
+			expRequests: 3,
+		},
+		{
+			name:   "no_retries",
+			status: http.StatusInternalServerError,
+			cfg: func(dir string) *Config {
+				return &Config{MaxRetries: NoRetries}
+			},
+			calls:       1,
+			err:         "failed to get pull request body",
+			expRequests: 1,
+		},
+		{
+			name:   "cache_dir",
+			status: http.StatusOK,
+			cfg: func(dir string) *Config {
+				return &Config{CacheDir: dir, CacheTTL: time.Hour}
+			},
+			calls:       2,
+			expRequests: 1,
+		},
+		{
+			name:   "http_cassette",
+			status: http.StatusOK,
+			cfg: func(dir string) *Config {
+				return &Config{HTTPCassette: filepath.Join(dir, "cassette.json"), HTTPCassetteMode: CassetteModeRecord}
+			},
+			calls:       1,
+			expRequests: 1,
+			expFile:     "cassette.json",
+		},
+		{
+			name:   "with_http_client",
+			status: http.StatusOK,
+			cfg: func(dir string) *Config {
+				return &Config{CacheDir: dir, CacheTTL: time.Hour}
+			},
+			opts:              []Option{WithHTTPClient(&http.Client{Transport: client})},
+			calls:             2,
+			expRequests:       2,
+			expClientRequests: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("ETag", `"v1"`)
+				w.WriteHeader(tc.status)
+				if tc.status == http.StatusOK {
+					w.Write([]byte(`{"number":1,"body":"WANT_LGTM=all"}`))
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			dir := t.TempDir()
+			cfg := tc.cfg(dir)
+			cfg.Type = TypeGitea
+			cfg.Gitea = GiteaConfig{
+				GiteaServerURL:         srv.URL,
+				GiteaOwner:             "owner",
+				GiteaRepo:              "repo",
+				GiteaPullRequestNumber: 1,
+			}
+			want := *cfg
+
+			p, err := NewPlatform(ctx, cfg, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for range tc.calls {
+				body, err := p.GetRequestBody(ctx)
+				if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+					t.Error(diff)
+				}
+				if err == nil && body != "WANT_LGTM=all" {
+					t.Errorf("expected body %q to be %q", body, "WANT_LGTM=all")
+				}
+			}
+
+			if got, want := requests.Load(), int64(tc.expRequests); got != want {
+				t.Errorf("expected %d requests to the server, got %d", want, got)
+			}
+			if tc.expClientRequests > 0 {
+				if got, want := client.count.Load(), int64(tc.expClientRequests); got != want {
+					t.Errorf("expected %d requests from the http client, got %d", want, got)
+				}
+			}
+			if tc.expFile != "" {
+				b, err := os.ReadFile(filepath.Join(dir, tc.expFile))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(b), "/api/v1/repos/owner/repo/pulls/1") {
+					t.Errorf("expected %s to record the request, got %s", tc.expFile, b)
+				}
+			}
+
+			// The config of the caller is not modified.
+			if !reflect.DeepEqual(cfg, &want) {
+				t.Errorf("expected config to not be modified, got %#v, want %#v", cfg, &want)
+			}
+		})
+	}
+}
+
+func TestConfig_MaxRetriesFlag(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name          string
+		args          []string
+		expMaxRetries uint64
+	}{
+		{
+			name:          "default",
+			expMaxRetries: 3,
+		},
+		{
+			name:          "max_retries",
+			args:          []string{"-max-retries=5"},
+			expMaxRetries: 5,
+		},
+		{
+			name:          "no_retries",
+			args:          []string{"-max-retries=0"},
+			expMaxRetries: NoRetries,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg Config
+			set := cli.NewFlagSet(cli.WithLookupEnv(cli.MapLookuper(nil)))
+			cfg.RegisterFlagsContext(ctx, set)
+			if err := set.Parse(append([]string{"-platform=local"}, tc.args...)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := cfg.MaxRetries, tc.expMaxRetries; got != want {
+				t.Errorf("expected max retries %d, got %d", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/tagrep/internal/version"
+)
+
+// maxErrorBodySize is the maximum number of bytes of an error response body
+// included in error messages.
+const maxErrorBodySize = 1024
+
+// restIgnoredStatusCodes are status codes that should not be retried for
+// platforms using the restClient.
+var restIgnoredStatusCodes = map[int]struct{}{
+	400: {},
+	401: {},
+	403: {},
+	404: {},
+	405: {},
+	409: {},
+	422: {},
+}
+
+// restClient is a minimal JSON client for the REST APIs of platforms that do
+// not have a Go client library.
+type restClient struct {
+	httpClient *http.Client
+	baseURL    string
+
+	// auth adds credentials to every request.
+	auth func(r *http.Request)
+
+	// responsePrefix is stripped from every response body before decoding, for
+	// APIs that prefix JSON to prevent cross site script inclusion.
+	responsePrefix string
+}
+
+// restError is returned for non-2xx responses.
+type restError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *restError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// restRequestError is returned when a request cannot be built, e.g. because
+// its body cannot be encoded. Building it again fails the same way, so it is
+// not retried.
+type restRequestError struct {
+	err error
+}
+
+func (e *restRequestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *restRequestError) Unwrap() error {
+	return e.err
+}
+
+// do sends a request to the path relative to the base URL, or to path itself
+// when it is an absolute URL such as a pagination link. Absolute URLs must have
+// the scheme and host of the base URL, so credentials are not sent elsewhere.
+// It encodes in as the JSON request body when non-nil and decodes the JSON
+// response body into out when non-nil. The response is returned even when the
+// request failed so the caller can decide whether to retry.
+func (c *restClient) do(ctx context.Context, method, path string, query url.Values, in, out any) (*http.Response, error) {
+	u, err := c.requestURL(path)
+	if err != nil {
+		return nil, &restRequestError{err: err}
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, &restRequestError{err: fmt.Errorf("failed to marshal request body: %w", err)}
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, &restRequestError{err: fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != nil {
+		c.auth(req)
+	}
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(b) > maxErrorBodySize {
+			b = b[:maxErrorBodySize]
+		}
+		return resp, &restError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(b)),
+		}
+	}
+
+	if out == nil {
+		return resp, nil
+	}
+
+	b = bytes.TrimPrefix(b, []byte(c.responsePrefix))
+	if err := json.Unmarshal(b, out); err != nil {
+		return resp, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return resp, nil
+}
+
+// requestURL returns the URL of a request to path, which is either relative to
+// the base URL or an absolute URL with the scheme and host of the base URL.
+func (c *restClient) requestURL(path string) (string, error) {
+	p, err := url.Parse(path)
+	if err != nil || !p.IsAbs() {
+		return strings.TrimSuffix(c.baseURL, "/") + "/" + strings.TrimPrefix(path, "/"), nil
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base url: %w", err)
+	}
+	if !strings.EqualFold(p.Scheme, base.Scheme) || !strings.EqualFold(p.Host, base.Host) {
+		return "", fmt.Errorf("url %s is not on the host of %s", p.Redacted(), base.Redacted())
+	}
+	return path, nil
+}
+
+// restMaybeRetryable marks the error as retryable unless the response status
+// code is one that should not be retried. Errors without a response (e.g.
+// network errors) are always retried, except errors building the request.
+func restMaybeRetryable(resp *http.Response, err error) error {
+	var reqErr *restRequestError
+	if errors.As(err, &reqErr) {
+		return err
+	}
+	if resp == nil {
+		return retry.RetryableError(err)
+	}
+	if _, ok := restIgnoredStatusCodes[resp.StatusCode]; !ok {
+		return retry.RetryableError(err)
+	}
+	return err
+}
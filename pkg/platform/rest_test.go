@@ -0,0 +1,114 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestRestClient_Do(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     func(srvURL string) string
+		in       any
+		err      string
+		expAuth  []string
+		expCalls int
+	}{
+		{
+			name:     "relative_path",
+			path:     func(srvURL string) string { return "/items" },
+			expAuth:  []string{"Bearer token"},
+			expCalls: 1,
+		},
+		{
+			name:     "absolute_url_same_host",
+			path:     func(srvURL string) string { return srvURL + "/api/items?page=2" },
+			expAuth:  []string{"Bearer token"},
+			expCalls: 1,
+		},
+		{
+			name:     "absolute_url_other_host",
+			path:     func(srvURL string) string { return "https://example.com/api/items?page=2" },
+			err:      "url https://example.com/api/items?page=2 is not on the host of",
+			expCalls: 1,
+		},
+		{
+			name:     "absolute_url_other_scheme",
+			path:     func(srvURL string) string { return "https" + srvURL[len("http"):] + "/api/items" },
+			err:      "is not on the host of",
+			expCalls: 1,
+		},
+		{
+			name:     "marshal_error",
+			path:     func(srvURL string) string { return "/items" },
+			in:       map[string]any{"invalid": make(chan int)},
+			err:      "failed to marshal request body",
+			expCalls: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotAuth []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+			}))
+			t.Cleanup(srv.Close)
+
+			c := &restClient{
+				httpClient: srv.Client(),
+				baseURL:    srv.URL + "/api",
+				auth: func(r *http.Request) {
+					r.Header.Set("Authorization", "Bearer token")
+				},
+			}
+
+			// Requests that cannot be built are not retried.
+			var calls int
+			backoff := retry.WithMaxRetries(3, retry.NewConstant(time.Millisecond))
+			err := retry.Do(t.Context(), backoff, func(ctx context.Context) error {
+				calls++
+				resp, err := c.do(ctx, http.MethodPost, tc.path(srv.URL), nil, tc.in, nil)
+				if err != nil {
+					return restMaybeRetryable(resp, err)
+				}
+				return nil
+			})
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if got, want := calls, tc.expCalls; got != want {
+				t.Errorf("calls not as expected; got %d, want %d", got, want)
+			}
+			if diff := cmp.Diff(gotAuth, tc.expAuth); diff != "" {
+				t.Errorf("authorization headers not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+// NoRetries is the MaxRetries of a platform that does not retry failed
+// requests, since a MaxRetries of 0 uses the default of 3 retries.
+const NoRetries uint64 = math.MaxUint64
+
+// retryLimit returns the number of retries for the MaxRetries of a platform.
+func retryLimit(maxRetries uint64) uint64 {
+	if maxRetries == NoRetries {
+		return 0
+	}
+	return maxRetries
+}
+
+// retryOptions configure retrying requests that are rate limited or fail with
+// a server error.
+type retryOptions struct {
+	MaxRetries        uint64
+	InitialRetryDelay time.Duration
+	MaxRetryDelay     time.Duration
+	// MaxRateLimitWait is the longest a rate limited request waits to be
+	// retried. Requests rate limited for longer fail immediately.
+	MaxRateLimitWait time.Duration
+}
+
+// rateLimitError is a retryable error for a rate limited request that must not
+// be retried before the delay has passed.
+type rateLimitError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rateLimitError) Unwrap() error {
+	return e.err
+}
+
+// withRateLimitRetries calls retryFunc until it succeeds, returns an error
+// that is not retryable or the retries are exhausted. Retries of rate limited
+// requests are delayed until the rate limit resets.
+func withRateLimitRetries(ctx context.Context, opts *retryOptions, retryFunc retry.RetryFunc) error {
+	var wait time.Duration
+
+	base := retry.NewFibonacci(opts.InitialRetryDelay)
+	base = retry.WithMaxRetries(retryLimit(opts.MaxRetries), base)
+	base = retry.WithCappedDuration(opts.MaxRetryDelay, base)
+	backoff := retry.BackoffFunc(func() (time.Duration, bool) {
+		next, stop := base.Next()
+		if stop {
+			return 0, true
+		}
+		next = max(next, wait)
+		wait = 0
+		return next, false
+	})
+
+	if err := retry.Do(ctx, backoff, func(ctx context.Context) error {
+		err := retryFunc(ctx)
+
+		var rlErr *rateLimitError
+		if errors.As(err, &rlErr) {
+			if rlErr.delay > opts.MaxRateLimitWait {
+				// Not wrapped as retryable, so the request fails immediately.
+				return fmt.Errorf("rate limited for %s, longer than the maximum wait of %s: %w",
+					rlErr.delay, opts.MaxRateLimitWait, rlErr.err)
+			}
+			logging.FromContext(ctx).WarnContext(ctx, "request was rate limited, retrying",
+				"delay", rlErr.delay.String())
+			wait = rlErr.delay
+		}
+
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to execute retriable function: %w", err)
+	}
+	return nil
+}
+
+// httpMaybeRetryable marks the error as retryable if the request was rate
+// limited, failed with a server error or failed without a response (e.g.
+// network errors). Client errors are never retried.
+func httpMaybeRetryable(resp *http.Response, err error) error {
+	if resp == nil {
+		return retry.RetryableError(err)
+	}
+
+	if delay, ok := rateLimitDelay(resp, time.Now()); ok {
+		return retry.RetryableError(&rateLimitError{err: err, delay: delay})
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return retry.RetryableError(err)
+	}
+	return err
+}
+
+// rateLimitDelay reports whether the response is rate limited and how long to
+// wait before retrying. GitHub signals rate limits with a 403 or 429 status
+// code and X-RateLimit-* headers, GitLab with a 429 status code and
+// RateLimit-* headers. Both set Retry-After for secondary rate limits. See
+// https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api
+// and https://docs.gitlab.com/ee/user/admin_area/settings/user_and_ip_rate_limits.html.
+func rateLimitDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return max(time.Duration(seconds)*time.Second, 0), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+
+	if delay, ok := rateLimitResetDelay(resp.Header, now); ok {
+		return delay, true
+	}
+
+	// A 403 without rate limit headers is a permission error.
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return 0, true
+	}
+	return 0, false
+}
+
+// rateLimitResetDelay reports whether the rate limit headers show no remaining
+// requests and how long until the rate limit resets.
+func rateLimitResetDelay(header http.Header, now time.Time) (time.Duration, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if header.Get(prefix+"Remaining") != "0" {
+			continue
+		}
+		if reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0), true
+		}
+	}
+	return 0, false
+}
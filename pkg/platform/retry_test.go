@@ -0,0 +1,187 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestRateLimitDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reset := strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)
+
+	cases := []struct {
+		name     string
+		status   int
+		header   http.Header
+		expDelay time.Duration
+		expOK    bool
+	}{
+		{
+			name:   "not_found",
+			status: http.StatusNotFound,
+			header: http.Header{"Retry-After": []string{"10"}},
+		},
+		{
+			name:   "forbidden_without_headers",
+			status: http.StatusForbidden,
+			header: http.Header{},
+		},
+		{
+			name:     "github_secondary_rate_limit",
+			status:   http.StatusForbidden,
+			header:   http.Header{"Retry-After": []string{"10"}},
+			expDelay: 10 * time.Second,
+			expOK:    true,
+		},
+		{
+			name:     "retry_after_date",
+			status:   http.StatusTooManyRequests,
+			header:   http.Header{"Retry-After": []string{now.Add(time.Minute).Format(http.TimeFormat)}},
+			expDelay: time.Minute,
+			expOK:    true,
+		},
+		{
+			name:   "github_primary_rate_limit",
+			status: http.StatusForbidden,
+			header: http.Header{
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{reset},
+			},
+			expDelay: 30 * time.Second,
+			expOK:    true,
+		},
+		{
+			name:   "github_remaining_requests",
+			status: http.StatusForbidden,
+			header: http.Header{
+				"X-Ratelimit-Remaining": []string{"10"},
+				"X-Ratelimit-Reset":     []string{reset},
+			},
+		},
+		{
+			name:   "gitlab_rate_limit",
+			status: http.StatusTooManyRequests,
+			header: http.Header{
+				"Ratelimit-Remaining": []string{"0"},
+				"Ratelimit-Reset":     []string{reset},
+			},
+			expDelay: 30 * time.Second,
+			expOK:    true,
+		},
+		{
+			name:   "too_many_requests_without_headers",
+			status: http.StatusTooManyRequests,
+			header: http.Header{},
+			expOK:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			delay, ok := rateLimitDelay(&http.Response{StatusCode: tc.status, Header: tc.header}, now)
+			if got, want := delay, tc.expDelay; got != want {
+				t.Errorf("delay not as expected; got %s, want %s", got, want)
+			}
+			if got, want := ok, tc.expOK; got != want {
+				t.Errorf("ok not as expected; got %t, want %t", got, want)
+			}
+		})
+	}
+}
+
+func TestWithRateLimitRetries(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name        string
+		resp        *http.Response
+		expErr      string
+		expAttempts int
+	}{
+		{
+			name:        "client_error",
+			resp:        &http.Response{StatusCode: http.StatusNotFound},
+			expErr:      "request failed",
+			expAttempts: 1,
+		},
+		{
+			name:        "server_error",
+			resp:        &http.Response{StatusCode: http.StatusBadGateway},
+			expErr:      "request failed",
+			expAttempts: 3,
+		},
+		{
+			name:        "network_error",
+			expErr:      "request failed",
+			expAttempts: 3,
+		},
+		{
+			name: "rate_limited",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+			},
+			expErr:      "request failed",
+			expAttempts: 3,
+		},
+		{
+			name: "rate_limited_too_long",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"Retry-After": []string{"3600"}},
+			},
+			expErr:      "rate limited for 1h0m0s, longer than the maximum wait of 1m0s",
+			expAttempts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int
+			err := withRateLimitRetries(ctx, &retryOptions{
+				MaxRetries:        2,
+				InitialRetryDelay: time.Millisecond,
+				MaxRetryDelay:     time.Millisecond,
+				MaxRateLimitWait:  time.Minute,
+			}, func(ctx context.Context) error {
+				attempts++
+				return httpMaybeRetryable(tc.resp, fmt.Errorf("request failed"))
+			})
+			if diff := testutil.DiffErrString(err, tc.expErr); diff != "" {
+				t.Error(diff)
+			}
+			if got, want := attempts, tc.expAttempts; got != want {
+				t.Errorf("attempts not as expected; got %d, want %d", got, want)
+			}
+		})
+	}
+}
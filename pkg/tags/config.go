@@ -18,26 +18,48 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"sort"
 	"strings"
 
 	"github.com/posener/complete/v2"
 
 	"github.com/abcxyz/pkg/cli"
+	"github.com/abcxyz/pkg/sets"
 )
 
 // Config is the configuration needed to parse tags.
 type Config struct {
-	Format      string
-	ArrayTags   []string
-	StringTags  []string
-	BoolTags    []string
+	Format     string
+	ArrayTags  []string
+	StringTags []string
+	BoolTags   []string
+	// RequiredTags are the tags that must be present.
+	RequiredTags []string
+	// Enums are the allowed values of tags, of each value for array tags.
+	Enums       map[string][]string
 	OutputAll   bool
 	PrettyPrint bool
+
+	enums []string
 }
 
+// RegisterFlags registers the flags for parsing tags and formatting the output.
 func (c *Config) RegisterFlags(set *cli.FlagSet) {
 	f := set.NewSection("TAG OPTIONS")
 
+	c.registerFormatFlags(set, f)
+	c.registerTagFlags(set, f)
+}
+
+// RegisterTagFlags registers only the flags for parsing tags, for commands
+// that control their own output format.
+func (c *Config) RegisterTagFlags(set *cli.FlagSet) {
+	f := set.NewSection("TAG OPTIONS")
+
+	c.registerTagFlags(set, f)
+}
+
+func (c *Config) registerFormatFlags(set *cli.FlagSet, f *cli.FlagSection) {
 	f.StringVar(&cli.StringVar{
 		Name:    "format",
 		Target:  &c.Format,
@@ -48,6 +70,26 @@ func (c *Config) RegisterFlags(set *cli.FlagSet) {
 			return allowedFormats
 		}),
 	})
+	f.BoolVar(&cli.BoolVar{
+		Name:    "pretty",
+		Target:  &c.PrettyPrint,
+		Example: "true",
+		Default: false,
+		Usage:   "Whether to pretty print results for json on multiple lines.",
+	})
+
+	set.AfterParse(func(merr error) error {
+		c.Format = strings.ToLower(strings.TrimSpace(c.Format))
+
+		if !slices.Contains(allowedFormats, c.Format) {
+			merr = errors.Join(merr, fmt.Errorf("unsupported value for format flag: %s", c.Format))
+		}
+
+		return merr
+	})
+}
+
+func (c *Config) registerTagFlags(set *cli.FlagSet, f *cli.FlagSection) {
 	f.StringSliceVar(&cli.StringSliceVar{
 		Name:    "array-tags",
 		Target:  &c.ArrayTags,
@@ -76,21 +118,83 @@ func (c *Config) RegisterFlags(set *cli.FlagSet) {
 		Default: false,
 		Usage:   "Whether to print out all tags present in the resource or only those explicitly set in -array-tags, -string-tags, -bool-tags.",
 	})
-	f.BoolVar(&cli.BoolVar{
-		Name:    "pretty",
-		Target:  &c.PrettyPrint,
-		Example: "true",
-		Default: false,
-		Usage:   "Whether to pretty print results for json on multiple lines.",
+	f.StringSliceVar(&cli.StringSliceVar{
+		Name:    "required-tags",
+		Target:  &c.RequiredTags,
+		Example: "TAG_1",
+		Usage:   "Tags that must be present. Parsing fails if any of them is missing.",
+	})
+	f.StringSliceVar(&cli.StringSliceVar{
+		Name:    "enum",
+		Target:  &c.enums,
+		Example: "TAG_1=VALUE_1|VALUE_2",
+		Usage: "The allowed values of a tag as TAG=VALUE|VALUE, repeat for multiple " +
+			"tags. The values of array tags are each one of the allowed values.",
 	})
 
 	set.AfterParse(func(merr error) error {
-		c.Format = strings.ToLower(strings.TrimSpace(c.Format))
-
-		if !slices.Contains(allowedFormats, c.Format) {
-			merr = errors.Join(merr, fmt.Errorf("unsupported value for format flag: %s", c.Format))
+		if len(c.enums) > 0 {
+			c.Enums = make(map[string][]string, len(c.enums))
+		}
+		for _, e := range c.enums {
+			tag, values, ok := strings.Cut(e, "=")
+			tag = strings.TrimSpace(tag)
+			if !ok || tag == "" || values == "" {
+				merr = errors.Join(merr, fmt.Errorf("invalid enum %q, expected TAG=VALUE|VALUE", e))
+				continue
+			}
+			if _, ok := c.Enums[tag]; ok {
+				merr = errors.Join(merr, fmt.Errorf("duplicate enum for tag %s", tag))
+				continue
+			}
+			c.Enums[tag] = strings.Split(values, "|")
 		}
 
-		return merr
+		return errors.Join(merr, c.validateConstraints())
 	})
 }
+
+// validateConstraints returns an error for every required or enum tag that is
+// not configured, unless OutputAll is set, and for enums of bool tags.
+func (c *Config) validateConstraints() error {
+	targetTags := sets.Union(c.ArrayTags, c.StringTags, c.BoolTags)
+
+	var merr error
+	for _, key := range c.RequiredTags {
+		if !c.OutputAll && !slices.Contains(targetTags, key) {
+			merr = errors.Join(merr, fmt.Errorf("required tag %s is not in -array-tags, -string-tags or -bool-tags", key))
+		}
+	}
+
+	enumKeys := make([]string, 0, len(c.Enums))
+	for key := range c.Enums {
+		enumKeys = append(enumKeys, key)
+	}
+	sort.Strings(enumKeys)
+	for _, key := range enumKeys {
+		switch {
+		case !slices.Contains(targetTags, key):
+			if !c.OutputAll {
+				merr = errors.Join(merr, fmt.Errorf("enum tag %s is not in -array-tags, -string-tags or -bool-tags", key))
+			}
+		case c.tagType(key) == TypeBool:
+			merr = errors.Join(merr, fmt.Errorf("enum is not supported for bool tag %s", key))
+		}
+	}
+	return merr
+}
+
+// tagType returns the type the tag key is parsed as, with the same precedence
+// as processTagValues for tags of several types.
+func (c *Config) tagType(key string) string {
+	switch {
+	case slices.Contains(c.ArrayTags, key):
+		return TypeArray
+	case slices.Contains(c.StringTags, key):
+		return TypeString
+	case slices.Contains(c.BoolTags, key):
+		return TypeBool
+	default:
+		return TypeString
+	}
+}
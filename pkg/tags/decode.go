@@ -0,0 +1,349 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// structTagKey is the key of the struct tags read by Unmarshal.
+const structTagKey = "tagrep"
+
+// fieldSpec is how a struct field is decoded from a tag.
+type fieldSpec struct {
+	// index is the index sequence of the field, see reflect.Value.FieldByIndex.
+	index    []int
+	name     string
+	typ      string
+	required bool
+	// def is the default value, nil when there is none.
+	def  []string
+	enum []string
+}
+
+// Unmarshal parses the tags from body and stores them in the struct pointed
+// to by v. Like encoding/json, each exported field is read from the tag named
+// in its struct tag, or its upper-cased name without one, and fields with the
+// name "-" are skipped:
+//
+//	type Tags struct {
+//		WantLGTM  string   `tagrep:"WANT_LGTM,required,enum=all|any"`
+//		Reviewers []string `tagrep:"REVIEWERS"`
+//		Draft     bool     `tagrep:"DRAFT,default=false"`
+//	}
+//
+// []string fields are array tags, bool fields are bool tags, and string and
+// integer fields use the last value of their tag. Pointers to these scalars
+// are left nil when the tag is missing. Fields of other types are skipped
+// unless they have a struct tag. The options after the name are:
+//
+//   - required: the tag must be present.
+//   - default=VALUE: the value when the tag is missing, with values of arrays
+//     separated by "|".
+//   - enum=A|B: the allowed values, for each value of arrays.
+//
+// Fields of missing tags without a default are left unchanged. The problems
+// of all invalid tags are returned together.
+//
+// Like encoding/json, the fields of embedded structs without a struct tag are
+// decoded as if they were fields of the outer struct, and nil embedded struct
+// pointers are allocated when one of their fields is decoded. Unlike
+// encoding/json, a tag decoded by more than one field is an error regardless
+// of how deeply the fields are embedded.
+func Unmarshal(ctx context.Context, body string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("failed to unmarshal tags: v must be a non-nil pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+
+	specs, err := structSpecs(rv.Type())
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal tags into %s: %w", rv.Type(), err)
+	}
+
+	// Only arrays are parsed as their type, so that the problems of the other
+	// values are returned together with the problems of the struct tags.
+	cfg := &Config{}
+	for _, s := range specs {
+		if s.typ == TypeArray {
+			cfg.ArrayTags = append(cfg.ArrayTags, s.name)
+		} else {
+			cfg.StringTags = append(cfg.StringTags, s.name)
+		}
+	}
+	parser := NewTagParser(ctx, cfg)
+	values, err := parser.Parse(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	var merr error
+	for _, s := range specs {
+		raw, line := s.def, 0
+		if tv, ok := values[s.name]; ok {
+			raw, line = tv.Raw, tv.Lines[len(tv.Lines)-1]
+		}
+		if raw == nil {
+			if s.required {
+				merr = errors.Join(merr, fmt.Errorf("missing required tag %s", s.name))
+			}
+			continue
+		}
+
+		if err := s.decode(fieldByIndex(rv, s.index), raw); err != nil {
+			if line == 0 {
+				merr = errors.Join(merr, fmt.Errorf("failed to decode default of tag %s: %w", s.name, err))
+			} else {
+				merr = errors.Join(merr, fmt.Errorf("failed to decode tag %s on line %d: %w", s.name, line, err))
+			}
+		}
+	}
+	return merr
+}
+
+// structSpecs returns how the fields of the struct type t are decoded, with
+// the fields of embedded structs as if they were fields of t.
+func structSpecs(t reflect.Type) ([]*fieldSpec, error) {
+	var specs []*fieldSpec
+	seen := make(map[string]string)
+	visiting := make(map[reflect.Type]bool)
+	var merr error
+
+	var walk func(t reflect.Type, index []int, prefix string)
+	walk = func(t reflect.Type, index []int, prefix string) {
+		// Embedded struct pointers can refer back to a struct being walked.
+		if visiting[t] {
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		for i := range t.NumField() {
+			f := t.Field(i)
+			tag, ok := f.Tag.Lookup(structTagKey)
+			if tag == "-" {
+				continue
+			}
+			fieldIndex := append(slices.Clone(index), i)
+			fieldName := prefix + f.Name
+
+			if f.Anonymous && !ok {
+				et := f.Type
+				// Pointers to unexported structs cannot be allocated.
+				if et.Kind() == reflect.Pointer && f.IsExported() {
+					et = et.Elem()
+				}
+				if et.Kind() == reflect.Struct {
+					walk(et, fieldIndex, fieldName+".")
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			s, err := parseFieldSpec(f, tag)
+			if err != nil {
+				// Without a struct tag, fields of other types are not meant to
+				// be decoded.
+				if !ok {
+					continue
+				}
+				merr = errors.Join(merr, fmt.Errorf("field %s: %w", fieldName, err))
+				continue
+			}
+			s.index = fieldIndex
+
+			if other, ok := seen[s.name]; ok {
+				merr = errors.Join(merr, fmt.Errorf("fields %s and %s both decode tag %s", other, fieldName, s.name))
+				continue
+			}
+			seen[s.name] = fieldName
+			specs = append(specs, s)
+		}
+	}
+	walk(t, nil, "")
+
+	if merr != nil {
+		return nil, merr
+	}
+	return specs, nil
+}
+
+// fieldByIndex returns the field of v with the index sequence, allocating nil
+// embedded struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// parseFieldSpec parses the struct tag of field f.
+func parseFieldSpec(f reflect.StructField, tag string) (*fieldSpec, error) {
+	name, opts, _ := strings.Cut(tag, ",")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = f.Name
+	}
+	s := &fieldSpec{name: strings.ToUpper(name)}
+	if !tagPattern.MatchString(s.name + "=") {
+		return nil, fmt.Errorf("invalid tag name %q", name)
+	}
+
+	switch t := f.Type; {
+	case t.Kind() == reflect.Slice && t.Elem() == reflect.TypeFor[string]():
+		s.typ = TypeArray
+	case indirect(t).Kind() == reflect.Bool:
+		s.typ = TypeBool
+	case indirect(t).Kind() == reflect.String, isInteger(indirect(t)):
+		s.typ = TypeString
+	default:
+		return nil, fmt.Errorf("unsupported type %s", f.Type)
+	}
+
+	if opts == "" {
+		return s, nil
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		k, v, hasValue := strings.Cut(strings.TrimSpace(opt), "=")
+		switch {
+		case k == "required" && !hasValue:
+			s.required = true
+		case k == "default" && hasValue:
+			s.def = []string{v}
+			if s.typ == TypeArray {
+				s.def = strings.Split(v, "|")
+			}
+		case k == "enum" && hasValue && v != "":
+			if s.typ == TypeBool {
+				return nil, fmt.Errorf("enum is not supported for bool tags")
+			}
+			s.enum = strings.Split(v, "|")
+		default:
+			return nil, fmt.Errorf("invalid option %q", opt)
+		}
+	}
+
+	if s.required && s.def != nil {
+		return nil, fmt.Errorf("required tags cannot have a default")
+	}
+	if s.def != nil {
+		if err := s.decode(reflect.New(f.Type).Elem(), s.def); err != nil {
+			return nil, fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// decode stores the raw values of the tag in field fv.
+func (s *fieldSpec) decode(fv reflect.Value, raw []string) error {
+	if s.typ == TypeArray {
+		var merr error
+		for _, v := range raw {
+			merr = errors.Join(merr, s.checkEnum(v))
+		}
+		if merr != nil {
+			return merr
+		}
+		fv.Set(reflect.ValueOf(slices.Clone(raw)).Convert(fv.Type()))
+		return nil
+	}
+
+	last := raw[len(raw)-1]
+	if err := s.checkEnum(last); err != nil {
+		return err
+	}
+
+	if fv.Kind() == reflect.Pointer {
+		p := reflect.New(fv.Type().Elem())
+		if err := decodeScalar(p.Elem(), last); err != nil {
+			return err
+		}
+		fv.Set(p)
+		return nil
+	}
+	return decodeScalar(fv, last)
+}
+
+// checkEnum returns an error if v is not one of the allowed values.
+func (s *fieldSpec) checkEnum(v string) error {
+	if len(s.enum) > 0 && !slices.Contains(s.enum, v) {
+		return fmt.Errorf("value %q is not one of %q", v, s.enum)
+	}
+	return nil
+}
+
+// decodeScalar parses v as the bool, string or integer type of fv and stores
+// it in fv.
+func decodeScalar(fv reflect.Value, v string) error {
+	switch k := fv.Kind(); {
+	case k == reflect.Bool:
+		b, err := parseBoolValue(v)
+		if err != nil {
+			return fmt.Errorf("failed to parse bool: %w", err)
+		}
+		fv.SetBool(b)
+	case k == reflect.String:
+		fv.SetString(v)
+	case fv.CanInt():
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("failed to parse %s as %s: %w", v, fv.Type(), err)
+		}
+		fv.SetInt(n)
+	case fv.CanUint():
+		n, err := strconv.ParseUint(strings.TrimSpace(v), 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("failed to parse %s as %s: %w", v, fv.Type(), err)
+		}
+		fv.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+// indirect returns the element type of pointer types.
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// isInteger reports whether t is a signed or unsigned integer type.
+func isInteger(t reflect.Type) bool {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
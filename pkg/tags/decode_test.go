@@ -0,0 +1,329 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+type names []string
+
+type decodeBase struct {
+	Team string `tagrep:"TEAM"`
+}
+
+type DecodeOwner struct {
+	Owner string `tagrep:"OWNER"`
+}
+
+type decodeCycle struct {
+	*DecodeCycle
+	Name string `tagrep:"NAME"`
+}
+
+type DecodeCycle struct {
+	*decodeCycle
+}
+
+func TestStructSpecs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		v        any
+		expNames []string
+		err      string
+	}{
+		{
+			name: "names",
+			v: struct {
+				WantLGTM  string `tagrep:"want_lgtm"`
+				Reviewers []string
+				Draft     bool   `tagrep:",default=false"`
+				Skipped   string `tagrep:"-"`
+				Other     map[string]string
+				private   string
+			}{},
+			expNames: []string{"WANT_LGTM", "REVIEWERS", "DRAFT"},
+		},
+		{
+			name: "embedded_structs",
+			v: struct {
+				decodeBase
+				*DecodeOwner
+				Name string `tagrep:"NAME"`
+			}{},
+			expNames: []string{"TEAM", "OWNER", "NAME"},
+		},
+		{
+			name: "embedded_pointer_cycle",
+			v: struct {
+				decodeCycle
+			}{},
+			expNames: []string{"NAME"},
+		},
+		{
+			name: "tagged_embedded_struct",
+			v: struct {
+				DecodeOwner `tagrep:"BASE"`
+			}{},
+			err: "field DecodeOwner: unsupported type tags.DecodeOwner",
+		},
+		{
+			name: "duplicate_names",
+			v: struct {
+				A string `tagrep:"OWNER"`
+				B string `tagrep:"owner"`
+			}{},
+			err: "fields A and B both decode tag OWNER",
+		},
+		{
+			name: "duplicate_embedded_name",
+			v: struct {
+				Team string `tagrep:"TEAM"`
+				decodeBase
+			}{},
+			err: "fields Team and decodeBase.Team both decode tag TEAM",
+		},
+		{
+			name: "invalid_name",
+			v: struct {
+				A string `tagrep:"OWNER-1"`
+			}{},
+			err: `field A: invalid tag name "OWNER-1"`,
+		},
+		{
+			name: "invalid_option",
+			v: struct {
+				A string `tagrep:"OWNER,requird"`
+			}{},
+			err: `field A: invalid option "requird"`,
+		},
+		{
+			name: "option_without_value",
+			v: struct {
+				A string `tagrep:"OWNER,enum="`
+			}{},
+			err: `field A: invalid option "enum="`,
+		},
+		{
+			name: "required_with_value",
+			v: struct {
+				A string `tagrep:"OWNER,required=true"`
+			}{},
+			err: `field A: invalid option "required=true"`,
+		},
+		{
+			name: "required_with_default",
+			v: struct {
+				A string `tagrep:"OWNER,required,default=alice"`
+			}{},
+			err: "field A: required tags cannot have a default",
+		},
+		{
+			name: "invalid_default",
+			v: struct {
+				A int `tagrep:"COUNT,default=many"`
+			}{},
+			err: "field A: invalid default: failed to parse many as int",
+		},
+		{
+			name: "default_not_in_enum",
+			v: struct {
+				A string `tagrep:"WANT_LGTM,default=some,enum=all|any"`
+			}{},
+			err: `field A: invalid default: value "some" is not one of ["all" "any"]`,
+		},
+		{
+			name: "bool_enum",
+			v: struct {
+				A bool `tagrep:"DRAFT,enum=true"`
+			}{},
+			err: "field A: enum is not supported for bool tags",
+		},
+		{
+			name: "unsupported_type",
+			v: struct {
+				A map[string]string `tagrep:"LABELS"`
+				B []int             `tagrep:"COUNTS"`
+				C float64           `tagrep:"RATIO"`
+			}{},
+			err: "field A: unsupported type map[string]string\nfield B: unsupported type []int\nfield C: unsupported type float64",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			specs, err := structSpecs(reflect.TypeOf(tc.v))
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+
+			var got []string
+			for _, s := range specs {
+				got = append(got, s.name)
+			}
+			if diff := cmp.Diff(got, tc.expNames); diff != "" {
+				t.Errorf("tag names not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+type decodeTarget struct {
+	decodeBase
+	*DecodeOwner
+
+	Names    names  `tagrep:"NAMES"`
+	Count    int8   `tagrep:"COUNT"`
+	Size     uint8  `tagrep:"SIZE"`
+	Priority *int   `tagrep:"PRIORITY"`
+	Draft    *bool  `tagrep:"DRAFT"`
+	Label    string `tagrep:"LABEL,default=none"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	priority := 2
+
+	cases := []struct {
+		name string
+		body string
+		exp  *decodeTarget
+		err  string
+	}{
+		{
+			name: "values",
+			body: "TEAM=widgets\nOWNER=alice\nNAMES=bob\nNAMES=carol\nCOUNT=-128\nSIZE=255\nPRIORITY=2",
+			exp: &decodeTarget{
+				decodeBase:  decodeBase{Team: "widgets"},
+				DecodeOwner: &DecodeOwner{Owner: "alice"},
+				Names:       names{"bob", "carol"},
+				Count:       -128,
+				Size:        255,
+				Priority:    &priority,
+				Label:       "none",
+			},
+		},
+		{
+			name: "missing_tags",
+			body: "A description.",
+			exp: &decodeTarget{
+				Label: "none",
+			},
+		},
+		{
+			name: "int_overflow",
+			body: "COUNT=128",
+			err:  "failed to decode tag COUNT on line 1: failed to parse 128 as int8",
+		},
+		{
+			name: "uint_overflow",
+			body: "SIZE=256",
+			err:  "failed to decode tag SIZE on line 1: failed to parse 256 as uint8",
+		},
+		{
+			name: "negative_uint",
+			body: "SIZE=-1",
+			err:  "failed to decode tag SIZE on line 1: failed to parse -1 as uint8",
+		},
+		{
+			name: "invalid_values",
+			body: "COUNT=many\nDRAFT=maybe",
+			err:  "failed to decode tag COUNT on line 1: failed to parse many as int8",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got decodeTarget
+			err := Unmarshal(ctx, tc.body, &got)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(&got, tc.exp, cmp.AllowUnexported(decodeTarget{})); diff != "" {
+				t.Errorf("decoded tags not as expected; (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_InvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(t.Context(), logging.TestLogger(t))
+
+	cases := []struct {
+		name string
+		v    any
+		err  string
+	}{
+		{
+			name: "nil",
+			v:    nil,
+			err:  "v must be a non-nil pointer to a struct, got <nil>",
+		},
+		{
+			name: "not_a_pointer",
+			v:    decodeTarget{},
+			err:  "v must be a non-nil pointer to a struct, got tags.decodeTarget",
+		},
+		{
+			name: "nil_pointer",
+			v:    (*decodeTarget)(nil),
+			err:  "v must be a non-nil pointer to a struct, got *tags.decodeTarget",
+		},
+		{
+			name: "not_a_struct",
+			v:    new(string),
+			err:  "v must be a non-nil pointer to a struct, got *string",
+		},
+		{
+			name: "invalid_struct_tags",
+			v: &struct {
+				A string `tagrep:"OWNER,requird"`
+			}{},
+			err: `failed to unmarshal tags into struct { A string "tagrep:\"OWNER,requird\"" }: field A: invalid option "requird"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Unmarshal(ctx, "OWNER=alice", tc.v)
+			if diff := testutil.DiffErrString(err, tc.err); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
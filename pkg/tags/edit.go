@@ -0,0 +1,246 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ManagedBlockBegin and ManagedBlockEnd delimit the section of a body that
+	// tagrep adds tags to. They are HTML comments, so they are hidden when the
+	// body is rendered as Markdown.
+	ManagedBlockBegin = "<!-- tagrep:begin -->"
+	ManagedBlockEnd   = "<!-- tagrep:end -->"
+)
+
+// tagNamePattern matches a valid tag name.
+var tagNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Tag is a tag with its values, each written on its own line.
+type Tag struct {
+	Name   string
+	Values []string
+}
+
+// ParseTagArgs parses NAME=VALUE arguments into tags. Repeated names become a
+// single tag with multiple values, in the order of the arguments.
+func ParseTagArgs(args []string) ([]*Tag, error) {
+	var ts []*Tag
+	index := make(map[string]*Tag)
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q, must be of the form NAME=VALUE", arg)
+		}
+		if err := ValidateTagName(name); err != nil {
+			return nil, err
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid value for tag %s, must not contain line breaks", name)
+		}
+
+		key := strings.ToUpper(name)
+		t, ok := index[key]
+		if !ok {
+			t = &Tag{Name: name}
+			index[key] = t
+			ts = append(ts, t)
+		}
+		t.Values = append(t.Values, value)
+	}
+	return ts, nil
+}
+
+// ValidateTagName returns an error if name is not a valid tag name.
+func ValidateTagName(name string) error {
+	if !tagNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid tag name %q, must only contain letters, digits and underscores", name)
+	}
+	return nil
+}
+
+// SetTags returns body with the tags set. The first line of an existing tag is
+// replaced by the new values and its other lines are removed, so the rest of
+// the body is left untouched. Tags not in the body are appended to the managed
+// block, which is added to the end of the body if needed. Tag names are matched
+// case-insensitively.
+func SetTags(body string, ts []*Tag) string {
+	pending := make(map[string]*Tag, len(ts))
+	for _, t := range ts {
+		pending[strings.ToUpper(t.Name)] = t
+	}
+	written := make(map[string]struct{}, len(ts))
+
+	lines := strings.Split(body, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		name, ok := tagLineName(line)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		key := strings.ToUpper(name)
+		if _, ok := written[key]; ok {
+			continue
+		}
+		t, ok := pending[key]
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		cr := ""
+		if strings.HasSuffix(line, "\r") {
+			cr = "\r"
+		}
+		for _, v := range t.Values {
+			out = append(out, name+"="+v+cr)
+		}
+		written[key] = struct{}{}
+	}
+
+	var appended []string
+	for _, t := range ts {
+		if _, ok := written[strings.ToUpper(t.Name)]; ok {
+			continue
+		}
+		for _, v := range t.Values {
+			appended = append(appended, t.Name+"="+v)
+		}
+	}
+	if len(appended) == 0 {
+		return strings.Join(out, "\n")
+	}
+
+	return appendToManagedBlock(out, appended, lineBreak(body))
+}
+
+// UnsetTags returns body without the lines of the named tags. The managed block
+// is removed when it has no lines left. Tag names are matched
+// case-insensitively.
+func UnsetTags(body string, names []string) string {
+	remove := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		remove[strings.ToUpper(n)] = struct{}{}
+	}
+
+	lines := strings.Split(body, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if name, ok := tagLineName(line); ok {
+			if _, ok := remove[strings.ToUpper(name)]; ok {
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+
+	begin, end := managedBlock(out)
+	if begin < 0 {
+		return strings.Join(out, "\n")
+	}
+	for _, line := range out[begin+1 : end] {
+		if strings.TrimSpace(line) != "" {
+			return strings.Join(out, "\n")
+		}
+	}
+
+	// Remove the empty block together with the blank lines separating it from
+	// the rest of the body.
+	before := strings.TrimRight(strings.Join(out[:begin], "\n"), "\r\n")
+	after := strings.TrimLeft(strings.Join(out[end+1:], "\n"), "\r\n")
+	switch {
+	case before == "":
+		return after
+	case after == "":
+		if strings.HasSuffix(body, "\n") {
+			return before + lineBreak(body)
+		}
+		return before
+	default:
+		return before + lineBreak(body) + lineBreak(body) + after
+	}
+}
+
+// appendToManagedBlock returns the lines with the tag lines appended to the
+// end of the managed block, adding the block to the end if there is none.
+func appendToManagedBlock(lines, tagLines []string, newline string) string {
+	cr := strings.TrimSuffix(newline, "\n")
+
+	if begin, end := managedBlock(lines); begin >= 0 {
+		out := make([]string, 0, len(lines)+len(tagLines))
+		out = append(out, lines[:end]...)
+		for _, l := range tagLines {
+			out = append(out, l+cr)
+		}
+		out = append(out, lines[end:]...)
+		return strings.Join(out, "\n")
+	}
+
+	body := strings.Join(lines, "\n")
+	block := ManagedBlockBegin + newline + strings.Join(tagLines, newline) + newline + ManagedBlockEnd
+
+	// Keep the body ending with a line break if it did.
+	end := ""
+	if strings.HasSuffix(body, "\n") {
+		end = newline
+	}
+
+	trimmed := strings.TrimRight(body, "\r\n")
+	if trimmed == "" {
+		return block + end
+	}
+	return trimmed + newline + newline + block + end
+}
+
+// managedBlock returns the indexes of the lines with the begin and end markers
+// of the managed block, or -1 if there is no complete block.
+func managedBlock(lines []string) (int, int) {
+	begin := -1
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case ManagedBlockBegin:
+			if begin < 0 {
+				begin = i
+			}
+		case ManagedBlockEnd:
+			if begin >= 0 {
+				return begin, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// lineBreak returns the line break used by body.
+func lineBreak(body string) string {
+	if strings.Contains(body, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// tagLineName returns the name of the tag declared on the line, if any.
+func tagLineName(line string) (string, bool) {
+	m := tagPattern.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+	if m == nil || m[1] == "" {
+		return "", false
+	}
+	return m[1], true
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/abcxyz/tagrep/pkg/tags"
+)
+
+func ExampleTagParser_Parse() {
+	ctx := context.Background()
+
+	parser := tags.NewTagParser(ctx, &tags.Config{
+		ArrayTags:  []string{"REVIEWERS"},
+		StringTags: []string{"WANT_LGTM"},
+		BoolTags:   []string{"DRAFT"},
+	})
+
+	body := `Adds a cache for widgets.
+
+WANT_LGTM=all
+REVIEWERS=bob
+Reviewers=carol
+DRAFT=no
+`
+	result, err := parser.Parse(ctx, body)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	names := make([]string, 0, len(result))
+	for name := range result {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tv := result[name]
+		fmt.Printf("%s (%s) = %#v from lines %v\n", name, tv.Type, tv.Value, tv.Lines)
+	}
+
+	// Output:
+	// DRAFT (bool) = false from lines [6]
+	// REVIEWERS (array) = []string{"bob", "carol"} from lines [4 5]
+	// WANT_LGTM (string) = "all" from lines [3]
+}
+
+func ExampleTagParser_Parse_errors() {
+	ctx := context.Background()
+
+	parser := tags.NewTagParser(ctx, &tags.Config{
+		BoolTags: []string{"DRAFT", "URGENT"},
+	})
+
+	_, err := parser.Parse(ctx, "DRAFT=maybe\nURGENT=soon\n")
+	fmt.Println(err)
+
+	// Output:
+	// failed to parse tag DRAFT on line 1: failed to parse bool: failed to parse maybe as bool: strconv.ParseBool: parsing "maybe": invalid syntax
+	// failed to parse tag URGENT on line 2: failed to parse bool: failed to parse soon as bool: strconv.ParseBool: parsing "soon": invalid syntax
+}
+
+func ExampleUnmarshal() {
+	ctx := context.Background()
+
+	type Tags struct {
+		WantLGTM  string   `tagrep:"WANT_LGTM,required,enum=all|any"`
+		Reviewers []string `tagrep:"REVIEWERS"`
+		Draft     bool     `tagrep:"DRAFT,default=false"`
+		Retries   *int     `tagrep:"RETRIES"`
+	}
+
+	body := `Adds a cache for widgets.
+
+WANT_LGTM=any
+REVIEWERS=bob
+REVIEWERS=carol
+RETRIES=3
+`
+	var t Tags
+	if err := tags.Unmarshal(ctx, body, &t); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s %q %t %d\n", t.WantLGTM, t.Reviewers, t.Draft, *t.Retries)
+
+	// Output:
+	// any ["bob" "carol"] false 3
+}
+
+func ExampleUnmarshal_errors() {
+	ctx := context.Background()
+
+	type Tags struct {
+		WantLGTM string `tagrep:"WANT_LGTM,required,enum=all|any"`
+		Owner    string `tagrep:"OWNER,required"`
+		Draft    bool   `tagrep:"DRAFT"`
+	}
+
+	var t Tags
+	err := tags.Unmarshal(ctx, "WANT_LGTM=some\nDRAFT=maybe\n", &t)
+	fmt.Println(err)
+
+	// Output:
+	// failed to decode tag WANT_LGTM on line 1: value "some" is not one of ["all" "any"]
+	// missing required tag OWNER
+	// failed to decode tag DRAFT on line 2: failed to parse bool: failed to parse maybe as bool: strconv.ParseBool: parsing "maybe": invalid syntax
+}
+
+func ExampleTagParser_ParseLinked() {
+	ctx := context.Background()
+
+	parser := tags.NewTagParser(ctx, &tags.Config{
+		StringTags: []string{"WANT_LGTM", "TEAM"},
+	})
+
+	body := "Fixes #12.\n\nWANT_LGTM=all\n"
+	linked := []*tags.LinkedBody{
+		{Source: "issue:https://github.com/abcxyz/tagrep/issues/12", Body: "WANT_LGTM=any\nTEAM=platform\n"},
+	}
+
+	result, err := parser.ParseLinked(ctx, "request", body, linked, tags.PrecedenceRequest)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, name := range []string{"TEAM", "WANT_LGTM"} {
+		tv := result[name]
+		fmt.Printf("%s=%s from %s line %v\n", name, tv.Value, tv.Source, tv.Lines)
+	}
+
+	// Output:
+	// TEAM=platform from issue:https://github.com/abcxyz/tagrep/issues/12 line [2]
+	// WANT_LGTM=all from request line [3]
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abcxyz/pkg/logging"
+)
+
+const (
+	// PrecedenceRequest keeps the tags of the request over the tags of linked
+	// issues and tickets.
+	PrecedenceRequest = "request"
+	// PrecedenceLinked replaces the tags of the request with the tags of linked
+	// issues and tickets.
+	PrecedenceLinked = "linked"
+)
+
+// LinkedBody is the body of an issue or ticket linked from a request.
+type LinkedBody struct {
+	// Source identifies the body, e.g. issue:<url> or ticket:<key>.
+	Source string
+	Body   string
+}
+
+// ParseLinked parses the tags from body like Parse, and adds the tags of the
+// linked bodies that body does not have. The Source of each tag is source for
+// the tags of body and the Source of the linked body otherwise, and its Lines
+// are lines of that body. Earlier linked bodies take precedence over later
+// ones. With PrecedenceLinked, the tags of the linked bodies also replace the
+// tags of body. Required tags may be found in any of the bodies.
+func (p *TagParser) ParseLinked(ctx context.Context, source, body string, linked []*LinkedBody, precedence string) (map[string]*TagValue, error) {
+	logger := logging.FromContext(ctx)
+
+	values, err := p.parse(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	for _, tv := range values {
+		tv.Source = source
+	}
+
+	for _, l := range linked {
+		linkedValues, err := p.parse(ctx, l.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tags of %s: %w", l.Source, err)
+		}
+
+		logger.DebugContext(ctx, "parsed tags from linked body",
+			"source", l.Source,
+			"tags", len(linkedValues))
+
+		for k, tv := range linkedValues {
+			if current, ok := values[k]; ok && (precedence != PrecedenceLinked || current.Source != source) {
+				continue
+			}
+			tv.Source = l.Source
+			values[k] = tv
+		}
+	}
+	if err := checkRequired(p.cfg.RequiredTags, values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Values returns the value of each tag, as returned by ParseTagValues.
+func Values(ts map[string]*TagValue) map[string]any {
+	values := make(map[string]any, len(ts))
+	for k, tv := range ts {
+		values[k] = tv.Value
+	}
+	return values
+}
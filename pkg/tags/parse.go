@@ -0,0 +1,107 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abcxyz/pkg/sets"
+)
+
+// TagValue is the typed value of a tag parsed from a body, with the lines it
+// was read from.
+type TagValue struct {
+	// Type is the type the tag is parsed as, one of TypeArray, TypeString or
+	// TypeBool.
+	Type string
+	// Value is a []string for TypeArray, a bool for TypeBool and a string for
+	// TypeString. Tags that are not arrays use their last value.
+	Value any
+	// Raw are the values as written in the body, in order.
+	Raw []string
+	// Lines are the 1-based lines of the body the Raw values were read from.
+	Lines []int
+	// Source is where the tag was read from when parsed with ParseLinked, e.g.
+	// request, issue:<url> or ticket:<key>.
+	Source string
+}
+
+// Parse parses the tags from v and returns the typed value of each tag keyed
+// by the upper-cased tag name. Names that only differ in case are the same
+// tag, with the values in the order they appear. Like ParseTagValues, only
+// the configured tags are returned unless OutputAll is set. The problems of
+// all invalid and missing required tags are returned together.
+func (p *TagParser) Parse(ctx context.Context, v string) (map[string]*TagValue, error) {
+	result, err := p.parse(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRequired(p.cfg.RequiredTags, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parse parses the tags from v like Parse, without checking that the required
+// tags are present.
+func (p *TagParser) parse(ctx context.Context, v string) (map[string]*TagValue, error) {
+	targetTags := sets.Union(p.cfg.ArrayTags, p.cfg.StringTags, p.cfg.BoolTags)
+
+	result := make(map[string]*TagValue)
+	var keys []string
+	for i, line := range strings.Split(v, "\n") {
+		m := tagPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key := strings.ToUpper(m[1])
+		if !p.cfg.OutputAll && !slices.Contains(targetTags, key) {
+			continue
+		}
+
+		tv, ok := result[key]
+		if !ok {
+			tv = &TagValue{Type: p.tagType(key)}
+			result[key] = tv
+			keys = append(keys, key)
+		}
+		tv.Raw = append(tv.Raw, m[2])
+		tv.Lines = append(tv.Lines, i+1)
+	}
+
+	var merr error
+	for _, key := range keys {
+		tv := result[key]
+		value, err := p.processTagValues(ctx, key, tv.Raw)
+		if err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to parse tag %s on line %d: %w", key, tv.Lines[len(tv.Lines)-1], err))
+			continue
+		}
+		tv.Value = value
+	}
+	if merr != nil {
+		return nil, merr
+	}
+	return result, nil
+}
+
+// tagType returns the type the tag key is parsed as.
+func (p *TagParser) tagType(key string) string {
+	return p.cfg.tagType(key)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/abcxyz/pkg/sets"
+)
+
+// JSONSchemaDialect is the JSON Schema version of the schemas returned by
+// JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema needed to describe the JSON output of
+// the tags.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false or the *Schema of properties not in
+	// Properties.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+
+	Items *Schema  `json:"items,omitempty"`
+	Enum  []string `json:"enum,omitempty"`
+}
+
+// SchemaOptions are the properties of the schema that are not tags.
+type SchemaOptions struct {
+	// Extra are properties output next to the tags, such as the sources of
+	// the tags.
+	Extra map[string]*Schema
+}
+
+// JSONSchema returns the JSON Schema of the object FormatTags outputs with
+// the json format. Properties are typed by -array-tags, -string-tags and
+// -bool-tags, and other tags are only allowed, as strings, with OutputAll.
+// Required tags and enums are the ones the parser enforces.
+func (p *TagParser) JSONSchema(opts *SchemaOptions) (*Schema, error) {
+	if opts == nil {
+		opts = &SchemaOptions{}
+	}
+
+	if err := p.cfg.validateConstraints(); err != nil {
+		return nil, err
+	}
+
+	targetTags := sets.Union(p.cfg.ArrayTags, p.cfg.StringTags, p.cfg.BoolTags)
+	sort.Strings(targetTags)
+
+	s := &Schema{
+		Schema:     JSONSchemaDialect,
+		Title:      "tagrep tags",
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(targetTags)+len(opts.Extra)),
+	}
+	if p.cfg.OutputAll {
+		s.AdditionalProperties = &Schema{Type: "string"}
+	} else {
+		s.AdditionalProperties = false
+	}
+
+	for _, key := range targetTags {
+		switch p.tagType(key) {
+		case TypeArray:
+			s.Properties[key] = &Schema{Type: "array", Items: &Schema{Type: "string"}}
+		case TypeBool:
+			s.Properties[key] = &Schema{Type: "boolean"}
+		default:
+			s.Properties[key] = &Schema{Type: "string"}
+		}
+	}
+
+	required := slices.Clone(p.cfg.RequiredTags)
+	sort.Strings(required)
+	s.Required = slices.Compact(required)
+
+	for key, values := range p.cfg.Enums {
+		prop, ok := s.Properties[key]
+		if !ok {
+			prop = &Schema{Type: "string"}
+			s.Properties[key] = prop
+		}
+		if prop.Items != nil {
+			prop = prop.Items
+		}
+		prop.Enum = slices.Clone(values)
+	}
+
+	var merr error
+	for key, extra := range opts.Extra {
+		if _, ok := s.Properties[key]; ok {
+			merr = errors.Join(merr, fmt.Errorf("property %s is already a tag", key))
+			continue
+		}
+		s.Properties[key] = extra
+	}
+
+	if merr != nil {
+		return nil, merr
+	}
+	return s, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/abcxyz/pkg/sets"
+)
+
+const (
+	TypeArray  = "array"
+	TypeString = "string"
+	TypeBool   = "bool"
+)
+
+// TagSummary describes a tag recognized in a body.
+type TagSummary struct {
+	Name string
+	// Type is the type the tag is parsed as, one of TypeArray, TypeString or
+	// TypeBool. Tags only output because of -output-all are strings.
+	Type string
+	// Values are the values used for the tag, formatted as strings.
+	Values []string
+	// Line is the 1-based line of the value with the problem, or of the last
+	// value of the tag in the body. It is 0 for missing required tags.
+	Line int
+	// Err is the problem with the tag, if any.
+	Err error
+	// Warning is a problem that does not make the tag invalid, e.g. repeated
+	// values of a tag that is not an array.
+	Warning error
+}
+
+// SummarizeTags returns the tags in v that ParseTagValues would output, sorted
+// by name. Unlike ParseTagValues it does not stop at invalid tags, the problem
+// of each tag is reported in its summary instead. Missing required tags are
+// reported as tags without values.
+func (p *TagParser) SummarizeTags(ctx context.Context, v string) []*TagSummary {
+	return p.SummarizeLinkedTags(ctx, v, nil)
+}
+
+// SummarizeLinkedTags summarizes the tags in v like SummarizeTags. Like
+// ParseLinked, required tags found in the linked bodies are not missing.
+func (p *TagParser) SummarizeLinkedTags(ctx context.Context, v string, linked []*LinkedBody) []*TagSummary {
+	// Merge the values of names that only differ in case, in a stable order.
+	ts := parseTags(ctx, v)
+	rawKeys := maps.Keys(ts)
+	sort.Strings(rawKeys)
+	values := make(map[string][]string, len(ts))
+	for _, k := range rawKeys {
+		key := strings.ToUpper(k)
+		values[key] = append(values[key], ts[k]...)
+	}
+
+	// The lines of each value, in the same order as the values.
+	rawLines := make(map[string][]int, len(ts))
+	for i, line := range strings.Split(v, "\n") {
+		if name, ok := tagLineName(line); ok {
+			rawLines[name] = append(rawLines[name], i+1)
+		}
+	}
+	lines := make(map[string][]int, len(values))
+	for _, k := range rawKeys {
+		key := strings.ToUpper(k)
+		lines[key] = append(lines[key], rawLines[k]...)
+	}
+
+	targetTags := sets.Union(p.cfg.ArrayTags, p.cfg.StringTags, p.cfg.BoolTags)
+	keys := maps.Keys(values)
+	sort.Strings(keys)
+
+	summaries := make([]*TagSummary, 0, len(keys))
+	for _, key := range keys {
+		if !p.cfg.OutputAll && !slices.Contains(targetTags, key) {
+			continue
+		}
+		vs := values[key]
+		last := vs[len(vs)-1]
+
+		s := &TagSummary{Name: key, Line: lineOf(lines[key], len(vs)-1)}
+		switch p.tagType(key) {
+		case TypeArray:
+			s.Type, s.Values = TypeArray, vs
+			for i, v := range vs {
+				if err := p.checkEnum(key, v); err != nil {
+					s.Err, s.Line = err, lineOf(lines[key], i)
+					break
+				}
+			}
+		case TypeBool:
+			s.Type, s.Values = TypeBool, []string{last}
+			if b, err := parseBoolValue(last); err != nil {
+				s.Err = err
+			} else {
+				s.Values = []string{strconv.FormatBool(b)}
+			}
+		default:
+			s.Type, s.Values = TypeString, []string{last}
+			s.Err = p.checkEnum(key, last)
+		}
+
+		// Parsing only logs repeated values, so they do not make the tag invalid.
+		if s.Type != TypeArray && len(vs) > 1 {
+			s.Warning = fmt.Errorf("found %d values for a tag that is not in -array-tags, using the last one", len(vs))
+		}
+		summaries = append(summaries, s)
+	}
+
+	for _, l := range linked {
+		for k, vs := range parseTags(ctx, l.Body) {
+			values[strings.ToUpper(k)] = vs
+		}
+	}
+	for _, key := range p.cfg.RequiredTags {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		summaries = append(summaries, &TagSummary{
+			Name:   key,
+			Type:   p.tagType(key),
+			Values: []string{},
+			Err:    errors.New("missing required tag"),
+		})
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+// lineOf returns the line of the i-th value, or 0 if it is unknown.
+func lineOf(lines []int, i int) int {
+	if i < 0 || i >= len(lines) {
+		return 0
+	}
+	return lines[i]
+}
@@ -60,7 +60,19 @@ func NewTagParser(ctx context.Context, cfg *Config) TagParser {
 	return TagParser{cfg}
 }
 
+// ParseTags parses the tags from v and returns them in the configured format.
 func (p *TagParser) ParseTags(ctx context.Context, v string) (string, error) {
+	tagStrs, err := p.ParseTagValues(ctx, v)
+	if err != nil {
+		return "", err
+	}
+	return p.FormatTags(ctx, tagStrs)
+}
+
+// ParseTagValues parses the tags from v and returns the processed value of
+// each tag keyed by the upper-cased tag name. Values are a []string for
+// -array-tags, a bool for -bool-tags and a string otherwise.
+func (p *TagParser) ParseTagValues(ctx context.Context, v string) (map[string]any, error) {
 	tagStrs := make(map[string]any)
 	ts := parseTags(ctx, v)
 	targetTags := sets.Union(p.cfg.ArrayTags, p.cfg.StringTags, p.cfg.BoolTags)
@@ -71,16 +83,41 @@ func (p *TagParser) ParseTags(ctx context.Context, v string) (string, error) {
 			continue
 		}
 		if tagStrs[key], err = p.processTagValues(ctx, key, t); err != nil {
-			return "", fmt.Errorf("failed to process duplicate keys: %w", err)
+			return nil, fmt.Errorf("failed to process duplicate keys: %w", err)
 		}
 	}
-	r, err := p.format(ctx, tagStrs)
+	if err := checkRequired(p.cfg.RequiredTags, tagStrs); err != nil {
+		return nil, err
+	}
+	return tagStrs, nil
+}
+
+// FormatTags returns the processed tag values, as returned by
+// ParseTagValues, in the configured format.
+func (p *TagParser) FormatTags(ctx context.Context, ts map[string]any) (string, error) {
+	r, err := p.format(ctx, ts)
 	if err != nil {
 		return "", fmt.Errorf("failed to format tags: %w", err)
 	}
 	return r, nil
 }
 
+// Lookup returns the raw values of the tag key in v, regardless of the
+// configured tag filters. Keys are matched case-insensitively.
+func Lookup(ctx context.Context, v, key string) []string {
+	ts := parseTags(ctx, v)
+	keys := maps.Keys(ts)
+	sort.Strings(keys)
+
+	var values []string
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			values = append(values, ts[k]...)
+		}
+	}
+	return values
+}
+
 func (p *TagParser) format(ctx context.Context, ts map[string]any) (r string, merr error) {
 	switch p.cfg.Format {
 	case FormatRaw:
@@ -122,6 +159,11 @@ func (p *TagParser) format(ctx context.Context, ts map[string]any) (r string, me
 // processTagValues either returns an array or a string value depending on the duplicate key strategy.
 func (p *TagParser) processTagValues(ctx context.Context, key string, ts []string) (any, error) {
 	if slices.Contains(p.cfg.ArrayTags, key) {
+		for _, v := range ts {
+			if err := p.checkEnum(key, v); err != nil {
+				return nil, err
+			}
+		}
 		return ts, nil
 	}
 	if len(ts) > 1 {
@@ -133,6 +175,9 @@ func (p *TagParser) processTagValues(ctx context.Context, key string, ts []strin
 	}
 	last := ts[len(ts)-1]
 	if slices.Contains(p.cfg.StringTags, key) {
+		if err := p.checkEnum(key, last); err != nil {
+			return nil, err
+		}
 		return last, nil
 	}
 	if slices.Contains(p.cfg.BoolTags, key) {
@@ -143,9 +188,32 @@ func (p *TagParser) processTagValues(ctx context.Context, key string, ts []strin
 		return b, nil
 	}
 
+	if err := p.checkEnum(key, last); err != nil {
+		return nil, err
+	}
 	return last, nil
 }
 
+// checkEnum returns an error if v is not one of the allowed values of the tag
+// key.
+func (p *TagParser) checkEnum(key, v string) error {
+	if allowed := p.cfg.Enums[key]; len(allowed) > 0 && !slices.Contains(allowed, v) {
+		return fmt.Errorf("value %q is not one of %q", v, allowed)
+	}
+	return nil
+}
+
+// checkRequired returns an error for every required tag that is not in values.
+func checkRequired[V any](required []string, values map[string]V) error {
+	var merr error
+	for _, key := range required {
+		if _, ok := values[key]; !ok {
+			merr = errors.Join(merr, fmt.Errorf("missing required tag %s", key))
+		}
+	}
+	return merr
+}
+
 func parseBoolValue(v string) (bool, error) {
 	vtl := strings.ToLower(strings.TrimSpace(v))
 	// Handle cases not handled in ParseBool, which accepts: